@@ -0,0 +1,158 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/parse"
+	"gopkg.in/yaml.v3"
+)
+
+// languageAliasesFlag collects the values of the -language-alias flag, which can be repeated
+type languageAliasesFlag map[string]string
+
+func (f languageAliasesFlag) String() string {
+	aliases := make([]string, 0, len(f))
+	for alias, lang := range f {
+		aliases = append(aliases, alias+"="+lang)
+	}
+	slices.Sort(aliases)
+	return strings.Join(aliases, " ")
+}
+
+func (f languageAliasesFlag) Set(value string) error {
+	alias, lang, found := strings.Cut(value, "=")
+	if !found || alias == "" || lang == "" {
+		return fmt.Errorf("the language alias %q is not written as alias=language", value)
+	}
+	f[alias] = lang
+	return nil
+}
+
+func languageAliasFlag(flags *flag.FlagSet) languageAliasesFlag {
+	aliases := languageAliasesFlag{}
+	flags.Var(aliases, "language-alias", "Uses the files of a language for another one, written as alias=language, like english=en. Can be repeated")
+	return aliases
+}
+
+// configKeys are the names of the flags of every command, the keys a config file can have
+var configKeys = []string{
+	"default-language", "messages", "icu", "type", "language-alias", "out-file", "out-package", "top-interface-name",
+	"public-non-named-interfaces", "check", "lang", "po-dir", "xliff-dir", "languages", "arb-dir", "arb-prefix",
+	"out-dir", "csv-file",
+}
+
+// parseFlags parses the arguments and gives the flags that were not set the values of the config file, which is
+// the one given with -config or the first i18n.yaml, i18n.yml or i18n.json found in the messages directory or the
+// current one. The keys of the config are the names of the flags, the ones the command does not have are ignored
+// and the ones no command has are reported
+func parseFlags(flags *flag.FlagSet, args []string) {
+	configFile := flags.String("config", "", "Specifies the config file, by default i18n.yaml, i18n.yml or i18n.json in the messages directory or the current one")
+	flags.Parse(args)
+
+	set := map[string]bool{}
+	flags.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	path := *configFile
+	if path == "" {
+		path = findConfigFile(flags.Lookup("messages").Value.String())
+		if path == "" {
+			return
+		}
+	}
+	config, err := readConfig(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not read the config file %s: %v\n", path, err)
+		os.Exit(1)
+	}
+
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if key == "config" {
+			continue
+		}
+		if !slices.Contains(configKeys, key) {
+			fmt.Fprintf(os.Stderr, "Unknown key %s in the config file %s, it is not a flag of any command\n", key, path)
+			continue
+		}
+		if set[key] || flags.Lookup(key) == nil {
+			continue
+		}
+		for _, value := range config[key] {
+			if err := flags.Set(key, value); err != nil {
+				fmt.Fprintf(os.Stderr, "Invalid value for %s in the config file %s: %v\n", key, path, err)
+				os.Exit(1)
+			}
+		}
+	}
+}
+
+// findConfigFile returns the path of the config file in the messages directory or the current one, or an empty
+// string if there is none
+func findConfigFile(messagesDir string) string {
+	dirs := []string{"."}
+	if messagesDir != "" {
+		dirs = []string{messagesDir, "."}
+	}
+	for _, dir := range dirs {
+		for _, name := range parse.ConfigFiles {
+			path := filepath.Join(dir, name)
+			if _, err := os.Stat(path); err == nil {
+				return path
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				fmt.Fprintf(os.Stderr, "Could not read the config file %s: %v\n", path, err)
+				os.Exit(1)
+			}
+		}
+	}
+	return ""
+}
+
+// readConfig reads the values of each key of the config file. Lists give one value for each item and mappings one
+// key=value for each entry, which sets a repeatable flag like -type or -language-alias once for each. Json is read
+// as yaml
+func readConfig(path string) (map[string][]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var document map[string]yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+	config := make(map[string][]string, len(document))
+	for key, node := range document {
+		switch node.Kind {
+		case yaml.ScalarNode:
+			config[key] = []string{node.Value}
+		case yaml.SequenceNode:
+			for _, item := range node.Content {
+				if item.Kind != yaml.ScalarNode {
+					return nil, fmt.Errorf("the items of %s must be scalars (line %d)", key, item.Line)
+				}
+				config[key] = append(config[key], item.Value)
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				name, value := node.Content[i], node.Content[i+1]
+				if value.Kind != yaml.ScalarNode {
+					return nil, fmt.Errorf("the values of %s must be scalars (line %d)", key, value.Line)
+				}
+				config[key] = append(config[key], name.Value+"="+value.Value)
+			}
+		default:
+			return nil, fmt.Errorf("the value of %s is not valid (line %d)", key, node.Line)
+		}
+	}
+	return config, nil
+}
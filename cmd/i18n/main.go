@@ -5,32 +5,291 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/MrNemo64/go-n-i18n/internal/cli"
 )
 
+const version = "v0.0.3"
+
+var commands = map[string]func(args []string){
+	"generate":       generate,
+	"check":          check,
+	"lint":           messagesCommand("lint", cli.Lint),
+	"stats":          messagesCommand("stats", cli.Stats),
+	"fmt":            messagesCommand("fmt", cli.Fmt),
+	"add-lang":       addLang,
+	"export-po":      exportPo,
+	"import-po":      importPo,
+	"export-xliff":   exportXliff,
+	"import-xliff":   importXliff,
+	"export-arb":     exportArb,
+	"export-android": exportAndroid,
+	"export-apple":   exportApple,
+	"export-csv":     exportCsv,
+	"import-csv":     importCsv,
+}
+
 func main() {
-	defaultLanguage := flag.String("default-language", "", "Specifies the default language")
-	messagesDir := flag.String("messages", "", "Specifies the directory with the files with the messages")
-	outFile := flag.String("out-file", "generated_lang.go", "Specifies the output file with the messages")
-	outPackage := flag.String("out-package", os.Getenv("GOPACKAGE"), "Specifies the output package name")
-	topInterfaceName := flag.String("top-interface-name", "messages", "Specifies the name for the top level interface")
-	publicNonNamedInterfaces := flag.Bool("public-non-named-interfaces", false, "Specifies that all generated interfaces should be public, even non named ones")
-	flag.Parse()
+	if len(os.Args) > 1 {
+		if command, found := commands[os.Args[1]]; found {
+			command(os.Args[2:])
+			return
+		}
+	}
+	generate(os.Args[1:])
+}
+
+// argumentTypesFlag collects the values of the -type flag, which can be repeated
+type argumentTypesFlag []string
+
+func (f *argumentTypesFlag) String() string {
+	return strings.Join(*f, " ")
+}
+
+func (f *argumentTypesFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+func typeFlag(flags *flag.FlagSet) *argumentTypesFlag {
+	argumentTypes := &argumentTypesFlag{}
+	flags.Var(argumentTypes, "type", "Registers a custom argument type written as name[,alias...]=type[:verb], like money=github.com/acme/money.Amount:v. Can be repeated")
+	return argumentTypes
+}
+
+func usage(flags *flag.FlagSet) {
+	flags.Usage()
+	fmt.Println("Commands: generate (default), check, lint, stats, fmt, add-lang, export-po, import-po, export-xliff, import-xliff, export-arb, export-android, export-apple, export-csv, import-csv")
+	fmt.Println("Version " + version)
+	os.Exit(1)
+}
+
+func generate(args []string) {
+	cli.Run(generateArgs("generate", args))
+}
+
+// check generates the code and compares it with the output file, like generate -check
+func check(args []string) {
+	cliArgs := generateArgs("check", args)
+	cliArgs.Check = true
+	cli.Run(cliArgs)
+}
+
+func generateArgs(name string, args []string) cli.CliArgs {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	defaultLanguage := flags.String("default-language", "", "Specifies the default language")
+	messagesDir := flags.String("messages", "", "Specifies the directory with the files with the messages")
+	outFile := flags.String("out-file", "generated_lang.go", "Specifies the output file with the messages")
+	outPackage := flags.String("out-package", os.Getenv("GOPACKAGE"), "Specifies the output package name")
+	topInterfaceName := flags.String("top-interface-name", "messages", "Specifies the name for the top level interface")
+	publicNonNamedInterfaces := flags.Bool("public-non-named-interfaces", false, "Specifies that all generated interfaces should be public, even non named ones")
+	icu := flags.Bool("icu", false, "Specifies that the messages use the ICU MessageFormat syntax")
+	argumentTypes := typeFlag(flags)
+	languageAliases := languageAliasFlag(flags)
+	check := flags.Bool("check", false, "Checks that the output file is up to date instead of writing it, printing the differences and exiting with an error if it is not")
+	parseFlags(flags, args)
 
 	if *defaultLanguage == "" || *messagesDir == "" || *outFile == "" || *outPackage == "" || *topInterfaceName == "" {
-		flag.Usage()
-		fmt.Println("Version v0.0.3")
-		os.Exit(1)
+		usage(flags)
 	}
 
-	cli.Run(cli.CliArgs{
+	return cli.CliArgs{
 		MessagesDirectory:        *messagesDir,
 		DefaultLanguage:          *defaultLanguage,
 		OutFile:                  *outFile,
 		Package:                  *outPackage,
 		TopLevelInterfaceName:    *topInterfaceName,
 		PublicNonNamedInterfaces: *publicNonNamedInterfaces,
+		Icu:                      *icu,
+		ArgumentTypes:            *argumentTypes,
+		LanguageAliases:          languageAliases,
+		Check:                    *check,
 		LogLevel:                 slog.LevelDebug,
+	}
+}
+
+// messagesFlags are the flags of the commands that only load the messages
+type messagesFlags struct {
+	defaultLanguage *string
+	messagesDir     *string
+	icu             *bool
+	argumentTypes   *argumentTypesFlag
+	languageAliases languageAliasesFlag
+}
+
+func newMessagesFlags(flags *flag.FlagSet) *messagesFlags {
+	return &messagesFlags{
+		defaultLanguage: flags.String("default-language", "", "Specifies the default language"),
+		messagesDir:     flags.String("messages", "", "Specifies the directory with the files with the messages"),
+		icu:             flags.Bool("icu", false, "Specifies that the messages use the ICU MessageFormat syntax"),
+		argumentTypes:   typeFlag(flags),
+		languageAliases: languageAliasFlag(flags),
+	}
+}
+
+func (f *messagesFlags) args(flags *flag.FlagSet) cli.MessagesArgs {
+	if *f.defaultLanguage == "" || *f.messagesDir == "" {
+		usage(flags)
+	}
+	return cli.MessagesArgs{
+		MessagesDirectory: *f.messagesDir,
+		DefaultLanguage:   *f.defaultLanguage,
+		Icu:               *f.icu,
+		ArgumentTypes:     *f.argumentTypes,
+		LanguageAliases:   f.languageAliases,
+		LogLevel:          slog.LevelDebug,
+	}
+}
+
+// messagesCommand returns a command that only needs the flags of the messages
+func messagesCommand(name string, command func(cli.MessagesArgs)) func(args []string) {
+	return func(args []string) {
+		flags := flag.NewFlagSet(name, flag.ExitOnError)
+		messages := newMessagesFlags(flags)
+		parseFlags(flags, args)
+		command(messages.args(flags))
+	}
+}
+
+func addLang(args []string) {
+	flags := flag.NewFlagSet("add-lang", flag.ExitOnError)
+	messages := newMessagesFlags(flags)
+	lang := flags.String("lang", "", "Specifies the language to add")
+	parseFlags(flags, args)
+
+	if *lang == "" {
+		usage(flags)
+	}
+
+	cli.AddLang(cli.AddLangArgs{
+		MessagesArgs: messages.args(flags),
+		Language:     *lang,
+	})
+}
+
+func poArgs(name string, args []string) cli.PoArgs {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	messages := newMessagesFlags(flags)
+	poDir := flags.String("po-dir", "", "Specifies the directory with the .po files, by default po next to the messages directory")
+	parseFlags(flags, args)
+
+	messagesArgs := messages.args(flags)
+	return cli.PoArgs{
+		MessagesArgs: messagesArgs,
+		PoDirectory:  besideMessages(messagesArgs, *poDir, "po"),
+	}
+}
+
+func exportPo(args []string) {
+	cli.ExportPo(poArgs("export-po", args))
+}
+
+func importPo(args []string) {
+	cli.ImportPo(poArgs("import-po", args))
+}
+
+func xliffArgs(name string, args []string) cli.XliffArgs {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	messages := newMessagesFlags(flags)
+	xliffDir := flags.String("xliff-dir", "", "Specifies the directory with the .xlf files, by default xliff next to the messages directory")
+	languages := flags.String("languages", "", "Specifies a comma separated list of languages to export even if they have no messages yet")
+	parseFlags(flags, args)
+
+	var langs []string
+	if *languages != "" {
+		langs = strings.Split(*languages, ",")
+	}
+	messagesArgs := messages.args(flags)
+	return cli.XliffArgs{
+		MessagesArgs:   messagesArgs,
+		XliffDirectory: besideMessages(messagesArgs, *xliffDir, "xliff"),
+		Languages:      langs,
+	}
+}
+
+func exportXliff(args []string) {
+	cli.ExportXliff(xliffArgs("export-xliff", args))
+}
+
+func importXliff(args []string) {
+	cli.ImportXliff(xliffArgs("import-xliff", args))
+}
+
+func exportArb(args []string) {
+	flags := flag.NewFlagSet("export-arb", flag.ExitOnError)
+	messages := newMessagesFlags(flags)
+	arbDir := flags.String("arb-dir", "", "Specifies the directory where the .arb files are written, by default arb next to the messages directory")
+	prefix := flags.String("arb-prefix", "app", "Specifies the prefix of the names of the .arb files")
+	parseFlags(flags, args)
+
+	if *prefix == "" {
+		usage(flags)
+	}
+
+	messagesArgs := messages.args(flags)
+	cli.ExportArb(cli.ArbArgs{
+		MessagesArgs: messagesArgs,
+		ArbDirectory: besideMessages(messagesArgs, *arbDir, "arb"),
+		Prefix:       *prefix,
 	})
 }
+
+func nativeArgs(name, defaultOutDir string, args []string) cli.NativeArgs {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	messages := newMessagesFlags(flags)
+	outDir := flags.String("out-dir", "", "Specifies the directory where the files are written, by default "+defaultOutDir+" next to the messages directory")
+	parseFlags(flags, args)
+
+	messagesArgs := messages.args(flags)
+	return cli.NativeArgs{
+		MessagesArgs: messagesArgs,
+		OutDirectory: besideMessages(messagesArgs, *outDir, defaultOutDir),
+	}
+}
+
+// besideMessages returns dir or, if it is not given, the directory with the name next to the messages directory, so
+// the exported files are not loaded as messages
+func besideMessages(messages cli.MessagesArgs, dir, name string) string {
+	if dir != "" {
+		return dir
+	}
+	messagesDir, err := filepath.Abs(messages.MessagesDirectory)
+	if err != nil {
+		return name
+	}
+	return filepath.Join(filepath.Dir(messagesDir), name)
+}
+
+func exportAndroid(args []string) {
+	cli.ExportAndroid(nativeArgs("export-android", "res", args))
+}
+
+func exportApple(args []string) {
+	cli.ExportApple(nativeArgs("export-apple", "apple", args))
+}
+
+func csvArgs(name string, args []string) cli.CsvArgs {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	messages := newMessagesFlags(flags)
+	csvFile := flags.String("csv-file", "messages.csv", "Specifies the csv file with the table of messages")
+	parseFlags(flags, args)
+
+	if *csvFile == "" {
+		usage(flags)
+	}
+
+	return cli.CsvArgs{
+		MessagesArgs: messages.args(flags),
+		CsvFile:      *csvFile,
+	}
+}
+
+func exportCsv(args []string) {
+	cli.ExportCsv(csvArgs("export-csv", args))
+}
+
+func importCsv(args []string) {
+	cli.ImportCsv(csvArgs("import-csv", args))
+}
@@ -1,78 +1,124 @@
-/** Code generated using https://github.com/MrNemo64/go-n-i18n 
+/** Code generated using https://github.com/MrNemo64/go-n-i18n
  * Any changes to this file will be lost on the next tool run */
 
 package lang
 
 import (
-    "fmt"
-    "strings"
+	"fmt"
+	"strconv"
+	"strings"
 )
 
 func MessagesFor(tag string) (Messages, bool) {
-    switch strings.ReplaceAll(tag, "_", "-") {
-    case "en-EN":
-        return en_EN_Messages{}, true
-    }
-    return nil, false
+	switch strings.ReplaceAll(tag, "_", "-") {
+	case "en-EN":
+		return en_EN_Messages{}, true
+	}
+	return nil, false
 }
 
 func MessagesForMust(tag string) Messages {
-    switch strings.ReplaceAll(tag, "_", "-") {
-    case "en-EN":
-        return en_EN_Messages{}
-    }
-    panic(fmt.Errorf("unknwon language tag: " + tag))
+	switch strings.ReplaceAll(tag, "_", "-") {
+	case "en-EN":
+		return en_EN_Messages{}
+	}
+	panic(fmt.Errorf("unknwon language tag: " + tag))
 }
 
 func MessagesForOrDefault(tag string) Messages {
-    switch strings.ReplaceAll(tag, "_", "-") {
-    case "en-EN":
-        return en_EN_Messages{}
-    }
-    return en_EN_Messages{}
+	switch strings.ReplaceAll(tag, "_", "-") {
+	case "en-EN":
+		return en_EN_Messages{}
+	}
+	return en_EN_Messages{}
 }
 
-type Messages interface{
-    WhereAmI() string
-    NestedMessages() nestedMessages
-    MultiLineMessage(user string, amount float64) string
-    ConditionalMessages(amount int) string
+type Messages interface {
+	WhereAmI() string
+	NestedMessages() nestedMessages
+	MultiLineMessage(user string, amount float64) string
+	ConditionalMessages(amount int) string
+	PluralMessages(apples int) string
 }
-type nestedMessages interface{
-    Simple() string
-    Parametrized(amount int) string
+type nestedMessages interface {
+	Simple() string
+	Parametrized(amount int) string
 }
 
 type en_EN_Messages struct{}
+
 func (en_EN_Messages) WhereAmI() string {
-    return "Assume this json is in the file \"en-EN.json\""
+	return "Assume this json is in the file \"en-EN.json\""
 }
 func (en_EN_Messages) NestedMessages() nestedMessages {
-    return en_EN_nestedMessages{}
+	return en_EN_nestedMessages{}
 }
+
 type en_EN_nestedMessages struct{}
+
 func (en_EN_nestedMessages) Simple() string {
-    return "This is just a simple message nested into \"nested-messages\""
+	return "This is just a simple message nested into \"nested-messages\""
 }
 func (en_EN_nestedMessages) Parametrized(amount int) string {
-    return fmt.Sprintf("This message has an amount parameter of type int: %d", amount)
+	return fmt.Sprintf("This message has an amount parameter of type int: %d", amount)
 }
 func (en_EN_Messages) MultiLineMessage(user string, amount float64) string {
-    return fmt.Sprintf("Hello %s!", user) + "\n" +
-        "Messages can be multi-line" + "\n" +
-        "And each one can have parameters" + "\n" +
-        fmt.Sprintf("This one has a float formatted with 2 decimals! %.2f", amount)
+	return fmt.Sprintf("Hello %s!", user) + "\n" +
+		"Messages can be multi-line" + "\n" +
+		"And each one can have parameters" + "\n" +
+		fmt.Sprintf("This one has a float formatted with 2 decimals! %.2f", amount)
 }
 func (en_EN_Messages) ConditionalMessages(amount int) string {
-    if amount == 0 {
-        return "If amount is 0, this message is used"
-    } else if amount == 1 {
-        return "This message is returned if the amount is 1"
-    } else {
-        return "This is the \"else\" branch" + "\n" +
-            "This multi-line message is used" + "\n" +
-            fmt.Sprintf("And shows the amount: %d", amount)
-    }
+	if amount == 0 {
+		return "If amount is 0, this message is used"
+	} else if amount == 1 {
+		return "This message is returned if the amount is 1"
+	} else {
+		return "This is the \"else\" branch" + "\n" +
+			"This multi-line message is used" + "\n" +
+			fmt.Sprintf("And shows the amount: %d", amount)
+	}
+}
+func (en_EN_Messages) PluralMessages(apples int) string {
+	if apples == 0 {
+		return "You have no apples"
+	}
+	switch pluralRule_en(float64(apples)) {
+	case pluralOne:
+		return "You have one apple"
+	}
+	return fmt.Sprintf("You have %d apples", apples)
 }
 
+type pluralForm int
 
+const (
+	pluralZero pluralForm = iota
+	pluralOne
+	pluralTwo
+	pluralFew
+	pluralMany
+	pluralOther
+)
+
+// pluralOperands returns the CLDR plural operands of x
+func pluralOperands(x float64) (n float64, i, v, f, t int64) {
+	if x < 0 {
+		x = -x
+	}
+	n = x
+	integer, fraction, _ := strings.Cut(strconv.FormatFloat(x, 'f', -1, 64), ".")
+	i, _ = strconv.ParseInt(integer, 10, 64)
+	v = int64(len(fraction))
+	f, _ = strconv.ParseInt(fraction, 10, 64)
+	t = f
+	return
+}
+
+func pluralRule_en(x float64) pluralForm {
+	_, i, v, _, _ := pluralOperands(x)
+	if i == 1 && v == 0 {
+		return pluralOne
+	}
+	return pluralOther
+}
@@ -17,11 +17,14 @@ func main() {
 		This multiline message is used
 		And shows the amount: 100
 	*/
-	fmt.Println(bundle.MultilineMessage("MrNemo64", 13.1267))
+	fmt.Println(bundle.MultiLineMessage("MrNemo64", 13.1267))
 	/*
 		Hello MrNemo64!
 		Messages can be multiline
 		And each one can have parameters
 		This one has a float formated with 2 decimals! 13.13
 	*/
+	fmt.Println(bundle.PluralMessages(0)) // You have no apples
+	fmt.Println(bundle.PluralMessages(1)) // You have one apple
+	fmt.Println(bundle.PluralMessages(5)) // You have 5 apples
 }
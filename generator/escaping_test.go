@@ -0,0 +1,46 @@
+package generator
+
+import (
+	"fmt"
+	"testing"
+)
+
+// escapingMessages are the messages of TestGeneratedEscaping, with characters that must be escaped in the generated
+// string literals and Sprintf formats
+const escapingMessages = `
+block: |-
+  First line
+  Second "line"
+folded: >
+  Folded
+  text
+path: C:\Users\{name:str}
+discount: 100% off
+sale: "{amount:int}% off, \"{name:str}\" 100%"
+tab: "A\tB"
+`
+
+func TestGeneratedEscaping(t *testing.T) {
+	tests := []struct {
+		message string
+		args    string
+		want    string
+	}{
+		{message: "Block", want: "First line\nSecond \"line\""},
+		{message: "Folded", want: "Folded text\n"},
+		{message: "Path", args: `"Ana"`, want: `C:\Users\Ana`},
+		{message: "Discount", want: "100% off"},
+		{message: "Sale", args: `50, "Ana"`, want: `50% off, "Ana" 100%`},
+		{message: "Tab", want: "A\tB"},
+	}
+	var calls []string
+	for _, test := range tests {
+		calls = append(calls, fmt.Sprintf("fmt.Sprintf(\"%%q\", lang.MessagesForMust(\"en\").%s(%s))", test.message, test.args))
+	}
+	lines := runGenerated(t, map[string]string{"en.yaml": escapingMessages}, calls)
+	for i, test := range tests {
+		if lines[i] != fmt.Sprintf("%q", test.want) {
+			t.Errorf("%s: expected %q, got %s", test.message, test.want, lines[i])
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// formatterMessages are the messages of the languages of TestGeneratedFormatters
+const formatterMessages = `{
+	"number": "{x:number}",
+	"rounded": "{x:number:integer}",
+	"percent": "{x:percent:.1}",
+	"money": "{x:currency:EUR}",
+	"#apples": {"_arg": "x", "one": "one", "few": "few", "many": "many", "other": "other {x:number}"},
+	"day": "{x:date}",
+	"long-day": "{x:date:long}",
+	"full-day": "{x:date:full}",
+	"short-time": "{x:time:short}",
+	"skeleton": "{x:date:yMMMEd}",
+	"hour": "{x:time:jm}",
+	"took": "{x:duration}",
+	"when": "{x:reltime}",
+	"invited": "{x:list}",
+	"choose": "{x:list:or}",
+	"measure": "{x:list:unit}"
+}`
+
+func TestGeneratedFormatters(t *testing.T) {
+	tests := []struct {
+		lang    string
+		message string
+		value   string
+		want    string
+	}{
+		{lang: "en", message: "Number", value: "1234.5", want: "1,234.5"},
+		{lang: "en", message: "Number", value: "-0.12345", want: "-0.123"},
+		{lang: "en", message: "Number", value: "1234567", want: "1,234,567"},
+		{lang: "en", message: "Number", value: "math.NaN()", want: "NaN"},
+		{lang: "en", message: "Number", value: "math.Inf(-1)", want: "-∞"},
+		{lang: "en", message: "Rounded", value: "2.6", want: "3"},
+		{lang: "en", message: "Rounded", value: "2.5", want: "2"}, // half to even, like ICU
+		{lang: "en", message: "Percent", value: "0.256", want: "25.6%"},
+		{lang: "en", message: "Money", value: "-1234.5", want: "-€1,234.50"},
+		{lang: "de", message: "Number", value: "1234.5", want: "1.234,5"},
+		{lang: "de", message: "Percent", value: "0.5", want: "50,0 %"},
+		{lang: "de", message: "Money", value: "1234.5", want: "1.234,50 €"},
+		{lang: "es", message: "Number", value: "1234", want: "1234"},
+		{lang: "es", message: "Number", value: "12345", want: "12.345"},
+		{lang: "fi", message: "Number", value: "-1234", want: "−1 234"},
+		{lang: "fi", message: "Number", value: "math.NaN()", want: "epäluku"},
+		{lang: "en-IN", message: "Number", value: "1234567", want: "12,34,567"},
+		{lang: "en", message: "Apples", value: "1", want: "one"},
+		{lang: "en", message: "Apples", value: "1.5", want: "other 1.5"},
+		{lang: "ru", message: "Apples", value: "21", want: "one"},
+		{lang: "ru", message: "Apples", value: "23", want: "few"},
+		{lang: "ru", message: "Apples", value: "11", want: "many"},
+		{lang: "ru", message: "Apples", value: "-5", want: "many"},
+		{lang: "ru", message: "Apples", value: "2.5", want: "other 2,5"},
+		{lang: "fr", message: "Apples", value: "0", want: "one"},
+		{lang: "fr", message: "Apples", value: "1000000", want: "many"},
+		{lang: "en", message: "Day", value: "march5", want: "Mar 5, 2024"},
+		{lang: "en", message: "LongDay", value: "march5", want: "March 5, 2024"},
+		{lang: "en", message: "FullDay", value: "march5", want: "Tuesday, March 5, 2024"},
+		{lang: "en", message: "ShortTime", value: "march5", want: "3:04 PM"},
+		{lang: "en", message: "Skeleton", value: "march5", want: "Tue, Mar 5, 2024"},
+		{lang: "en", message: "Hour", value: "march5", want: "3:04 PM"},
+		{lang: "de", message: "LongDay", value: "march5", want: "5. März 2024"},
+		{lang: "de", message: "Hour", value: "march5", want: "15:04"},
+		{lang: "es", message: "LongDay", value: "march5", want: "5 de marzo de 2024"},
+		{lang: "es", message: "Skeleton", value: "march5", want: "mar, 5 mar 2024"},
+		{lang: "fr", message: "FullDay", value: "march5", want: "mardi 5 mars 2024"},
+		{lang: "en", message: "Took", value: "0", want: "0 seconds"},
+		{lang: "en", message: "Took", value: "time.Second", want: "1 second"},
+		{lang: "en", message: "Took", value: "89 * time.Second", want: "1 minute"},
+		{lang: "en", message: "Took", value: "90 * time.Second", want: "2 minutes"},
+		{lang: "en", message: "Took", value: "90 * time.Minute", want: "2 hours"},
+		{lang: "en", message: "Took", value: "59*time.Minute + 40*time.Second", want: "1 hour"},
+		{lang: "en", message: "Took", value: "-36 * time.Hour", want: "2 days"},
+		{lang: "en", message: "Took", value: "13 * 24 * time.Hour", want: "2 weeks"},
+		{lang: "en", message: "Took", value: "400 * 24 * time.Hour", want: "1 year"},
+		{lang: "en", message: "Took", value: "math.MinInt64", want: "292 years"},
+		{lang: "es", message: "Took", value: "3 * time.Minute", want: "3 minutos"},
+		{lang: "de", message: "Took", value: "22 * time.Hour", want: "22 Stunden"},
+		{lang: "en", message: "When", value: "time.Now().Add(-49 * time.Hour)", want: "2 days ago"},
+		{lang: "en", message: "When", value: "time.Now().Add(time.Hour + 20*time.Minute)", want: "in 1 hour"},
+		{lang: "en", message: "When", value: "time.Now()", want: "now"},
+		{lang: "es", message: "When", value: "time.Now().Add(-49 * time.Hour)", want: "hace 2 días"},
+		{lang: "en", message: "Invited", value: "nil", want: ""},
+		{lang: "en", message: "Invited", value: `[]string{"a"}`, want: "a"},
+		{lang: "en", message: "Invited", value: `[]string{"a", "b"}`, want: "a and b"},
+		{lang: "en", message: "Invited", value: `[]string{"a", "b", "c"}`, want: "a, b, and c"},
+		{lang: "en", message: "Invited", value: `[]string{"a", "b", "c", "d"}`, want: "a, b, c, and d"},
+		{lang: "en", message: "Choose", value: `[]string{"a", "b", "c"}`, want: "a, b, or c"},
+		{lang: "en", message: "Measure", value: `[]string{"3 feet", "7 inches"}`, want: "3 feet, 7 inches"},
+		{lang: "es", message: "Invited", value: `[]string{"a", "b", "c"}`, want: "a, b y c"},
+		{lang: "es", message: "Choose", value: `[]string{"a", "b", "c"}`, want: "a, b o c"},
+		{lang: "de", message: "Invited", value: `[]string{"a", "b", "c"}`, want: "a, b und c"},
+		{lang: "ru", message: "LongDay", value: "march5", want: "2024 M03 5"}, // the root patterns, ru has no calendar data
+	}
+
+	files := make(map[string]string)
+	for _, lang := range []string{"en", "de", "es", "fi", "en-IN", "ru", "fr"} {
+		files[lang+".json"] = formatterMessages
+	}
+	var calls []string
+	for _, test := range tests {
+		calls = append(calls, fmt.Sprintf("lang.MessagesForMust(%q).%s(%s)", test.lang, test.message, test.value))
+	}
+	lines := runGenerated(t, files, calls)
+	for i, test := range tests {
+		if lines[i] != test.want {
+			t.Errorf("%s(%s) in %s: expected %q, got %q", test.message, test.value, test.lang, test.want, lines[i])
+		}
+	}
+}
+
+// runGenerated generates the code of the message files, with en as default language, and runs a program
+// that prints each call on its own line, returning the printed lines
+func runGenerated(t *testing.T, files map[string]string, calls []string) []string {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("builds and runs the generated code")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("the go command is not available")
+	}
+	messages := filepath.Join(t.TempDir(), "lang")
+	if err := os.Mkdir(messages, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(messages, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	result, err := Generate(context.Background(), Options{MessagesDirectory: messages, DefaultLanguage: "en", Package: "lang"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	module := filepath.Dir(messages)
+	main := strings.Builder{}
+	main.WriteString("package main\n\nimport (\n\t\"fmt\"\n\t\"math\"\n\t\"time\"\n\n\t\"formatters/lang\"\n)\n\n")
+	main.WriteString("var (\n\tmarch5 = time.Date(2024, time.March, 5, 15, 4, 9, 0, time.UTC)\n\t_      = math.Pi\n)\n\nfunc main() {\n")
+	for _, call := range calls {
+		fmt.Fprintf(&main, "\tfmt.Println(%s)\n", call)
+	}
+	main.WriteString("}\n")
+	generated := map[string]string{
+		"go.mod":                 "module formatters\n\ngo 1.23\n",
+		"main.go":                main.String(),
+		"lang/generated_lang.go": result.Code,
+	}
+	for name, content := range generated {
+		if err := os.WriteFile(filepath.Join(module, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := exec.Command(goBin, "run", ".")
+	cmd.Dir = module
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("could not run the generated code: %v\n%s", err, output)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(output), "\n"), "\n")
+	if len(lines) != len(calls) {
+		t.Fatalf("expected %d lines, got:\n%s", len(calls), output)
+	}
+	return lines
+}
@@ -0,0 +1,158 @@
+// Package generator runs the pipeline of the i18n command from go code: it loads the message files, validates them
+// and generates the code with a method for each message
+package generator
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/parse"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/pipeline"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/validate"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/writing"
+)
+
+// Options are the settings of the generation, the same as the flags of the i18n generate command
+type Options struct {
+	MessagesDirectory string
+	DefaultLanguage   string
+	// Package is the name of the package of the generated code
+	Package string
+	// TopLevelInterfaceName is the name of the interface with all the messages, messages if empty
+	TopLevelInterfaceName    string
+	PublicNonNamedInterfaces bool
+	// Icu parses the messages with the ICU MessageFormat syntax
+	Icu bool
+	// ArgumentTypes are custom argument types written as name[,alias...]=type[:verb]
+	ArgumentTypes []string
+	// LanguageAliases map the languages of the file names to the languages of their messages
+	LanguageAliases map[string]string
+	// Logger receives the steps of the generation, nothing is logged if nil
+	Logger *slog.Logger
+}
+
+type Severity int
+
+const (
+	// SeverityWarning diagnostics are the problems found in the messages, the generation only stops if an error is
+	// returned along with them
+	SeverityWarning Severity = iota
+	// SeverityError diagnostics are problems that stop the generation by themselves
+	SeverityError
+)
+
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+// Diagnostic is a problem found while loading the messages or generating the code
+type Diagnostic struct {
+	Severity Severity
+	Message  string
+	// Lang is the language the diagnostic is about, empty if it is not about a single one
+	Lang string
+	// Paths are the paths of the messages the diagnostic is about, if it is about a group of messages
+	Paths []string
+}
+
+type Result struct {
+	// Code is the formatted go code, empty if it could not be generated
+	Code string
+	// Messages are the loaded messages, nil if they could not be loaded
+	Messages    *Messages
+	Diagnostics []Diagnostic
+}
+
+// Load parses and validates the messages. If they have problems, they are returned as diagnostics with
+// SeverityWarning along with the error
+func Load(ctx context.Context, opts Options) (Result, error) {
+	log := logger(opts)
+	result := Result{}
+	wc := util.NewWarningsCollector()
+	loaded, err := pipeline.Load(ctx, log, pipeline.Options{
+		MessagesDirectory: opts.MessagesDirectory,
+		DefaultLanguage:   opts.DefaultLanguage,
+		ArgumentTypes:     opts.ArgumentTypes,
+		LanguageAliases:   opts.LanguageAliases,
+		Parse:             parse.Options{Icu: opts.Icu},
+	}, wc)
+	for _, warning := range wc.Warnings() {
+		result.Diagnostics = append(result.Diagnostics, Diagnostic{Severity: SeverityWarning, Message: warning.Error()})
+	}
+	if err != nil {
+		return result, err
+	}
+
+	if len(loaded.Removed) > 0 {
+		result.Diagnostics = append(result.Diagnostics, Diagnostic{
+			Severity: SeverityWarning,
+			Message:  "Removed entries without the default language",
+			Lang:     opts.DefaultLanguage,
+			Paths:    util.Map(loaded.Removed, func(_ int, entry *types.MessageEntry) string { return (*entry).PathAsStr() }),
+		})
+	}
+	result.Messages = &Messages{bag: loaded.Bag, files: loaded.Files, defaultLanguage: opts.DefaultLanguage}
+	return result, nil
+}
+
+// Generate loads the messages and generates their code. The languages missing some messages, or some branches of
+// conditional and plural messages, use the ones of the default language, which is reported as a diagnostic for
+// each language
+func Generate(ctx context.Context, opts Options) (Result, error) {
+	result, err := Load(ctx, opts)
+	if err != nil {
+		return result, err
+	}
+	log := logger(opts)
+	bag := result.Messages.bag
+	langs := result.Messages.Languages()
+	filled := bag.MustHaveAllLangs(langs, opts.DefaultLanguage)
+	for lang, branches := range bag.CompleteBranches(opts.DefaultLanguage) {
+		filled[lang] = append(filled[lang], branches...)
+	}
+	for _, lang := range langs {
+		if paths := filled[lang]; len(paths) > 0 {
+			result.Diagnostics = append(result.Diagnostics, Diagnostic{
+				Severity: SeverityWarning,
+				Message:  "Some entries were missing in the language. Using the message of the default language",
+				Lang:     lang,
+				Paths:    paths,
+			})
+		}
+	}
+	for _, lang := range langs {
+		wc := util.NewWarningsCollector()
+		validate.LanguageData(bag, []string{lang}, wc)
+		for _, warning := range wc.Warnings() {
+			result.Diagnostics = append(result.Diagnostics, Diagnostic{Severity: SeverityWarning, Message: warning.Error(), Lang: lang})
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
+	topLevelInterfaceName := opts.TopLevelInterfaceName
+	if topLevelInterfaceName == "" {
+		topLevelInterfaceName = "messages"
+	}
+	log.Info("Generating code")
+	code, err := writing.GenerateGoCode(bag, writing.GoNamer(topLevelInterfaceName, opts.PublicNonNamedInterfaces), langs, opts.DefaultLanguage, opts.Package)
+	if err != nil {
+		return result, err
+	}
+	result.Code = code
+	return result, nil
+}
+
+func logger(opts Options) *slog.Logger {
+	if opts.Logger == nil {
+		return slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	return opts.Logger
+}
@@ -0,0 +1,68 @@
+package generator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+func TestGenerateExample(t *testing.T) {
+	dir := filepath.Join("..", "example", "lang")
+	want, err := os.ReadFile(filepath.Join(dir, "generated_lang.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := Generate(context.Background(), Options{MessagesDirectory: dir, DefaultLanguage: "en-EN", Package: "lang"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, diagnostic := range result.Diagnostics {
+		t.Errorf("unexpected %s: %s", diagnostic.Severity, diagnostic.Message)
+	}
+	diff, err := util.UnifiedDiff("generated_lang.go", "generated", string(want), result.Code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Errorf("the generated code is not the one of the example:\n%s", diff)
+	}
+}
+
+func TestLoadExample(t *testing.T) {
+	result, err := Load(context.Background(), Options{MessagesDirectory: filepath.Join("..", "example", "lang"), DefaultLanguage: "en-EN"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path []string
+		args []string // the names of the arguments, nil if the message does not exist
+	}{
+		{path: []string{"where-am-i"}, args: []string{}},
+		{path: []string{"nested-messages", "parametrized"}, args: []string{"amount"}},
+		{path: []string{"multi-line-message"}, args: []string{"user", "amount"}},
+		{path: []string{"conditional-messages"}, args: []string{"amount"}},
+		{path: []string{"plural-messages"}, args: []string{"apples"}},
+		{path: []string{"nested-messages"}},
+		{path: []string{"nested-messages", "missing"}},
+	}
+	for _, test := range tests {
+		t.Run(strings.Join(test.path, "."), func(t *testing.T) {
+			message, found := result.Messages.Get(test.path...)
+			if found != (test.args != nil) {
+				t.Fatalf("expected found to be %v", test.args != nil)
+			}
+			if !found {
+				return
+			}
+			args := util.Map(message.Arguments(), func(_ int, arg *Argument) string { return arg.Name })
+			if !slices.Equal(args, test.args) {
+				t.Errorf("expected the arguments %v, got %v", test.args, args)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"github.com/MrNemo64/go-n-i18n/internal/cli/parse"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+)
+
+// Messages are the messages loaded from the messages directory
+type Messages struct {
+	bag             *types.MessageBag
+	files           []parse.FileEntry
+	defaultLanguage string
+}
+
+func (m *Messages) DefaultLanguage() string { return m.defaultLanguage }
+
+// Languages returns the languages with messages, the default one first and the rest in the order they were found
+func (m *Messages) Languages() []string {
+	langs := []string{m.defaultLanguage}
+	for _, lang := range m.bag.Languages().Get() {
+		if lang != m.defaultLanguage {
+			langs = append(langs, lang)
+		}
+	}
+	return langs
+}
+
+// Files returns the paths of the files the messages were read from
+func (m *Messages) Files() []string {
+	files := make([]string, len(m.files))
+	for i, file := range m.files {
+		files[i] = file.FullPath()
+	}
+	return files
+}
+
+// All returns all the messages in the order they were declared
+func (m *Messages) All() []Message {
+	instances := m.bag.Instances()
+	messages := make([]Message, len(instances))
+	for i, instance := range instances {
+		messages[i] = Message{instance}
+	}
+	return messages
+}
+
+// Get returns the message in the path, like Get("user", "greet") for the message user.greet
+func (m *Messages) Get(path ...string) (Message, bool) {
+	bag := m.bag
+	for i, part := range path {
+		entry, found := bag.GetEntry(part)
+		if !found {
+			return Message{}, false
+		}
+		if i == len(path)-1 {
+			if !entry.IsInstance() {
+				return Message{}, false
+			}
+			return Message{entry.AsInstance()}, true
+		}
+		if !entry.IsBag() {
+			return Message{}, false
+		}
+		bag = entry.AsBag()
+	}
+	return Message{}, false
+}
+
+// Message is a message with its translations
+type Message struct {
+	instance *types.MessageInstance
+}
+
+func (m Message) Path() []string { return m.instance.Path() }
+
+// Key returns the path of the message joined by dots
+func (m Message) Key() string { return m.instance.PathAsStr() }
+
+// Languages returns the languages the message is written in. After Generate, the languages where it was missing
+// have the message of the default language
+func (m Message) Languages() []string { return m.instance.Languages().Get() }
+
+// File returns the path of the file with the message in the language
+func (m Message) File(lang string) string { return m.instance.Source(lang) }
+
+// Description returns the documentation of the message in the language, empty if it has none
+func (m Message) Description(lang string) string { return m.instance.Description(lang) }
+
+// Argument is a parameter of the method of a message
+type Argument struct {
+	Name string
+	// Type is the name of the argument type, like int or date
+	Type string
+	// GoType is the go type of the parameter
+	GoType string
+	// Format is the format used when the message does not give one
+	Format string
+}
+
+// Arguments returns the parameters of the method of the message in order
+func (m Message) Arguments() []Argument {
+	args := m.instance.Args().Args
+	arguments := make([]Argument, len(args))
+	for i, arg := range args {
+		arguments[i] = Argument{Name: arg.Name, Type: arg.Type.Name, GoType: arg.Type.Type, Format: arg.DefaultFormat()}
+	}
+	return arguments
+}
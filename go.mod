@@ -2,4 +2,8 @@ module github.com/MrNemo64/go-n-i18n
 
 go 1.23.2
 
-require github.com/iancoleman/orderedmap v0.3.0
+require (
+	github.com/BurntSushi/toml v1.5.0
+	github.com/iancoleman/orderedmap v0.3.0
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/exchange"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+type AddLangArgs struct {
+	MessagesArgs
+	Language string
+}
+
+// AddLang writes the json files of a new language next to the files of the default language, with the keys of its
+// messages and null texts, which are untranslated until they are filled
+func AddLang(args AddLangArgs) {
+	log := newLogger(args.LogLevel)
+	messages, files := args.load(log)
+	if messages.Languages().Contains(args.Language) {
+		log.Error("The language already has messages", "lang", args.Language)
+		os.Exit(1)
+	}
+
+	wc := util.NewWarningsCollector()
+	importer := exchange.NewImporter(messages, files, args.DefaultLanguage, wc)
+	for _, row := range exchange.CsvRows(messages, []string{args.Language}, args.DefaultLanguage) {
+		importer.SetUntranslated(args.Language, row.Key)
+	}
+	for _, warning := range wc.Warnings() {
+		log.Warn(warning.Error())
+	}
+
+	if err := importer.Save(); err != nil {
+		log.Error("Could not save the new language", "err", err)
+		os.Exit(1)
+	}
+	log.Info("Added the language", "lang", args.Language)
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/exchange"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/parse"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+type ArbArgs struct {
+	MessagesArgs
+	ArbDirectory string
+	Prefix       string // the files are named <prefix>_<lang>.arb
+}
+
+// ExportArb writes a <prefix>_<lang>.arb file for each language into the arb directory. The file of the default
+// language is the template with the metadata of the messages
+func ExportArb(args ArbArgs) {
+	log := newLogger(args.LogLevel)
+	messages, _ := args.load(log)
+
+	if inside, err := isInside(args.ArbDirectory, args.MessagesDirectory); err != nil {
+		log.Error("Could not resolve the arb directory", "err", err)
+		os.Exit(1)
+	} else if inside {
+		log.Error("The arb directory can not be inside the messages directory since its files would be loaded as messages", "dir", args.ArbDirectory)
+		os.Exit(1)
+	}
+	if err := os.MkdirAll(args.ArbDirectory, 0755); err != nil {
+		log.Error("Could not create the arb directory", "err", err)
+		os.Exit(1)
+	}
+
+	wc := util.NewWarningsCollector()
+	for _, lang := range messages.Languages().Get() {
+		log.Info("Exporting language", "lang", lang)
+		content, err := parse.EncodeJson(exchange.ArbDocument(messages, lang, args.DefaultLanguage, wc))
+		if err != nil {
+			log.Error("Could not encode the arb file", "lang", lang, "err", err)
+			os.Exit(1)
+		}
+		name := filepath.Join(args.ArbDirectory, args.Prefix+"_"+exchange.ArbLocale(lang)+".arb")
+		if err := os.WriteFile(name, content, 0644); err != nil {
+			log.Error("Could not write arb file", "file", name, "err", err)
+			os.Exit(1)
+		}
+	}
+	for _, warning := range wc.Warnings() {
+		log.Warn(warning.Error())
+	}
+}
+
+// isInside reports whether the path is dir or is inside it
+func isInside(path, dir string) (bool, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false, err
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false, err
+	}
+	rel, err := filepath.Rel(absDir, absPath)
+	if err != nil {
+		return false, nil // on another volume
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))), nil
+}
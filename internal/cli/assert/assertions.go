@@ -22,3 +22,9 @@ func Has[T comparable](arr []T, el T) {
 	}
 	panic(fmt.Errorf("expected slice %+v to have the element %+v", arr, el))
 }
+
+func True(cond bool, msg string) {
+	if !cond {
+		panic(fmt.Errorf("expected condition to be true: %s", msg))
+	}
+}
@@ -0,0 +1,252 @@
+package cldr
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+var (
+	ErrInvalidDateFormat util.Error = util.MakeError("expected an empty format, full, long, medium, short or one of the skeletons %s")
+)
+
+// DateStyles are the styles of the date and time patterns, from the longest to the shortest
+var DateStyles = []string{"full", "long", "medium", "short"}
+
+// DefaultDateStyle is the style used by date and time arguments without format
+const DefaultDateStyle = "medium"
+
+// DateSkeletons are the CLDR skeletons that can be used as format of date and time arguments. In the skeletons, j is
+// the hour in the format the language prefers, h (1-12) or H (0-23)
+var DateSkeletons = []string{
+	"yMd", "yMMMd", "yMMMMd", "yMMMEd", "yMMM", "yMMMM", "MMMd", "MMMMd", "MEd", "Md", "Hm", "hm", "Hms", "hms",
+}
+
+// DateSymbols are the CLDR names and patterns of the gregorian calendar of a language. Days start on Sunday
+type DateSymbols struct {
+	Id          string
+	Months      [12]string
+	ShortMonths [12]string
+	Days        [7]string
+	ShortDays   [7]string
+	Am          string
+	Pm          string
+	// Hour is the hour field the language prefers, h (1-12) or H (0-23)
+	Hour byte
+	// DatePatterns and TimePatterns are the patterns of each style, DateTimePatterns join a date of the style with a time
+	// replacing {1} with the date and {0} with the time
+	DatePatterns     map[string]string
+	TimePatterns     map[string]string
+	DateTimePatterns map[string]string
+	Skeletons        map[string]string
+}
+
+func styles(full, long, medium, short string) map[string]string {
+	return map[string]string{"full": full, "long": long, "medium": medium, "short": short}
+}
+
+// timeStyles returns the time patterns of each style, the full one has no zone name so it is the long one
+func timeStyles(long, medium, short string) map[string]string {
+	return styles(long, long, medium, short)
+}
+
+// skeletons returns the patterns of DateSkeletons, the ones of the times are the same in most languages
+func skeletons(yMd, yMMMd, yMMMMd, yMMMEd, yMMM, yMMMM, MMMd, MMMMd, MEd, Md string) map[string]string {
+	return map[string]string{
+		"yMd": yMd, "yMMMd": yMMMd, "yMMMMd": yMMMMd, "yMMMEd": yMMMEd, "yMMM": yMMM, "yMMMM": yMMMM,
+		"MMMd": MMMd, "MMMMd": MMMMd, "MEd": MEd, "Md": Md,
+		"Hm": "HH:mm", "hm": "h:mm a", "Hms": "HH:mm:ss", "hms": "h:mm:ss a",
+	}
+}
+
+// joinAll returns the same date time pattern for all the styles
+func joinAll(pattern string) map[string]string {
+	return styles(pattern, pattern, pattern, pattern)
+}
+
+var times24 = timeStyles("HH:mm:ss z", "HH:mm:ss", "HH:mm")
+
+var rootDates = &DateSymbols{
+	Id:               "root",
+	Months:           [12]string{"M01", "M02", "M03", "M04", "M05", "M06", "M07", "M08", "M09", "M10", "M11", "M12"},
+	ShortMonths:      [12]string{"M01", "M02", "M03", "M04", "M05", "M06", "M07", "M08", "M09", "M10", "M11", "M12"},
+	Days:             [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+	ShortDays:        [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+	Am:               "AM",
+	Pm:               "PM",
+	Hour:             'H',
+	DatePatterns:     styles("y MMMM d, EEEE", "y MMMM d", "y MMM d", "y-MM-dd"),
+	TimePatterns:     times24,
+	DateTimePatterns: joinAll("{1} {0}"),
+	Skeletons:        skeletons("y-MM-dd", "y MMM d", "y MMMM d", "y MMM d, E", "y MMM", "y MMMM", "MMM d", "MMMM d", "MM-dd, E", "MM-dd"),
+}
+
+var (
+	englishMonths = [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}
+	englishDays   = [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+)
+
+// dateSymbols holds the CLDR gregorian calendar data by language
+var dateSymbols = map[string]*DateSymbols{
+	"en": {
+		Months:           englishMonths,
+		ShortMonths:      [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		Days:             englishDays,
+		ShortDays:        [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		Am:               "AM",
+		Pm:               "PM",
+		Hour:             'h',
+		DatePatterns:     styles("EEEE, MMMM d, y", "MMMM d, y", "MMM d, y", "M/d/yy"),
+		TimePatterns:     timeStyles("h:mm:ss a z", "h:mm:ss a", "h:mm a"),
+		DateTimePatterns: styles("{1} 'at' {0}", "{1} 'at' {0}", "{1}, {0}", "{1}, {0}"),
+		Skeletons:        skeletons("M/d/y", "MMM d, y", "MMMM d, y", "EEE, MMM d, y", "MMM y", "MMMM y", "MMM d", "MMMM d", "EEE, M/d", "M/d"),
+	},
+	"en-GB": {
+		Months:           englishMonths,
+		ShortMonths:      [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sept", "Oct", "Nov", "Dec"},
+		Days:             englishDays,
+		ShortDays:        [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		Am:               "am",
+		Pm:               "pm",
+		Hour:             'H',
+		DatePatterns:     styles("EEEE d MMMM y", "d MMMM y", "d MMM y", "dd/MM/y"),
+		TimePatterns:     times24,
+		DateTimePatterns: styles("{1} 'at' {0}", "{1} 'at' {0}", "{1}, {0}", "{1}, {0}"),
+		Skeletons:        skeletons("dd/MM/y", "d MMM y", "d MMMM y", "EEE, d MMM y", "MMM y", "MMMM y", "d MMM", "d MMMM", "EEE dd/MM", "dd/MM"),
+	},
+	"es": {
+		Months:           [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		ShortMonths:      [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		Days:             [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		ShortDays:        [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		Am:               "a." + nbsp + "m.",
+		Pm:               "p." + nbsp + "m.",
+		Hour:             'H',
+		DatePatterns:     styles("EEEE, d 'de' MMMM 'de' y", "d 'de' MMMM 'de' y", "d MMM y", "d/M/yy"),
+		TimePatterns:     timeStyles("H:mm:ss z", "H:mm:ss", "H:mm"),
+		DateTimePatterns: joinAll("{1}, {0}"),
+		Skeletons:        skeletons("d/M/y", "d MMM y", "d 'de' MMMM 'de' y", "EEE, d MMM y", "MMM y", "MMMM 'de' y", "d MMM", "d 'de' MMMM", "EEE, d/M", "d/M"),
+	},
+	"de": {
+		Months:           [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		ShortMonths:      [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+		Days:             [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		ShortDays:        [7]string{"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."},
+		Am:               "AM",
+		Pm:               "PM",
+		Hour:             'H',
+		DatePatterns:     styles("EEEE, d. MMMM y", "d. MMMM y", "dd.MM.y", "dd.MM.yy"),
+		TimePatterns:     times24,
+		DateTimePatterns: styles("{1} 'um' {0}", "{1} 'um' {0}", "{1}, {0}", "{1}, {0}"),
+		Skeletons:        skeletons("d.M.y", "d. MMM y", "d. MMMM y", "EEE, d. MMM y", "MMM y", "MMMM y", "d. MMM", "d. MMMM", "EEE, d.M.", "d.M."),
+	},
+	"fr": {
+		Months:           [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		ShortMonths:      [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		Days:             [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		ShortDays:        [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+		Am:               "AM",
+		Pm:               "PM",
+		Hour:             'H',
+		DatePatterns:     styles("EEEE d MMMM y", "d MMMM y", "d MMM y", "dd/MM/y"),
+		TimePatterns:     times24,
+		DateTimePatterns: styles("{1} 'à' {0}", "{1} 'à' {0}", "{1} {0}", "{1} {0}"),
+		Skeletons:        skeletons("dd/MM/y", "d MMM y", "d MMMM y", "EEE d MMM y", "MMM y", "MMMM y", "d MMM", "d MMMM", "EEE dd/MM", "dd/MM"),
+	},
+	"it": {
+		Months:           [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		ShortMonths:      [12]string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+		Days:             [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		ShortDays:        [7]string{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+		Am:               "AM",
+		Pm:               "PM",
+		Hour:             'H',
+		DatePatterns:     styles("EEEE d MMMM y", "d MMMM y", "d MMM y", "dd/MM/yy"),
+		TimePatterns:     times24,
+		DateTimePatterns: joinAll("{1}, {0}"),
+		Skeletons:        skeletons("d/M/y", "d MMM y", "d MMMM y", "EEE d MMM y", "MMM y", "MMMM y", "d MMM", "d MMMM", "EEE d/M", "d/M"),
+	},
+	"pt": {
+		Months:           [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		ShortMonths:      [12]string{"jan.", "fev.", "mar.", "abr.", "mai.", "jun.", "jul.", "ago.", "set.", "out.", "nov.", "dez."},
+		Days:             [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+		ShortDays:        [7]string{"dom.", "seg.", "ter.", "qua.", "qui.", "sex.", "sáb."},
+		Am:               "AM",
+		Pm:               "PM",
+		Hour:             'H',
+		DatePatterns:     styles("EEEE, d 'de' MMMM 'de' y", "d 'de' MMMM 'de' y", "d 'de' MMM 'de' y", "dd/MM/y"),
+		TimePatterns:     times24,
+		DateTimePatterns: joinAll("{1} {0}"),
+		Skeletons:        skeletons("dd/MM/y", "d 'de' MMM 'de' y", "d 'de' MMMM 'de' y", "EEE, d 'de' MMM 'de' y", "MMM 'de' y", "MMMM 'de' y", "d 'de' MMM", "d 'de' MMMM", "EEE, dd/MM", "d/M"),
+	},
+	"nl": {
+		Months:           [12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+		ShortMonths:      [12]string{"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+		Days:             [7]string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+		ShortDays:        [7]string{"zo", "ma", "di", "wo", "do", "vr", "za"},
+		Am:               "a.m.",
+		Pm:               "p.m.",
+		Hour:             'H',
+		DatePatterns:     styles("EEEE d MMMM y", "d MMMM y", "d MMM y", "dd-MM-y"),
+		TimePatterns:     times24,
+		DateTimePatterns: styles("{1} 'om' {0}", "{1} 'om' {0}", "{1} {0}", "{1} {0}"),
+		Skeletons:        skeletons("d-M-y", "d MMM y", "d MMMM y", "EEE d MMM y", "MMM y", "MMMM y", "d MMM", "d MMMM", "EEE d-M", "d-M"),
+	},
+	"ja": {
+		Months:           [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		ShortMonths:      [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		Days:             [7]string{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日"},
+		ShortDays:        [7]string{"日", "月", "火", "水", "木", "金", "土"},
+		Am:               "午前",
+		Pm:               "午後",
+		Hour:             'H',
+		DatePatterns:     styles("y年M月d日EEEE", "y年M月d日", "y/MM/dd", "y/MM/dd"),
+		TimePatterns:     timeStyles("H:mm:ss z", "H:mm:ss", "H:mm"),
+		DateTimePatterns: joinAll("{1} {0}"),
+		Skeletons: map[string]string{
+			"yMd": "y/M/d", "yMMMd": "y年M月d日", "yMMMMd": "y年M月d日", "yMMMEd": "y年M月d日(EEE)", "yMMM": "y年M月",
+			"yMMMM": "y年M月", "MMMd": "M月d日", "MMMMd": "M月d日", "MEd": "M/d(EEE)", "Md": "M/d",
+			"Hm": "H:mm", "hm": "aK:mm", "Hms": "H:mm:ss", "hms": "aK:mm:ss",
+		},
+	},
+}
+
+// Dates returns the calendar data of the language. If the language is not known the root data is returned
+func Dates(tag string) (*DateSymbols, bool) {
+	for _, lookup := range lookupTags(tag) {
+		if symbols, found := dateSymbols[lookup]; found {
+			withId := *symbols
+			withId.Id = lookup
+			return &withId, true
+		}
+	}
+	return rootDates, false
+}
+
+// CheckDateFormat reports if the format of a date, time or datetime argument is not a style or a known skeleton
+func CheckDateFormat(format string) error {
+	if format == "" || slices.Contains(DateStyles, format) || slices.Contains(DateSkeletons, strings.ReplaceAll(format, "j", "H")) {
+		return nil
+	}
+	return ErrInvalidDateFormat.WithArgs(strings.Join(DateSkeletons, ", "))
+}
+
+// DatePattern returns the pattern of the language for a date, time or datetime argument with the format, which
+// must be valid. Skeletons give the same pattern for all the kinds
+func (s *DateSymbols) DatePattern(kind, format string) string {
+	if format == "" {
+		format = DefaultDateStyle
+	}
+	if !slices.Contains(DateStyles, format) {
+		return s.Skeletons[strings.ReplaceAll(format, "j", string(s.Hour))]
+	}
+	switch kind {
+	case "date":
+		return s.DatePatterns[format]
+	case "time":
+		return s.TimePatterns[format]
+	default:
+		return strings.NewReplacer("{1}", s.DatePatterns[format], "{0}", s.TimePatterns[format]).Replace(s.DateTimePatterns[format])
+	}
+}
@@ -0,0 +1,139 @@
+package cldr
+
+import (
+	"strings"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+var (
+	ErrInvalidDurationFormat util.Error = util.MakeError("expected an empty format, durations and relative times have no formats")
+)
+
+// TimeUnitNames are the units durations and relative times are written in, from the smallest to the largest
+var TimeUnitNames = []string{"second", "minute", "hour", "day", "week", "month", "year"}
+
+// TimeUnits are the CLDR patterns of a language to write an amount of a unit like "{0} minutes" and the time relative
+// to now like "in {0} minutes" or "{0} minutes ago". Each pattern has the unit of TimeUnitNames at its index and is
+// given by plural category, the categories the language does not give use the other one
+type TimeUnits struct {
+	Id       string
+	Duration [7]map[string]string
+	Future   [7]map[string]string
+	Past     [7]map[string]string
+	// Now is the relative time of less than a second
+	Now string
+}
+
+// unitNames are the one and other forms of the names of each unit of TimeUnitNames
+type unitNames [7][2]string
+
+// unitPatterns returns the patterns of each unit replacing {unit} in the pattern with its names
+func unitPatterns(pattern string, names unitNames) [7]map[string]string {
+	var patterns [7]map[string]string
+	for i, name := range names {
+		patterns[i] = map[string]string{
+			"one":   strings.ReplaceAll(pattern, "{unit}", name[0]),
+			"other": strings.ReplaceAll(pattern, "{unit}", name[1]),
+		}
+	}
+	return patterns
+}
+
+// sameNames returns the names of the languages whose units have the same name in all the plural categories
+func sameNames(second, minute, hour, day, week, month, year string) unitNames {
+	return unitNames{{second, second}, {minute, minute}, {hour, hour}, {day, day}, {week, week}, {month, month}, {year, year}}
+}
+
+var rootTimeUnits = &TimeUnits{
+	Id:       "root",
+	Duration: unitPatterns("{0} {unit}", sameNames("s", "min", "h", "d", "w", "m", "y")),
+	Future:   unitPatterns("+{0} {unit}", sameNames("s", "min", "h", "d", "w", "m", "y")),
+	Past:     unitPatterns("-{0} {unit}", sameNames("s", "min", "h", "d", "w", "m", "y")),
+	Now:      "now",
+}
+
+var (
+	englishUnits    = unitNames{{"second", "seconds"}, {"minute", "minutes"}, {"hour", "hours"}, {"day", "days"}, {"week", "weeks"}, {"month", "months"}, {"year", "years"}}
+	spanishUnits    = unitNames{{"segundo", "segundos"}, {"minuto", "minutos"}, {"hora", "horas"}, {"día", "días"}, {"semana", "semanas"}, {"mes", "meses"}, {"año", "años"}}
+	germanUnits     = unitNames{{"Sekunde", "Sekunden"}, {"Minute", "Minuten"}, {"Stunde", "Stunden"}, {"Tag", "Tage"}, {"Woche", "Wochen"}, {"Monat", "Monate"}, {"Jahr", "Jahre"}}
+	germanDative    = unitNames{{"Sekunde", "Sekunden"}, {"Minute", "Minuten"}, {"Stunde", "Stunden"}, {"Tag", "Tagen"}, {"Woche", "Wochen"}, {"Monat", "Monaten"}, {"Jahr", "Jahren"}}
+	frenchUnits     = unitNames{{"seconde", "secondes"}, {"minute", "minutes"}, {"heure", "heures"}, {"jour", "jours"}, {"semaine", "semaines"}, {"mois", "mois"}, {"an", "ans"}}
+	italianUnits    = unitNames{{"secondo", "secondi"}, {"minuto", "minuti"}, {"ora", "ore"}, {"giorno", "giorni"}, {"settimana", "settimane"}, {"mese", "mesi"}, {"anno", "anni"}}
+	portugueseUnits = unitNames{{"segundo", "segundos"}, {"minuto", "minutos"}, {"hora", "horas"}, {"dia", "dias"}, {"semana", "semanas"}, {"mês", "meses"}, {"ano", "anos"}}
+	dutchUnits      = unitNames{{"seconde", "seconden"}, {"minuut", "minuten"}, {"uur", "uur"}, {"dag", "dagen"}, {"week", "weken"}, {"maand", "maanden"}, {"jaar", "jaar"}}
+	japaneseUnits   = sameNames("秒", "分", "時間", "日", "週間", "か月", "年")
+)
+
+// timeUnits holds the CLDR unit and relative time patterns by language
+var timeUnits = map[string]*TimeUnits{
+	"en": {
+		Duration: unitPatterns("{0} {unit}", englishUnits),
+		Future:   unitPatterns("in {0} {unit}", englishUnits),
+		Past:     unitPatterns("{0} {unit} ago", englishUnits),
+		Now:      "now",
+	},
+	"es": {
+		Duration: unitPatterns("{0} {unit}", spanishUnits),
+		Future:   unitPatterns("dentro de {0} {unit}", spanishUnits),
+		Past:     unitPatterns("hace {0} {unit}", spanishUnits),
+		Now:      "ahora",
+	},
+	"de": {
+		Duration: unitPatterns("{0} {unit}", germanUnits),
+		Future:   unitPatterns("in {0} {unit}", germanDative),
+		Past:     unitPatterns("vor {0} {unit}", germanDative),
+		Now:      "jetzt",
+	},
+	"fr": {
+		Duration: unitPatterns("{0} {unit}", frenchUnits),
+		Future:   unitPatterns("dans {0} {unit}", frenchUnits),
+		Past:     unitPatterns("il y a {0} {unit}", frenchUnits),
+		Now:      "maintenant",
+	},
+	"it": {
+		Duration: unitPatterns("{0} {unit}", italianUnits),
+		Future:   unitPatterns("tra {0} {unit}", italianUnits),
+		Past:     unitPatterns("{0} {unit} fa", italianUnits),
+		Now:      "ora",
+	},
+	"pt": {
+		Duration: unitPatterns("{0} {unit}", portugueseUnits),
+		Future:   unitPatterns("em {0} {unit}", portugueseUnits),
+		Past:     unitPatterns("há {0} {unit}", portugueseUnits),
+		Now:      "agora",
+	},
+	"nl": {
+		Duration: unitPatterns("{0} {unit}", dutchUnits),
+		Future:   unitPatterns("over {0} {unit}", dutchUnits),
+		Past:     unitPatterns("{0} {unit} geleden", dutchUnits),
+		Now:      "nu",
+	},
+	"ja": {
+		Duration: unitPatterns("{0} {unit}", japaneseUnits),
+		Future:   unitPatterns("{0} {unit}後", japaneseUnits),
+		Past:     unitPatterns("{0} {unit}前", japaneseUnits),
+		Now:      "今",
+	},
+}
+
+// Units returns the unit and relative time patterns of the language. If the language is not known the root patterns
+// are returned
+func Units(tag string) (*TimeUnits, bool) {
+	for _, lookup := range lookupTags(tag) {
+		if units, found := timeUnits[lookup]; found {
+			withId := *units
+			withId.Id = lookup
+			return &withId, true
+		}
+	}
+	return rootTimeUnits, false
+}
+
+// CheckDurationFormat reports if a duration or reltime argument has a format
+func CheckDurationFormat(format string) error {
+	if format != "" {
+		return ErrInvalidDurationFormat
+	}
+	return nil
+}
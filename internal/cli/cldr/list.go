@@ -0,0 +1,82 @@
+package cldr
+
+import (
+	"slices"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+var (
+	ErrInvalidListFormat util.Error = util.MakeError("expected an empty format, and, or or unit")
+)
+
+// ListStyles are the styles of the lists, the first one is used by list arguments without format
+var ListStyles = []string{"and", "or", "unit"}
+
+// ListPattern are the CLDR patterns that join the items of a list. Start joins the first item with the rest, Middle
+// the items in between, End the last two items and Two the items of lists with only two
+type ListPattern struct {
+	Start  string
+	Middle string
+	End    string
+	Two    string
+}
+
+// ListPatterns are the list patterns of a language by style
+type ListPatterns struct {
+	Id   string
+	And  ListPattern
+	Or   ListPattern
+	Unit ListPattern
+}
+
+// joined returns the list pattern whose items are joined with the separator except the last ones, joined with last
+func joined(separator, last string) ListPattern {
+	return ListPattern{Start: "{0}" + separator + "{1}", Middle: "{0}" + separator + "{1}", End: "{0}" + last + "{1}", Two: "{0}" + last + "{1}"}
+}
+
+var rootLists = &ListPatterns{Id: "root", And: joined(", ", ", "), Or: joined(", ", " or "), Unit: joined(", ", ", ")}
+
+// listPatterns holds the CLDR list patterns by language
+var listPatterns = map[string]*ListPatterns{
+	"en": {
+		And:  ListPattern{Start: "{0}, {1}", Middle: "{0}, {1}", End: "{0}, and {1}", Two: "{0} and {1}"},
+		Or:   ListPattern{Start: "{0}, {1}", Middle: "{0}, {1}", End: "{0}, or {1}", Two: "{0} or {1}"},
+		Unit: joined(", ", ", "),
+	},
+	"en-GB": {And: joined(", ", " and "), Or: joined(", ", " or "), Unit: joined(", ", ", ")},
+	"es":    {And: joined(", ", " y "), Or: joined(", ", " o "), Unit: joined(", ", " y ")},
+	"de":    {And: joined(", ", " und "), Or: joined(", ", " oder "), Unit: joined(", ", " und ")},
+	"fr":    {And: joined(", ", " et "), Or: joined(", ", " ou "), Unit: joined(", ", " et ")},
+	"it":    {And: joined(", ", " e "), Or: joined(", ", " o "), Unit: joined(", ", " e ")},
+	"pt":    {And: joined(", ", " e "), Or: joined(", ", " ou "), Unit: joined(", ", " e ")},
+	"nl":    {And: joined(", ", " en "), Or: joined(", ", " of "), Unit: joined(", ", " en ")},
+	"ja": {
+		And:  joined("、", "、"),
+		Or:   ListPattern{Start: "{0}、{1}", Middle: "{0}、{1}", End: "{0}、または{1}", Two: "{0}または{1}"},
+		Unit: joined(" ", " "),
+	},
+}
+
+// Lists returns the list patterns of the language. If the language is not known the root patterns are returned
+func Lists(tag string) (*ListPatterns, bool) {
+	for _, lookup := range lookupTags(tag) {
+		if patterns, found := listPatterns[lookup]; found {
+			withId := *patterns
+			withId.Id = lookup
+			return &withId, true
+		}
+	}
+	return rootLists, false
+}
+
+// ParseListFormat returns the style of the format of a list argument
+func ParseListFormat(format string) (string, error) {
+	if format == "" {
+		return ListStyles[0], nil
+	}
+	if slices.Contains(ListStyles, format) {
+		return format, nil
+	}
+	return "", ErrInvalidListFormat
+}
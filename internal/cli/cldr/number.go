@@ -0,0 +1,251 @@
+package cldr
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+var (
+	ErrInvalidNumberFormat   util.Error = util.MakeError("expected an empty format, `integer` or `.N` to show N fraction digits")
+	ErrInvalidPercentFormat             = util.MakeError("expected an empty format or `.N` to show N fraction digits")
+	ErrInvalidCurrencyFormat            = util.MakeError("expected the ISO 4217 code of the currency like `EUR`, optionally followed by `.N` to show N fraction digits")
+)
+
+// NumberSymbols are the CLDR symbols and patterns used to format the numbers of a language with the latin digits
+type NumberSymbols struct {
+	Id      string
+	Decimal string
+	Group   string
+	Minus   string
+	// NaN is written for the numbers that are not a number, the one of the root symbols if empty
+	NaN string
+	// MinGrouping is the minimum amount of digits before the first group separator
+	MinGrouping int
+	// DecimalPattern only defines the sizes of the groups, the fraction digits are given by the format of the argument
+	DecimalPattern  string
+	PercentPattern  string
+	CurrencyPattern string
+}
+
+const (
+	nbsp      = "\u00a0"
+	nnbsp     = "\u202f"
+	minusSign = "\u2212"
+
+	// Infinity is the CLDR symbol of the infinity in every language with the latin digits
+	Infinity = "∞"
+
+	patternDecimal  = "#,##0.###"
+	patternIndian   = "#,##,##0.###"
+	percentAttached = "#,##0%"
+	percentSpaced   = "#,##0" + nbsp + "%"
+	currencyBefore  = "¤#,##0.00"
+	currencySpaced  = "¤" + nbsp + "#,##0.00"
+	currencyAfter   = "#,##0.00" + nbsp + "¤"
+)
+
+var rootNumbers = &NumberSymbols{Id: "root", Decimal: ".", Group: ",", Minus: "-", NaN: "NaN", MinGrouping: 1,
+	DecimalPattern: patternDecimal, PercentPattern: percentAttached, CurrencyPattern: currencySpaced}
+
+func numbers(decimal, group, minus string, minGrouping int, percent, currency string) *NumberSymbols {
+	return &NumberSymbols{Decimal: decimal, Group: group, Minus: minus, MinGrouping: minGrouping,
+		DecimalPattern: patternDecimal, PercentPattern: percent, CurrencyPattern: currency}
+}
+
+func withNaN(symbols *NumberSymbols, nan string) *NumberSymbols {
+	symbols.NaN = nan
+	return symbols
+}
+
+// numberSymbols holds the CLDR number symbols by language
+var numberSymbols = map[string]*NumberSymbols{
+	"en": numbers(".", ",", "-", 1, percentAttached, currencyBefore),
+	"ja": numbers(".", ",", "-", 1, percentAttached, currencyBefore),
+	"ko": numbers(".", ",", "-", 1, percentAttached, currencyBefore),
+	"zh": numbers(".", ",", "-", 1, percentAttached, currencyBefore),
+	"th": numbers(".", ",", "-", 1, percentAttached, currencyBefore),
+	"id": numbers(",", ".", "-", 1, percentAttached, currencyBefore),
+	"tr": numbers(",", ".", "-", 1, "%#,##0", currencyBefore),
+	"nl": numbers(",", ".", "-", 1, percentAttached, currencySpaced),
+	"pt": numbers(",", ".", "-", 1, percentAttached, currencySpaced),
+	"it": numbers(",", ".", "-", 1, percentAttached, currencyAfter),
+	"el": numbers(",", ".", "-", 1, percentAttached, currencyAfter),
+	"de": numbers(",", ".", "-", 1, percentSpaced, currencyAfter),
+	"da": numbers(",", ".", "-", 1, percentSpaced, currencyAfter),
+	"ca": numbers(",", ".", "-", 1, percentSpaced, currencyAfter),
+	"ro": numbers(",", ".", "-", 1, percentSpaced, currencyAfter),
+	"es": numbers(",", ".", "-", 2, percentSpaced, currencyAfter),
+	"fr": numbers(",", nnbsp, "-", 1, "#,##0"+nnbsp+"%", currencyAfter),
+	"cs": numbers(",", nbsp, "-", 1, percentSpaced, currencyAfter),
+	"sk": numbers(",", nbsp, "-", 1, percentSpaced, currencyAfter),
+	"ru": withNaN(numbers(",", nbsp, "-", 1, percentSpaced, currencyAfter), "не число"),
+	"uk": numbers(",", nbsp, "-", 1, percentAttached, currencyAfter),
+	"hu": numbers(",", nbsp, "-", 1, percentAttached, currencyAfter),
+	"pl": numbers(",", nbsp, "-", 2, percentAttached, currencyAfter),
+	"fi": withNaN(numbers(",", nbsp, minusSign, 1, percentSpaced, currencyAfter), "epäluku"),
+	"nb": numbers(",", nbsp, minusSign, 1, percentSpaced, currencyAfter),
+	"no": numbers(",", nbsp, minusSign, 1, percentSpaced, currencyAfter),
+	"sv": numbers(",", nbsp, minusSign, 1, percentSpaced, currencyAfter),
+
+	"de-AT": numbers(",", nbsp, "-", 1, percentSpaced, currencySpaced),
+	"de-CH": numbers(".", "\u2019", "-", 1, percentAttached, currencySpaced),
+	"es-MX": numbers(".", ",", "-", 1, percentSpaced, currencyBefore),
+	"es-US": numbers(".", ",", "-", 1, percentSpaced, currencyBefore),
+	"pt-PT": numbers(",", nbsp, "-", 2, percentAttached, currencyAfter),
+	"en-IN": {Decimal: ".", Group: ",", Minus: "-", MinGrouping: 1,
+		DecimalPattern: patternIndian, PercentPattern: "#,##,##0%", CurrencyPattern: "¤#,##,##0.00"},
+	"hi": {Decimal: ".", Group: ",", Minus: "-", MinGrouping: 1,
+		DecimalPattern: patternIndian, PercentPattern: "#,##,##0%", CurrencyPattern: "¤#,##,##0.00"},
+}
+
+// Numbers returns the number symbols of the language. If the language is not known the root symbols are returned
+func Numbers(tag string) (*NumberSymbols, bool) {
+	for _, lookup := range lookupTags(tag) {
+		if symbols, found := numberSymbols[lookup]; found {
+			withId := *symbols
+			withId.Id = lookup
+			if withId.NaN == "" {
+				withId.NaN = rootNumbers.NaN
+			}
+			return &withId, true
+		}
+	}
+	return rootNumbers, false
+}
+
+// Groups returns the size of the group closest to the decimal separator and the size of the rest of groups
+func (s *NumberSymbols) Groups() (primary int, secondary int) {
+	integer, _, _ := strings.Cut(s.DecimalPattern, ".")
+	groups := strings.Split(integer, ",")
+	primary = len(groups[len(groups)-1])
+	secondary = primary
+	if len(groups) > 2 {
+		secondary = len(groups[len(groups)-2])
+	}
+	return
+}
+
+// PercentAffixes returns the text before and after the number of a percentage
+func (s *NumberSymbols) PercentAffixes() (string, string) { return affixes(s.PercentPattern) }
+
+// CurrencyAffixes returns the text before and after the number of an amount of money, with ¤ in place of the symbol
+func (s *NumberSymbols) CurrencyAffixes() (string, string) { return affixes(s.CurrencyPattern) }
+
+func affixes(pattern string) (string, string) {
+	start := strings.IndexAny(pattern, "#0")
+	end := strings.LastIndexAny(pattern, "#0") + 1
+	return pattern[:start], pattern[end:]
+}
+
+// currencySymbols holds the CLDR symbol of the currencies whose code is not used as symbol
+var currencySymbols = map[string]string{
+	"USD": "$", "EUR": "€", "GBP": "£", "JPY": "¥", "CNY": "CN¥", "INR": "₹", "KRW": "₩", "BRL": "R$", "CAD": "CA$",
+	"AUD": "A$", "MXN": "MX$", "NZD": "NZ$", "HKD": "HK$", "TWD": "NT$", "ILS": "₪", "VND": "₫", "PHP": "₱",
+	"XAF": "FCFA", "XOF": "F CFA",
+}
+
+// localCurrencySymbols holds the symbols of the currencies that languages write differently than the rest
+var localCurrencySymbols = map[string]map[string]string{
+	"ja":    {"JPY": "￥", "CNY": "元"},
+	"zh":    {"CNY": "¥", "JPY": "JP¥", "USD": "US$"},
+	"ko":    {"KRW": "₩", "USD": "US$"},
+	"en-IN": {"INR": "₹"},
+	"en-CA": {"CAD": "$", "USD": "US$"},
+	"en-AU": {"AUD": "$", "USD": "US$"},
+	"es":    {"USD": "US$"},
+	"es-MX": {"MXN": "$", "USD": "USD"},
+	"es-US": {"USD": "$"},
+	"fr":    {"USD": "$US", "CAD": "$CA"},
+	"pt":    {"USD": "US$", "BRL": "R$"},
+	"pt-PT": {"USD": "US$"},
+	"sv":    {"SEK": "kr", "USD": "US$"},
+	"da":    {"DKK": "kr.", "USD": "US$"},
+	"nb":    {"NOK": "kr", "USD": "USD"},
+	"no":    {"NOK": "kr", "USD": "USD"},
+	"pl":    {"PLN": "zł", "USD": "USD"},
+	"cs":    {"CZK": "Kč", "USD": "US$"},
+	"hu":    {"HUF": "Ft", "USD": "USD"},
+	"ru":    {"RUB": "₽", "USD": "$"},
+	"uk":    {"UAH": "₴", "USD": "USD"},
+	"tr":    {"TRY": "₺", "USD": "$"},
+	"ro":    {"USD": "USD"},
+	"th":    {"THB": "฿", "USD": "US$"},
+	"id":    {"IDR": "Rp", "USD": "US$"},
+}
+
+// currencyDigits holds the fraction digits of the currencies that do not use 2
+var currencyDigits = map[string]int{
+	"JPY": 0, "KRW": 0, "VND": 0, "CLP": 0, "ISK": 0, "PYG": 0, "UGX": 0, "XAF": 0, "XOF": 0,
+	"BHD": 3, "JOD": 3, "KWD": 3, "OMR": 3, "TND": 3,
+}
+
+// CurrencySymbol returns the symbol the language uses for the currency, the code if the currency has no symbol
+func CurrencySymbol(tag, code string) string {
+	for _, lookup := range lookupTags(tag) {
+		if symbol, found := localCurrencySymbols[lookup][code]; found {
+			return symbol
+		}
+	}
+	if symbol, found := currencySymbols[code]; found {
+		return symbol
+	}
+	return code
+}
+
+// CurrencyDigits returns the amount of fraction digits the amounts of the currency are written with
+func CurrencyDigits(code string) int {
+	if digits, found := currencyDigits[code]; found {
+		return digits
+	}
+	return 2
+}
+
+var (
+	fractionFormat = regexp.MustCompile(`^\.(\d{1,2})$`)
+	currencyFormat = regexp.MustCompile(`^([A-Z]{3})(?:\.(\d{1,2}))?$`)
+)
+
+// ParseNumberFormat returns the minimum and maximum fraction digits of the format of a number argument: empty for up
+// to 3, `integer` for none and `.N` for exactly N
+func ParseNumberFormat(format string) (int, int, error) {
+	switch format {
+	case "":
+		return 0, 3, nil
+	case "integer":
+		return 0, 0, nil
+	}
+	if groups := fractionFormat.FindStringSubmatch(format); groups != nil {
+		digits, _ := strconv.Atoi(groups[1])
+		return digits, digits, nil
+	}
+	return 0, 0, ErrInvalidNumberFormat
+}
+
+// ParsePercentFormat returns the fraction digits of the format of a percent argument: empty for none and `.N` for N
+func ParsePercentFormat(format string) (int, error) {
+	if format == "" {
+		return 0, nil
+	}
+	if groups := fractionFormat.FindStringSubmatch(format); groups != nil {
+		digits, _ := strconv.Atoi(groups[1])
+		return digits, nil
+	}
+	return 0, ErrInvalidPercentFormat
+}
+
+// ParseCurrencyFormat returns the currency code and the fraction digits of the format of a currency argument,
+// `EUR` for the digits of the currency and `EUR.N` for N
+func ParseCurrencyFormat(format string) (string, int, error) {
+	groups := currencyFormat.FindStringSubmatch(format)
+	if groups == nil {
+		return "", 0, ErrInvalidCurrencyFormat
+	}
+	if groups[2] == "" {
+		return groups[1], CurrencyDigits(groups[1]), nil
+	}
+	digits, _ := strconv.Atoi(groups[2])
+	return groups[1], digits, nil
+}
@@ -0,0 +1,125 @@
+package cldr
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNumbers(t *testing.T) {
+	tests := []struct {
+		lang                     string
+		id                       string
+		decimal, group, minus    string
+		nan                      string
+		primary, secondary       int
+		percentPre, percentPos   string
+		currencyPre, currencyPos string
+	}{
+		{lang: "en-US", id: "en", decimal: ".", group: ",", minus: "-", nan: "NaN", primary: 3, secondary: 3, percentPos: "%", currencyPre: "¤"},
+		{lang: "de", id: "de", decimal: ",", group: ".", minus: "-", nan: "NaN", primary: 3, secondary: 3, percentPos: nbsp + "%", currencyPos: nbsp + "¤"},
+		{lang: "de_AT", id: "de-AT", decimal: ",", group: nbsp, minus: "-", nan: "NaN", primary: 3, secondary: 3, percentPos: nbsp + "%", currencyPre: "¤" + nbsp},
+		{lang: "tr", id: "tr", decimal: ",", group: ".", minus: "-", nan: "NaN", primary: 3, secondary: 3, percentPre: "%", currencyPre: "¤"},
+		{lang: "fi", id: "fi", decimal: ",", group: nbsp, minus: minusSign, nan: "epäluku", primary: 3, secondary: 3, percentPos: nbsp + "%", currencyPos: nbsp + "¤"},
+		{lang: "en-IN", id: "en-IN", decimal: ".", group: ",", minus: "-", nan: "NaN", primary: 3, secondary: 2, percentPos: "%", currencyPre: "¤"},
+		{lang: "xx", id: "root", decimal: ".", group: ",", minus: "-", nan: "NaN", primary: 3, secondary: 3, percentPos: "%", currencyPre: "¤" + nbsp},
+	}
+	for _, test := range tests {
+		t.Run(test.lang, func(t *testing.T) {
+			symbols, found := Numbers(test.lang)
+			if found != (test.id != "root") || symbols.Id != test.id {
+				t.Fatalf("expected the symbols of %s, got the ones of %s", test.id, symbols.Id)
+			}
+			if symbols.Decimal != test.decimal || symbols.Group != test.group || symbols.Minus != test.minus || symbols.NaN != test.nan {
+				t.Errorf("expected the symbols %q %q %q %q, got %q %q %q %q", test.decimal, test.group, test.minus, test.nan,
+					symbols.Decimal, symbols.Group, symbols.Minus, symbols.NaN)
+			}
+			if primary, secondary := symbols.Groups(); primary != test.primary || secondary != test.secondary {
+				t.Errorf("expected the groups %d and %d, got %d and %d", test.primary, test.secondary, primary, secondary)
+			}
+			if pre, pos := symbols.PercentAffixes(); pre != test.percentPre || pos != test.percentPos {
+				t.Errorf("expected the percent affixes %q and %q, got %q and %q", test.percentPre, test.percentPos, pre, pos)
+			}
+			if pre, pos := symbols.CurrencyAffixes(); pre != test.currencyPre || pos != test.currencyPos {
+				t.Errorf("expected the currency affixes %q and %q, got %q and %q", test.currencyPre, test.currencyPos, pre, pos)
+			}
+		})
+	}
+}
+
+func TestCurrencySymbol(t *testing.T) {
+	tests := []struct {
+		lang, code, want string
+		digits           int
+	}{
+		{lang: "en", code: "EUR", want: "€", digits: 2},
+		{lang: "en", code: "USD", want: "$", digits: 2},
+		{lang: "es", code: "USD", want: "US$", digits: 2},
+		{lang: "es-US", code: "USD", want: "$", digits: 2},
+		{lang: "es-MX", code: "USD", want: "USD", digits: 2},
+		{lang: "ja", code: "JPY", want: "￥", digits: 0},
+		{lang: "en", code: "KWD", want: "KWD", digits: 3},
+	}
+	for _, test := range tests {
+		t.Run(test.lang+" "+test.code, func(t *testing.T) {
+			if symbol := CurrencySymbol(test.lang, test.code); symbol != test.want {
+				t.Errorf("expected the symbol %s, got %s", test.want, symbol)
+			}
+			if digits := CurrencyDigits(test.code); digits != test.digits {
+				t.Errorf("expected %d fraction digits, got %d", test.digits, digits)
+			}
+		})
+	}
+}
+
+func TestParseNumberFormats(t *testing.T) {
+	tests := []struct {
+		kind     string
+		format   string
+		code     string
+		min, max int
+		err      error
+	}{
+		{kind: "number", format: "", min: 0, max: 3},
+		{kind: "number", format: "integer", min: 0, max: 0},
+		{kind: "number", format: ".2", min: 2, max: 2},
+		{kind: "number", format: ".123", err: ErrInvalidNumberFormat},
+		{kind: "number", format: "2", err: ErrInvalidNumberFormat},
+		{kind: "percent", format: "", min: 0, max: 0},
+		{kind: "percent", format: ".1", min: 1, max: 1},
+		{kind: "percent", format: "integer", err: ErrInvalidPercentFormat},
+		{kind: "currency", format: "EUR", code: "EUR", min: 2, max: 2},
+		{kind: "currency", format: "JPY", code: "JPY", min: 0, max: 0},
+		{kind: "currency", format: "EUR.0", code: "EUR", min: 0, max: 0},
+		{kind: "currency", format: "", err: ErrInvalidCurrencyFormat},
+		{kind: "currency", format: "eur", err: ErrInvalidCurrencyFormat},
+	}
+	for _, test := range tests {
+		t.Run(test.kind+" "+test.format, func(t *testing.T) {
+			var code string
+			var min, max int
+			var err error
+			switch test.kind {
+			case "number":
+				min, max, err = ParseNumberFormat(test.format)
+			case "percent":
+				min, err = ParsePercentFormat(test.format)
+				max = min
+			case "currency":
+				code, min, err = ParseCurrencyFormat(test.format)
+				max = min
+			}
+			if test.err != nil {
+				if !errors.Is(err, test.err) {
+					t.Errorf("expected %v, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if code != test.code || min != test.min || max != test.max {
+				t.Errorf("expected %q with %d to %d digits, got %q with %d to %d", test.code, test.min, test.max, code, min, max)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package cldr
+
+import (
+	"regexp"
+	"strings"
+)
+
+// PluralRule is a CLDR plural rule written as a go boolean expression over the plural operands
+// n (absolute value, float64), i (integer digits), v (amount of visible fraction digits),
+// f (visible fraction digits) and t (visible fraction digits without trailing zeros), all of them int64
+type PluralRule struct {
+	Category  string
+	Condition string
+}
+
+// PluralRules are the rules of a language, checked in order. If none matches the category is "other"
+type PluralRules struct {
+	Id    string
+	Rules []PluralRule
+}
+
+var operandExtractor = regexp.MustCompile(`\b[nivft]\b`)
+
+// Operands returns which of the operands n, i, v, f and t are used by the rules
+func (r *PluralRules) Operands() map[string]bool {
+	used := make(map[string]bool)
+	for _, rule := range r.Rules {
+		for _, op := range operandExtractor.FindAllString(rule.Condition, -1) {
+			used[op] = true
+		}
+	}
+	return used
+}
+
+// Categories returns the categories the language uses, always ending in "other"
+func (r *PluralRules) Categories() []string {
+	categories := make([]string, 0, len(r.Rules)+1)
+	for _, rule := range r.Rules {
+		categories = append(categories, rule.Category)
+	}
+	return append(categories, "other")
+}
+
+const (
+	ruleOneI1V0        = "i == 1 && v == 0"
+	ruleManyMillions   = "i != 0 && i%1000000 == 0 && v == 0"
+	ruleSlavicFew      = "v == 0 && i%10 >= 2 && i%10 <= 4 && (i%100 < 12 || i%100 > 14)"
+	ruleSerboCroatOne  = "(v == 0 && i%10 == 1 && i%100 != 11) || (f%10 == 1 && f%100 != 11)"
+	ruleSerboCroatFew  = "(v == 0 && i%10 >= 2 && i%10 <= 4 && (i%100 < 12 || i%100 > 14)) || (f%10 >= 2 && f%10 <= 4 && (f%100 < 12 || f%100 > 14))"
+	ruleEastSlavicOne  = "v == 0 && i%10 == 1 && i%100 != 11"
+	ruleEastSlavicMany = "v == 0 && (i%10 == 0 || (i%10 >= 5 && i%10 <= 9) || (i%100 >= 11 && i%100 <= 14))"
+)
+
+var (
+	rootRules       = &PluralRules{Id: "root"}
+	germanicRules   = []PluralRule{{"one", ruleOneI1V0}}
+	nOneRules       = []PluralRule{{"one", "n == 1"}}
+	eastSlavicRules = []PluralRule{{"one", ruleEastSlavicOne}, {"few", ruleSlavicFew}, {"many", ruleEastSlavicMany}}
+	hindiRules      = []PluralRule{{"one", "i == 0 || n == 1"}}
+	serboCroatRules = []PluralRule{{"one", ruleSerboCroatOne}, {"few", ruleSerboCroatFew}}
+)
+
+// cardinalRules holds the CLDR cardinal plural rules by language. The compact decimal exponent (e) is always 0
+var cardinalRules = map[string][]PluralRule{
+	"af": nOneRules, "az": nOneRules, "bg": nOneRules, "el": nOneRules, "eu": nOneRules, "hu": nOneRules,
+	"ka": nOneRules, "kk": nOneRules, "nb": nOneRules, "no": nOneRules, "sq": nOneRules, "sw": nOneRules,
+	"ta": nOneRules, "te": nOneRules, "tr": nOneRules, "uz": nOneRules,
+
+	"de": germanicRules, "en": germanicRules, "et": germanicRules, "fi": germanicRules, "gl": germanicRules,
+	"nl": germanicRules, "sv": germanicRules, "ur": germanicRules,
+
+	"am": hindiRules, "bn": hindiRules, "fa": hindiRules, "gu": hindiRules, "hi": hindiRules, "kn": hindiRules,
+	"zu": hindiRules,
+
+	"id": nil, "ja": nil, "km": nil, "ko": nil, "lo": nil, "ms": nil, "my": nil, "th": nil, "vi": nil, "zh": nil,
+
+	"be": eastSlavicRules, "ru": eastSlavicRules, "uk": eastSlavicRules,
+
+	"bs": serboCroatRules, "hr": serboCroatRules, "sr": serboCroatRules,
+
+	"ca": {{"one", ruleOneI1V0}, {"many", ruleManyMillions}},
+	"it": {{"one", ruleOneI1V0}, {"many", ruleManyMillions}},
+	"es": {{"one", "n == 1"}, {"many", ruleManyMillions}},
+	"fr": {{"one", "i == 0 || i == 1"}, {"many", ruleManyMillions}},
+	"pt": {{"one", "i == 0 || i == 1"}, {"many", ruleManyMillions}},
+
+	"pt-PT": {{"one", ruleOneI1V0}, {"many", ruleManyMillions}},
+
+	"da": {{"one", "n == 1 || (t != 0 && (i == 0 || i == 1))"}},
+	"is": {{"one", "(t == 0 && i%10 == 1 && i%100 != 11) || t != 0"}},
+	"pl": {
+		{"one", ruleOneI1V0},
+		{"few", ruleSlavicFew},
+		{"many", "v == 0 && ((i != 1 && i%10 <= 1) || (i%10 >= 5 && i%10 <= 9) || (i%100 >= 12 && i%100 <= 14))"},
+	},
+	"cs": {{"one", ruleOneI1V0}, {"few", "i >= 2 && i <= 4 && v == 0"}, {"many", "v != 0"}},
+	"sk": {{"one", ruleOneI1V0}, {"few", "i >= 2 && i <= 4 && v == 0"}, {"many", "v != 0"}},
+	"sl": {
+		{"one", "v == 0 && i%100 == 1"},
+		{"two", "v == 0 && i%100 == 2"},
+		{"few", "(v == 0 && i%100 >= 3 && i%100 <= 4) || v != 0"},
+	},
+	"ro": {{"one", ruleOneI1V0}, {"few", "v != 0 || n == 0 || (v == 0 && n != 1 && i%100 >= 1 && i%100 <= 19)"}},
+	"lt": {
+		{"one", "v == 0 && i%10 == 1 && (i%100 < 11 || i%100 > 19)"},
+		{"few", "v == 0 && i%10 >= 2 && (i%100 < 11 || i%100 > 19)"},
+		{"many", "f != 0"},
+	},
+	"lv": {
+		{"zero", "(v == 0 && (i%10 == 0 || (i%100 >= 11 && i%100 <= 19))) || (v == 2 && f%100 >= 11 && f%100 <= 19)"},
+		{"one", "(v == 0 && i%10 == 1 && i%100 != 11) || (v == 2 && f%10 == 1 && f%100 != 11) || (v != 2 && f%10 == 1)"},
+	},
+	"he": {{"one", "(i == 1 && v == 0) || (i == 0 && v != 0)"}, {"two", "i == 2 && v == 0"}},
+	"ar": {
+		{"zero", "n == 0"},
+		{"one", "n == 1"},
+		{"two", "n == 2"},
+		{"few", "v == 0 && i%100 >= 3 && i%100 <= 10"},
+		{"many", "v == 0 && i%100 >= 11 && i%100 <= 99"},
+	},
+	"ga": {{"one", "n == 1"}, {"two", "n == 2"}, {"few", "v == 0 && n >= 3 && n <= 6"}, {"many", "v == 0 && n >= 7 && n <= 10"}},
+	"cy": {{"zero", "n == 0"}, {"one", "n == 1"}, {"two", "n == 2"}, {"few", "n == 3"}, {"many", "n == 6"}},
+}
+
+// ordinalRules holds the CLDR ordinal plural rules by language. Ordinals are integers so the rules use i instead of n
+var ordinalRules = map[string][]PluralRule{
+	"bg": nil, "bs": nil, "cs": nil, "da": nil, "de": nil, "el": nil, "es": nil, "et": nil, "eu": nil, "fa": nil,
+	"fi": nil, "he": nil, "hr": nil, "id": nil, "is": nil, "ja": nil, "ko": nil, "lt": nil, "lv": nil, "nb": nil,
+	"nl": nil, "no": nil, "pl": nil, "pt": nil, "ru": nil, "sk": nil, "sl": nil, "sr": nil, "sw": nil, "ta": nil,
+	"te": nil, "th": nil, "tr": nil, "uk": nil, "ur": nil, "zh": nil, "ar": nil, "be": nil,
+
+	"en": {
+		{"one", "i%10 == 1 && i%100 != 11"},
+		{"two", "i%10 == 2 && i%100 != 12"},
+		{"few", "i%10 == 3 && i%100 != 13"},
+	},
+	"fr": {{"one", "i == 1"}},
+	"ga": {{"one", "i == 1"}},
+	"ms": {{"one", "i == 1"}},
+	"ro": {{"one", "i == 1"}},
+	"vi": {{"one", "i == 1"}},
+	"hu": {{"one", "i == 1 || i == 5"}},
+	"it": {{"many", "i == 11 || i == 8 || i == 80 || i == 800"}},
+	"sv": {{"one", "(i%10 == 1 || i%10 == 2) && i%100 != 11 && i%100 != 12"}},
+	"ca": {{"one", "i == 1 || i == 3"}, {"two", "i == 2"}, {"few", "i == 4"}},
+	"hi": {{"one", "i == 1"}, {"two", "i == 2 || i == 3"}, {"few", "i == 4"}, {"many", "i == 6"}},
+	"cy": {
+		{"zero", "i == 0 || i == 7 || i == 8 || i == 9"},
+		{"one", "i == 1"},
+		{"two", "i == 2"},
+		{"few", "i == 3 || i == 4"},
+		{"many", "i == 5 || i == 6"},
+	},
+}
+
+// BaseLanguage returns the language subtag of a tag like en-EN or en_EN
+func BaseLanguage(tag string) string {
+	tag = strings.ReplaceAll(tag, "_", "-")
+	if idx := strings.Index(tag, "-"); idx >= 0 {
+		return strings.ToLower(tag[:idx])
+	}
+	return strings.ToLower(tag)
+}
+
+// lookupTags returns the tags to look for in the CLDR data for a language, from most to least specific
+func lookupTags(tag string) []string {
+	parts := strings.Split(strings.ReplaceAll(tag, "_", "-"), "-")
+	parts[0] = strings.ToLower(parts[0])
+	if len(parts) == 1 {
+		return parts
+	}
+	return []string{parts[0] + "-" + strings.ToUpper(parts[1]), parts[0]}
+}
+
+// CardinalRules returns the cardinal plural rules of the language. If the language is not known
+// the root rules, where every number is "other", are returned
+func CardinalRules(tag string) (*PluralRules, bool) {
+	return findRules(cardinalRules, tag)
+}
+
+// OrdinalRules returns the ordinal plural rules of the language. If the language is not known
+// the root rules, where every number is "other", are returned
+func OrdinalRules(tag string) (*PluralRules, bool) {
+	return findRules(ordinalRules, tag)
+}
+
+func findRules(data map[string][]PluralRule, tag string) (*PluralRules, bool) {
+	for _, lookup := range lookupTags(tag) {
+		if rules, found := data[lookup]; found {
+			return &PluralRules{Id: lookup, Rules: rules}, true
+		}
+	}
+	return rootRules, false
+}
@@ -0,0 +1,166 @@
+package cldr
+
+import (
+	"go/constant"
+	"go/token"
+	gotypes "go/types"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// category returns the category of the number, written with its visible fraction digits like 1.50, evaluating the
+// conditions of the rules as go constant expressions
+func category(t *testing.T, rules *PluralRules, number string) string {
+	t.Helper()
+	integer, fraction, _ := strings.Cut(strings.TrimPrefix(number, "-"), ".")
+	n, _ := strconv.ParseFloat(strings.TrimPrefix(number, "-"), 64)
+	i, _ := strconv.ParseInt(integer, 10, 64)
+	f, _ := strconv.ParseInt("0"+fraction, 10, 64)
+	tr, _ := strconv.ParseInt("0"+strings.TrimRight(fraction, "0"), 10, 64)
+
+	pkg := gotypes.NewPackage("rules", "rules")
+	operand := func(name string, typ gotypes.BasicKind, value constant.Value) {
+		pkg.Scope().Insert(gotypes.NewConst(token.NoPos, pkg, name, gotypes.Typ[typ], value))
+	}
+	operand("n", gotypes.Float64, constant.MakeFloat64(n))
+	operand("i", gotypes.Int64, constant.MakeInt64(i))
+	operand("v", gotypes.Int64, constant.MakeInt64(int64(len(fraction))))
+	operand("f", gotypes.Int64, constant.MakeInt64(f))
+	operand("t", gotypes.Int64, constant.MakeInt64(tr))
+
+	for _, rule := range rules.Rules {
+		result, err := gotypes.Eval(token.NewFileSet(), pkg, token.NoPos, rule.Condition)
+		if err != nil {
+			t.Fatalf("the condition %q of %s is not valid: %v", rule.Condition, rule.Category, err)
+		}
+		if constant.BoolVal(result.Value) {
+			return rule.Category
+		}
+	}
+	return "other"
+}
+
+func TestCardinalRules(t *testing.T) {
+	tests := []struct {
+		lang   string
+		number string
+		want   string
+	}{
+		{lang: "en-US", number: "1", want: "one"},
+		{lang: "en", number: "0", want: "other"},
+		{lang: "en", number: "1.0", want: "other"},
+		{lang: "en", number: "21", want: "other"},
+		{lang: "fr", number: "0", want: "one"},
+		{lang: "fr", number: "1.5", want: "one"},
+		{lang: "fr", number: "2", want: "other"},
+		{lang: "fr", number: "1000000", want: "many"},
+		{lang: "es", number: "1", want: "one"},
+		{lang: "es", number: "2000000", want: "many"},
+		{lang: "pt-PT", number: "0", want: "other"},
+		{lang: "pt_BR", number: "0", want: "one"},
+		{lang: "ru", number: "1", want: "one"},
+		{lang: "ru", number: "21", want: "one"},
+		{lang: "ru", number: "11", want: "many"},
+		{lang: "ru", number: "3", want: "few"},
+		{lang: "ru", number: "13", want: "many"},
+		{lang: "ru", number: "1.5", want: "other"},
+		{lang: "pl", number: "1", want: "one"},
+		{lang: "pl", number: "22", want: "few"},
+		{lang: "pl", number: "12", want: "many"},
+		{lang: "pl", number: "21", want: "many"},
+		{lang: "cs", number: "3", want: "few"},
+		{lang: "cs", number: "1.5", want: "many"},
+		{lang: "hr", number: "2.3", want: "few"},
+		{lang: "hr", number: "1.1", want: "one"},
+		{lang: "lv", number: "10", want: "zero"},
+		{lang: "lv", number: "0.1", want: "one"},
+		{lang: "ar", number: "0", want: "zero"},
+		{lang: "ar", number: "2", want: "two"},
+		{lang: "ar", number: "105", want: "few"},
+		{lang: "ar", number: "111", want: "many"},
+		{lang: "ar", number: "100", want: "other"},
+		{lang: "cy", number: "6", want: "many"},
+		{lang: "da", number: "0.1", want: "one"},
+		{lang: "is", number: "21", want: "one"},
+		{lang: "is", number: "11", want: "other"},
+		{lang: "ja", number: "1", want: "other"},
+		{lang: "xx", number: "1", want: "other"},
+	}
+	for _, test := range tests {
+		t.Run(test.lang+" "+test.number, func(t *testing.T) {
+			rules, _ := CardinalRules(test.lang)
+			if got := category(t, rules, test.number); got != test.want {
+				t.Errorf("expected %s, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestOrdinalRules(t *testing.T) {
+	tests := []struct {
+		lang   string
+		number string
+		want   string
+	}{
+		{lang: "en", number: "1", want: "one"},
+		{lang: "en", number: "2", want: "two"},
+		{lang: "en", number: "3", want: "few"},
+		{lang: "en", number: "11", want: "other"},
+		{lang: "en", number: "12", want: "other"},
+		{lang: "en", number: "22", want: "two"},
+		{lang: "en", number: "103", want: "few"},
+		{lang: "fr", number: "1", want: "one"},
+		{lang: "fr", number: "2", want: "other"},
+		{lang: "it", number: "800", want: "many"},
+		{lang: "sv", number: "32", want: "one"},
+		{lang: "cy", number: "8", want: "zero"},
+		{lang: "es", number: "1", want: "other"},
+	}
+	for _, test := range tests {
+		t.Run(test.lang+" "+test.number, func(t *testing.T) {
+			rules, _ := OrdinalRules(test.lang)
+			if got := category(t, rules, test.number); got != test.want {
+				t.Errorf("expected %s, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestPluralRules(t *testing.T) {
+	tests := []struct {
+		lang       string
+		found      bool
+		id         string
+		categories string
+		operands   string
+	}{
+		{lang: "en-GB", found: true, id: "en", categories: "one other", operands: "iv"},
+		{lang: "pt-PT", found: true, id: "pt-PT", categories: "one many other", operands: "iv"},
+		{lang: "PT_pt", found: true, id: "pt-PT", categories: "one many other", operands: "iv"},
+		{lang: "ru", found: true, id: "ru", categories: "one few many other", operands: "iv"},
+		{lang: "hr", found: true, id: "hr", categories: "one few other", operands: "ivf"},
+		{lang: "ja", found: true, id: "ja", categories: "other", operands: ""},
+		{lang: "xx", found: false, id: "root", categories: "other", operands: ""},
+	}
+	for _, test := range tests {
+		t.Run(test.lang, func(t *testing.T) {
+			rules, found := CardinalRules(test.lang)
+			if found != test.found || rules.Id != test.id {
+				t.Fatalf("expected the rules %s (found %v), got %s (found %v)", test.id, test.found, rules.Id, found)
+			}
+			if categories := strings.Join(rules.Categories(), " "); categories != test.categories {
+				t.Errorf("expected the categories %s, got %s", test.categories, categories)
+			}
+			operands := ""
+			for _, op := range []string{"n", "i", "v", "f", "t"} {
+				if rules.Operands()[op] {
+					operands += op
+				}
+			}
+			if operands != test.operands {
+				t.Errorf("expected the operands %s, got %s", test.operands, operands)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"os"
+	"slices"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/exchange"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+type CsvArgs struct {
+	MessagesArgs
+	CsvFile string
+}
+
+// ExportCsv writes a table with a row for each unit and a column for each language, the default language first
+func ExportCsv(args CsvArgs) {
+	log := newLogger(args.LogLevel)
+	messages, _ := args.load(log)
+
+	langs := []string{args.DefaultLanguage}
+	others := messages.Languages().Get()
+	slices.Sort(others)
+	for _, lang := range others {
+		if lang != args.DefaultLanguage {
+			langs = append(langs, lang)
+		}
+	}
+
+	file, err := os.Create(args.CsvFile)
+	if err != nil {
+		log.Error("Could not create csv file", "file", args.CsvFile, "err", err)
+		os.Exit(1)
+	}
+	defer file.Close()
+	if err := exchange.WriteCsv(file, langs, exchange.CsvRows(messages, langs, args.DefaultLanguage)); err != nil {
+		log.Error("Could not write csv file", "file", args.CsvFile, "err", err)
+		os.Exit(1)
+	}
+}
+
+// ImportCsv writes the cells of the table that differ from the current messages into the json files of each language,
+// the default language included. Empty cells are skipped, as are texts that use arguments the message does not have
+func ImportCsv(args CsvArgs) {
+	log := newLogger(args.LogLevel)
+	messages, files := args.load(log)
+
+	file, err := os.Open(args.CsvFile)
+	if err != nil {
+		log.Error("Could not open csv file", "file", args.CsvFile, "err", err)
+		os.Exit(1)
+	}
+	langs, rows, err := exchange.ReadCsv(file)
+	file.Close()
+	if err != nil {
+		log.Error("Could not read csv file", "file", args.CsvFile, "err", err)
+		os.Exit(1)
+	}
+
+	wc := util.NewWarningsCollector()
+	importer := exchange.NewImporter(messages, files, args.DefaultLanguage, wc)
+	for i, lang := range langs {
+		var imported, missing []string
+		for _, row := range rows {
+			text := row.Texts[i]
+			if text == "" {
+				missing = append(missing, row.Key)
+				continue
+			}
+			instance, _, found := importer.Reference(lang, row.Key)
+			if !found {
+				wc.AddWarning(exchange.ErrUnknownUnit.WithArgs(row.Key))
+				continue
+			}
+			_, selectors, _ := exchange.ParseKey(row.Key)
+			if current, found := exchange.UnitText(instance, lang, selectors); found && current == text {
+				continue
+			}
+			dropped, invented := importer.CompareArguments(lang, row.Key, text)
+			if lang == args.DefaultLanguage {
+				dropped = nil // editing the default language may remove arguments of the message
+			}
+			if len(dropped) > 0 || len(invented) > 0 {
+				log.Warn("Refusing text that does not use the arguments of the message", "lang", lang, "entry", row.Key,
+					"dropped-arguments", dropped, "unknown-arguments", invented)
+				continue
+			}
+			if importer.Set(lang, row.Key, text) {
+				imported = append(imported, row.Key)
+			}
+		}
+		if len(imported) > 0 {
+			log.Info("Imported texts", "lang", lang, "entries", imported)
+		}
+		if len(missing) > 0 {
+			log.Warn("Missing texts", "lang", lang, "entries", missing)
+		}
+	}
+	for _, warning := range wc.Warnings() {
+		log.Warn(warning.Error())
+	}
+
+	if err := importer.Save(); err != nil {
+		log.Error("Could not save the imported texts", "err", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,129 @@
+package exchange
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+var androidPlatform = nativePlatform{
+	Name: "android",
+	Placeholder: func(position int, _ *types.MessageArgument, flags string, verb rune) string {
+		switch verb {
+		case 't':
+			verb = 'b'
+		case 's', 'd', 'f', 'e', 'E', 'g', 'G', 'x', 'X', 'o', 'c', 'b':
+		default: // v, q and the rest of the go verbs
+			verb = 's'
+		}
+		return "%" + strconv.Itoa(position) + "$" + flags + string(verb)
+	},
+}
+
+// AndroidName returns the name of the resource of a message, its path joined by _ with the - replaced by _
+func AndroidName(path []string) string {
+	return strings.ReplaceAll(strings.Join(path, "_"), "-", "_")
+}
+
+// AndroidValuesDirectory returns the name of the resources directory of a language: values for the default language,
+// values-en-rUS for languages with a region and values-b+zh+Hant+TW for the rest
+func AndroidValuesDirectory(lang, defLang string) string {
+	if lang == defLang {
+		return "values"
+	}
+	parts := strings.Split(lang, "-")
+	switch {
+	case len(parts) == 1:
+		return "values-" + parts[0]
+	case len(parts) == 2 && (len(parts[1]) == 2 || len(parts[1]) == 3 && isNumber(parts[1])):
+		return "values-" + parts[0] + "-r" + strings.ToUpper(parts[1])
+	default:
+		return "values-b+" + strings.Join(parts, "+")
+	}
+}
+
+// AndroidStrings returns the strings of the language for an android strings.xml
+func AndroidStrings(msgs *types.MessageBag, lang, defLang string, wc *util.WarningsCollector) []NativeString {
+	strs := nativeStrings(msgs, lang, defLang, androidPlatform, wc)
+	owners := make(map[string]string)
+	unique := make([]NativeString, 0, len(strs))
+	for _, str := range strs {
+		name := AndroidName(str.Path)
+		if owner, collides := owners[name]; collides {
+			wc.AddWarning(ErrNativeNameCollide.WithArgs(owner, types.PathAsStr(str.Path), androidPlatform.Name, name))
+			continue
+		}
+		owners[name] = types.PathAsStr(str.Path)
+		unique = append(unique, str)
+	}
+	return unique
+}
+
+// WriteAndroidStrings writes a strings.xml resources file. Plural messages are written as <plurals>
+func WriteAndroidStrings(w io.Writer, strs []NativeString) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "<?xml version=\"1.0\" encoding=\"utf-8\"?>\n")
+	fmt.Fprintf(bw, "<!-- Generated using https://github.com/MrNemo64/go-n-i18n -->\n")
+	fmt.Fprintf(bw, "<resources>\n")
+	for _, str := range strs {
+		if str.Description != "" {
+			fmt.Fprintf(bw, "    <!-- %s -->\n", strings.ReplaceAll(str.Description, "--", "- -"))
+		}
+		name := AndroidName(str.Path)
+		if str.Plural == nil {
+			fmt.Fprintf(bw, "    <string name=\"%s\">%s</string>\n", name, androidEscape(str.Text))
+			continue
+		}
+		fmt.Fprintf(bw, "    <plurals name=\"%s\">\n", name)
+		for _, form := range str.Plural.Forms {
+			fmt.Fprintf(bw, "        <item quantity=\"%s\">%s</item>\n", form.Quantity, androidEscape(form.Text))
+		}
+		fmt.Fprintf(bw, "    </plurals>\n")
+	}
+	fmt.Fprintf(bw, "</resources>\n")
+	return bw.Flush()
+}
+
+// androidEscape escapes a text for the android resources: quotes, backslashes and new lines are escaped with a
+// backslash, spaces that android would collapse are written as \u0020 and the xml characters as entities
+func androidEscape(text string) string {
+	sb := strings.Builder{}
+	runes := []rune(text)
+	for i, c := range runes {
+		switch {
+		case c == '\\':
+			sb.WriteString(`\\`)
+		case c == '\'':
+			sb.WriteString(`\'`)
+		case c == '"':
+			sb.WriteString(`\"`)
+		case c == '\n':
+			sb.WriteString(`\n`)
+		case c == '\t':
+			sb.WriteString(`\t`)
+		case c == '&':
+			sb.WriteString("&amp;")
+		case c == '<':
+			sb.WriteString("&lt;")
+		case c == '>':
+			sb.WriteString("&gt;")
+		case (c == '@' || c == '?') && i == 0:
+			sb.WriteString(`\` + string(c))
+		case c == ' ' && (i == 0 || i == len(runes)-1 || runes[i-1] == ' '):
+			sb.WriteString(`\u0020`)
+		default:
+			sb.WriteRune(c)
+		}
+	}
+	return sb.String()
+}
+
+func isNumber(str string) bool {
+	_, err := strconv.Atoi(str)
+	return err == nil
+}
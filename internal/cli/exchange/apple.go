@@ -0,0 +1,105 @@
+package exchange
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+var applePlatform = nativePlatform{
+	Name: "apple",
+	Placeholder: func(position int, arg *types.MessageArgument, flags string, verb rune) string {
+		return "%" + strconv.Itoa(position) + "$" + appleSpecifier(arg, flags, verb)
+	},
+	ExactZero: true, // the zero key of the stringsdict files is used for 0 in all languages
+}
+
+// appleSpecifier returns the format specifier of an argument: %@ for strings and objects, %ld for integers and the
+// verb of the format for floats
+func appleSpecifier(arg *types.MessageArgument, flags string, verb rune) string {
+	switch {
+	case strings.HasPrefix(arg.Type.Type, "uint"):
+		return flags + "lu"
+	case strings.HasPrefix(arg.Type.Type, "int"):
+		if verb == 'x' || verb == 'X' || verb == 'o' {
+			return flags + "l" + string(verb)
+		}
+		return flags + "ld"
+	case strings.HasPrefix(arg.Type.Type, "float"):
+		if verb == 'f' || verb == 'e' || verb == 'E' || verb == 'g' || verb == 'G' {
+			return flags + string(verb)
+		}
+		return flags + "g"
+	default:
+		return "@"
+	}
+}
+
+// AppleStrings returns the strings of the language for the Localizable.strings and Localizable.stringsdict files.
+// The key of each string is its path
+func AppleStrings(msgs *types.MessageBag, lang, defLang string, wc *util.WarningsCollector) []NativeString {
+	return nativeStrings(msgs, lang, defLang, applePlatform, wc)
+}
+
+// WriteAppleStrings writes the strings that are not plurals into a .strings file
+func WriteAppleStrings(w io.Writer, strs []NativeString) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "/* Generated using https://github.com/MrNemo64/go-n-i18n */\n")
+	for _, str := range strs {
+		if str.Plural != nil {
+			continue
+		}
+		fmt.Fprintf(bw, "\n")
+		if str.Description != "" {
+			fmt.Fprintf(bw, "/* %s */\n", strings.ReplaceAll(str.Description, "*/", "* /"))
+		}
+		fmt.Fprintf(bw, "\"%s\" = \"%s\";\n", appleEscape(types.PathAsStr(str.Path)), appleEscape(str.Text))
+	}
+	return bw.Flush()
+}
+
+// WriteAppleStringsdict writes the plural strings into a .stringsdict file, where each plural message has a
+// variable with the name of the argument that selects the form
+func WriteAppleStringsdict(w io.Writer, strs []NativeString) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	fmt.Fprintf(bw, "<!DOCTYPE plist PUBLIC \"-//Apple//DTD PLIST 1.0//EN\" \"http://www.apple.com/DTDs/PropertyList-1.0.dtd\">\n")
+	fmt.Fprintf(bw, "<!-- Generated using https://github.com/MrNemo64/go-n-i18n -->\n")
+	fmt.Fprintf(bw, "<plist version=\"1.0\">\n")
+	fmt.Fprintf(bw, "<dict>\n")
+	for _, str := range strs {
+		if str.Plural == nil {
+			continue
+		}
+		variable := str.Plural.Argument.Name
+		flags, verb := splitFormat(str.Plural.Argument.DefaultFormat())
+		fmt.Fprintf(bw, "    <key>%s</key>\n", xmlEscape(types.PathAsStr(str.Path)))
+		fmt.Fprintf(bw, "    <dict>\n")
+		fmt.Fprintf(bw, "        <key>NSStringLocalizedFormatKey</key>\n")
+		fmt.Fprintf(bw, "        <string>%%#@%s@</string>\n", xmlEscape(variable))
+		fmt.Fprintf(bw, "        <key>%s</key>\n", xmlEscape(variable))
+		fmt.Fprintf(bw, "        <dict>\n")
+		fmt.Fprintf(bw, "            <key>NSStringFormatSpecTypeKey</key>\n")
+		fmt.Fprintf(bw, "            <string>NSStringPluralRuleType</string>\n")
+		fmt.Fprintf(bw, "            <key>NSStringFormatValueTypeKey</key>\n")
+		fmt.Fprintf(bw, "            <string>%s</string>\n", appleSpecifier(str.Plural.Argument, flags, verb))
+		for _, form := range str.Plural.Forms {
+			fmt.Fprintf(bw, "            <key>%s</key>\n", form.Quantity)
+			fmt.Fprintf(bw, "            <string>%s</string>\n", xmlEscape(form.Text))
+		}
+		fmt.Fprintf(bw, "        </dict>\n")
+		fmt.Fprintf(bw, "    </dict>\n")
+	}
+	fmt.Fprintf(bw, "</dict>\n")
+	fmt.Fprintf(bw, "</plist>\n")
+	return bw.Flush()
+}
+
+func appleEscape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`).Replace(text)
+}
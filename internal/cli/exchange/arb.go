@@ -0,0 +1,144 @@
+package exchange
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/cldr"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+	"github.com/iancoleman/orderedmap"
+)
+
+var (
+	ErrArbMessage    util.Error = util.MakeError("could not export the message %s in the lang %s to arb: %w")
+	ErrArbKeyCollide            = util.MakeError("the messages %s and %s have the same arb key %s, only the first one is exported")
+)
+
+// arbTypes maps the types of the arguments to the types of the placeholders of arb files
+var arbTypes = map[string]string{
+	"string": "String",
+	"int":    "int", "int8": "int", "int16": "int", "int32": "int", "int64": "int",
+	"uint": "int", "uint8": "int", "uint16": "int", "uint32": "int", "uint64": "int",
+	"float32": "double", "float64": "double",
+	"time.Time": "DateTime",
+}
+
+// arbDateFormats maps the styles of the date and time arguments to the arb date formats, the skeletons are the same
+var arbDateFormats = map[string]map[string]string{
+	"date": {"short": "yMd", "medium": "yMMMd", "long": "yMMMMd", "full": "yMMMMEEEEd"},
+	"time": {"short": "jm", "medium": "jms", "long": "jms", "full": "jms"},
+}
+
+// ArbKey returns the key of a message in arb files, its path in camel case since arb keys are dart identifiers
+func ArbKey(path []string) string {
+	sb := strings.Builder{}
+	for i, part := range path {
+		upper := i > 0
+		for _, c := range part {
+			if c == '-' || c == '_' {
+				upper = true
+				continue
+			}
+			if upper {
+				sb.WriteString(strings.ToUpper(string(c)))
+				upper = false
+			} else {
+				sb.WriteRune(c)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// ArbLocale returns the locale of a language as written in arb files and in their names, with _ instead of -
+func ArbLocale(lang string) string {
+	return strings.ReplaceAll(lang, "-", "_")
+}
+
+// ArbDocument returns the arb file of the language with its messages in ICU MessageFormat. The file of the default
+// language is the template, so it also has the `@key` metadata with the description and placeholders of each message.
+// References are inlined since arb files can not reference other messages. Messages that can not be written in ICU
+// MessageFormat are reported to the collector and skipped
+func ArbDocument(msgs *types.MessageBag, lang, defLang string, wc *util.WarningsCollector) *orderedmap.OrderedMap {
+	document := orderedmap.New()
+	document.Set("@@locale", ArbLocale(lang))
+	owners := make(map[string]string)
+	for _, instance := range msgs.Instances() {
+		value, found := instance.Message(lang)
+		if !found || instance.Untranslated(lang, defLang) {
+			continue
+		}
+		key := ArbKey(instance.Path())
+		if owner, collides := owners[key]; collides {
+			wc.AddWarning(ErrArbKeyCollide.WithArgs(owner, instance.PathAsStr(), key))
+			continue
+		}
+		owners[key] = instance.PathAsStr()
+		if missing := instance.MissingBranches(lang, defLang); len(missing) > 0 {
+			// arb messages are whole, a partial one would leave the missing branches without text
+			wc.AddWarning(ErrArbMessage.WithArgs(instance.PathAsStr(), lang, ErrMissingBranches.WithArgs(strings.Join(missing, ", "))))
+			continue
+		}
+		value, err := InlineReferences(value, lang, defLang)
+		if err != nil {
+			wc.AddWarning(ErrArbMessage.WithArgs(instance.PathAsStr(), lang, err))
+			continue
+		}
+		encoded, err := EncodeIcu(value)
+		if err != nil {
+			wc.AddWarning(ErrArbMessage.WithArgs(instance.PathAsStr(), lang, err))
+			continue
+		}
+		document.Set(key, encoded)
+		if lang == defLang {
+			if metadata := arbMetadata(instance, lang); metadata != nil {
+				document.Set("@"+key, *metadata)
+			}
+		}
+	}
+	return document
+}
+
+// arbDateFormat returns the arb format of a date, time or datetime argument. Datetime styles have no equivalent
+func arbDateFormat(arg *types.MessageArgument) (string, bool) {
+	if !arg.Type.Localized || arg.Type.Type != "time.Time" {
+		return "", false
+	}
+	format := arg.DefaultFormat()
+	if format == "" {
+		format = cldr.DefaultDateStyle
+	}
+	if !slices.Contains(cldr.DateStyles, format) {
+		return format, true
+	}
+	style, found := arbDateFormats[arg.Type.Name][format]
+	return style, found
+}
+
+func arbMetadata(instance *types.MessageInstance, lang string) *orderedmap.OrderedMap {
+	metadata := orderedmap.New()
+	if description := instance.Description(lang); description != "" {
+		metadata.Set("description", description)
+	}
+	if args := instance.Args().Args; len(args) > 0 {
+		placeholders := orderedmap.New()
+		for _, arg := range args {
+			placeholder := orderedmap.New()
+			if typeName, found := arbTypes[arg.Type.Type]; found {
+				placeholder.Set("type", typeName)
+				if format, found := arbDateFormat(arg); found {
+					placeholder.Set("format", format)
+				}
+			} else {
+				placeholder.Set("type", "Object")
+			}
+			placeholders.Set(arg.Name, *placeholder)
+		}
+		metadata.Set("placeholders", *placeholders)
+	}
+	if len(metadata.Keys()) == 0 {
+		return nil
+	}
+	return metadata
+}
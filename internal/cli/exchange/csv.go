@@ -0,0 +1,103 @@
+package exchange
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+var (
+	ErrCsvHeader   util.Error = util.MakeError("the first row of the csv file must be the key column followed by a column for each language but is %v")
+	ErrCsvRowWidth            = util.MakeError("the row %d of the csv file has %d columns but the header has %d")
+)
+
+// CsvKeyColumn is the name of the first column of the csv files, the one with the keys of the units
+const CsvKeyColumn = "key"
+
+// CsvRow is a unit with its text in each of the languages of the table, empty if the language does not have it
+type CsvRow struct {
+	Key   string
+	Texts []string
+}
+
+// CsvRows returns a row for each unit to translate into any of the languages with its text in each of them, see
+// TranslationUnits
+func CsvRows(msgs *types.MessageBag, langs []string, defLang string) []CsvRow {
+	var rows []CsvRow
+	for _, instance := range msgs.Instances() {
+		for _, unit := range TranslationUnits(instance, defLang, langs...) {
+			row := CsvRow{Key: unit.Key(), Texts: make([]string, len(langs))}
+			for i, lang := range langs {
+				row.Texts[i], _ = UnitText(instance, lang, unit.Selectors)
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// UnitText returns the text of the branch of the message identified by the selectors in the language
+func UnitText(instance *types.MessageInstance, lang string, selectors []string) (string, bool) {
+	value, found := instance.Message(lang)
+	if !found {
+		return "", false
+	}
+	if value, found = Select(value, selectors); !found || !isLeaf(value) {
+		return "", false
+	}
+	return Text(value), true
+}
+
+// WriteCsv writes the table with a header with the key column and the languages
+func WriteCsv(w io.Writer, langs []string, rows []CsvRow) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(append([]string{CsvKeyColumn}, langs...)); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := writer.Write(append([]string{row.Key}, row.Texts...)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadCsv reads a table written by WriteCsv, returning the languages of its columns and its rows.
+// A leading byte order mark, added by some spreadsheets, is ignored
+func ReadCsv(r io.Reader) ([]string, []CsvRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil, ErrCsvHeader.WithArgs([]string{})
+	}
+	header := records[0]
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+	if len(header) < 2 || strings.TrimSpace(header[0]) != CsvKeyColumn {
+		return nil, nil, ErrCsvHeader.WithArgs(header)
+	}
+	langs := make([]string, len(header)-1)
+	for i := range langs {
+		langs[i] = strings.TrimSpace(header[i+1])
+	}
+	rows := make([]CsvRow, 0, len(records)-1)
+	for i, record := range records[1:] {
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue // empty line
+		}
+		if len(record) != len(header) {
+			return nil, nil, ErrCsvRowWidth.WithArgs(i+2, len(record), len(header))
+		}
+		rows = append(rows, CsvRow{Key: strings.TrimSpace(record[0]), Texts: record[1:]})
+	}
+	return langs, rows, nil
+}
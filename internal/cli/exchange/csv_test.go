@@ -0,0 +1,35 @@
+package exchange
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+)
+
+func TestCsvRoundTrip(t *testing.T) {
+	testRoundTrip(t, append(roundTripMessages, pluralRoundTripMessages...), func(msgs *types.MessageBag, lang string) string {
+		langs := []string{"en", lang}
+		sb := strings.Builder{}
+		if err := WriteCsv(&sb, langs, CsvRows(msgs, langs, "en")); err != nil {
+			t.Fatal(err)
+		}
+		return sb.String()
+	}, func(im *Importer, lang, exported string) {
+		langs := []string{"en", lang}
+		// spreadsheets save the table with a byte order mark
+		read, rows, err := ReadCsv(strings.NewReader("\ufeff" + exported))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(read, langs) {
+			t.Errorf("expected the languages %v, got %v", langs, read)
+		}
+		for _, row := range rows {
+			if row.Texts[1] != "" {
+				im.Set(lang, row.Key, row.Texts[1])
+			}
+		}
+	})
+}
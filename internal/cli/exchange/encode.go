@@ -0,0 +1,144 @@
+package exchange
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/parse"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/iancoleman/orderedmap"
+)
+
+// Encode turns a value into its json representation. Arguments are written only with their name and format
+// since their type is already known from the language the value is taken from, references as {@path}. Untranslated
+// texts are null
+func Encode(value types.MessageValue) any {
+	switch v := value.(type) {
+	case *types.ValueString:
+		if types.IsUntranslated(v) {
+			return nil
+		}
+		return v.Message()
+	case *types.ValueParametrized:
+		sb := strings.Builder{}
+		for i, arg := range v.Args {
+			sb.WriteString(v.TextSegments[i].Message())
+			sb.WriteString(segmentPlaceholder(arg, arg.Format))
+		}
+		sb.WriteString(v.TextSegments[len(v.TextSegments)-1].Message())
+		return sb.String()
+	case *types.ValueMultiline:
+		lines := make([]any, len(v.Lines))
+		for i, line := range v.Lines {
+			lines[i] = Encode(AsValue(line))
+		}
+		return lines
+	case *types.ValueConditional:
+		encoded := orderedmap.New()
+		for _, condition := range v.Conditions {
+			encoded.Set(condition.Condition, Encode(AsValue(condition.Value)))
+		}
+		if v.Else != nil {
+			encoded.Set("", Encode(AsValue(v.Else)))
+		}
+		return *encoded
+	case *types.ValuePlural:
+		encoded := orderedmap.New()
+		if v.Argument.Name != parse.DefaultPluralArgument {
+			encoded.Set("_arg", v.Argument.Name)
+		}
+		if v.Ordinal {
+			encoded.Set("_ordinal", true)
+		}
+		for _, exact := range v.Exact {
+			encoded.Set("="+exact.Number, Encode(AsValue(exact.Value)))
+		}
+		for _, form := range v.Forms {
+			encoded.Set(form.Category.String(), Encode(AsValue(form.Value)))
+		}
+		return *encoded
+	default:
+		panic(fmt.Errorf("unknown MessageValue type %+v", value))
+	}
+}
+
+// EncodeText turns a text with {name} arguments into the json representation of a message with the shape of the
+// reference value: if the reference is multiline the text is split in lines and the arguments get the format
+// they have in the reference
+func EncodeText(text string, reference types.MessageValue) any {
+	formats := make(map[string]string)
+	types.VisitValues(reference, func(value types.MessageValue) {
+		if parametrized, ok := value.(*types.ValueParametrized); ok {
+			for _, arg := range parametrized.Args {
+				if arg.IsReference() {
+					continue
+				}
+				if _, found := formats[arg.Argument.Name]; !found || formats[arg.Argument.Name] == "" {
+					formats[arg.Argument.Name] = arg.Format
+				}
+			}
+		}
+	})
+	encoded := parse.ArgumentExtractor.ReplaceAllStringFunc(text, func(match string) string {
+		groups := parse.ArgumentExtractor.FindStringSubmatch(match)
+		if groups[2] != "" || groups[3] != "" {
+			return match
+		}
+		return placeholder(groups[1], formats[groups[1]])
+	})
+	if _, isMultiline := reference.(*types.ValueMultiline); isMultiline {
+		lines := strings.Split(encoded, "\n")
+		asAny := make([]any, len(lines))
+		for i := range lines {
+			asAny[i] = lines[i]
+		}
+		return asAny
+	}
+	return encoded
+}
+
+// Placeholders returns the names of the arguments used in a text in order of appearance
+func Placeholders(text string) []string {
+	var names []string
+	for _, groups := range parse.ArgumentExtractor.FindAllStringSubmatch(text, -1) {
+		names = append(names, groups[1])
+	}
+	return names
+}
+
+// CompareArguments returns the arguments of the reference that the text does not use and the arguments the text
+// uses that the message does not have. The branches of conditional and plural messages may leave out arguments, like
+// a plural form that writes its number as a word, so only the arguments dropped by whole messages are returned
+func CompareArguments(text string, instance *types.MessageInstance, reference types.MessageValue, branch bool) ([]string, []string) {
+	used := Placeholders(text)
+	var missing, invented []string
+	if !branch {
+		for _, name := range Placeholders(Text(reference)) {
+			if !slices.Contains(used, name) && !slices.Contains(missing, name) {
+				missing = append(missing, name)
+			}
+		}
+	}
+	for _, name := range used {
+		if _, found := instance.Args().GetArgument(name); !found && !slices.Contains(invented, name) {
+			invented = append(invented, name)
+		}
+	}
+	return missing, invented
+}
+
+func placeholder(name, format string) string {
+	if format == "" {
+		return "{" + name + "}"
+	}
+	return "{" + name + "::" + format + "}"
+}
+
+// segmentPlaceholder renders an argument of a parametrized value with placeholder, or a reference as {@path}
+func segmentPlaceholder(arg *types.UsedArgument, format string) string {
+	if arg.IsReference() {
+		return "{@" + arg.Reference.PathAsStr() + "}"
+	}
+	return placeholder(arg.Argument.Name, format)
+}
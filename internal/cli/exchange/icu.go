@@ -0,0 +1,121 @@
+package exchange
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/parse"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+var (
+	ErrIcuCondition    util.Error = util.MakeError("the condition `%s` can not be written in ICU MessageFormat, only conditions like name == \"key\" comparing the same argument and with an else branch can")
+	ErrIcuMissingOther            = util.MakeError("the plural of the argument %s can not be written in ICU MessageFormat without an other form")
+)
+
+// icuSelectCondition matches the conditions that can be written as an option of an ICU select argument
+var icuSelectCondition = regexp.MustCompile(`^\s*([a-zA-Z_]\w*)\s*==\s*("(?:[^"\\]|\\.)*")\s*$`)
+
+var icuSelectKey = regexp.MustCompile(`^[\w-]+$`)
+
+// EncodeIcu turns a value into an ICU MessageFormat message. Conditional values are select arguments, so they
+// must compare a single argument with string literals and have an else branch. Formats of the arguments are dropped
+// and references are kept as {@path}
+func EncodeIcu(value types.MessageValue) (string, error) {
+	return encodeIcu(value, false)
+}
+
+func encodeIcu(value types.MessageValue, inPlural bool) (string, error) {
+	switch v := value.(type) {
+	case *types.ValueString:
+		return parse.EscapeIcuText(v.Message(), inPlural), nil
+	case *types.ValueParametrized:
+		sb := strings.Builder{}
+		for i, arg := range v.Args {
+			sb.WriteString(parse.EscapeIcuText(v.TextSegments[i].Message(), inPlural))
+			sb.WriteString(segmentPlaceholder(arg, ""))
+		}
+		sb.WriteString(parse.EscapeIcuText(v.TextSegments[len(v.TextSegments)-1].Message(), inPlural))
+		return sb.String(), nil
+	case *types.ValueMultiline:
+		lines := make([]string, len(v.Lines))
+		for i, line := range v.Lines {
+			encoded, err := encodeIcu(AsValue(line), inPlural)
+			if err != nil {
+				return "", err
+			}
+			lines[i] = encoded
+		}
+		return strings.Join(lines, "\n"), nil
+	case *types.ValueConditional:
+		return encodeIcuSelect(v, inPlural)
+	case *types.ValuePlural:
+		if v.Other() == nil {
+			return "", ErrIcuMissingOther.WithArgs(v.Argument.Name)
+		}
+		kind := "plural"
+		if v.Ordinal {
+			kind = "selectordinal"
+		}
+		sb := strings.Builder{}
+		sb.WriteString("{" + v.Argument.Name + ", " + kind + ",")
+		for _, exact := range v.Exact {
+			encoded, err := encodeIcu(AsValue(exact.Value), true)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(" =" + exact.Number + " {" + encoded + "}")
+		}
+		for _, form := range v.Forms {
+			encoded, err := encodeIcu(AsValue(form.Value), true)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(" " + form.Category.String() + " {" + encoded + "}")
+		}
+		sb.WriteString("}")
+		return sb.String(), nil
+	default:
+		panic(fmt.Errorf("unknown MessageValue type %+v", value))
+	}
+}
+
+func encodeIcuSelect(conditional *types.ValueConditional, inPlural bool) (string, error) {
+	if conditional.Else == nil || len(conditional.Conditions) == 0 {
+		condition := ""
+		if len(conditional.Conditions) > 0 {
+			condition = conditional.Conditions[0].Condition
+		}
+		return "", ErrIcuCondition.WithArgs(condition)
+	}
+	name := ""
+	sb := strings.Builder{}
+	for _, condition := range conditional.Conditions {
+		match := icuSelectCondition.FindStringSubmatch(condition.Condition)
+		if match == nil || (name != "" && match[1] != name) {
+			return "", ErrIcuCondition.WithArgs(condition.Condition)
+		}
+		key, err := strconv.Unquote(match[2])
+		if err != nil || !icuSelectKey.MatchString(key) || key == "other" {
+			return "", ErrIcuCondition.WithArgs(condition.Condition)
+		}
+		if name == "" {
+			name = match[1]
+			sb.WriteString("{" + name + ", select,")
+		}
+		encoded, err := encodeIcu(AsValue(condition.Value), inPlural)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(" " + key + " {" + encoded + "}")
+	}
+	encoded, err := encodeIcu(AsValue(conditional.Else), inPlural)
+	if err != nil {
+		return "", err
+	}
+	sb.WriteString(" other {" + encoded + "}}")
+	return sb.String(), nil
+}
@@ -0,0 +1,312 @@
+package exchange
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/parse"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+	"github.com/iancoleman/orderedmap"
+)
+
+var (
+	ErrUnknownUnit        util.Error = util.MakeError("the unit %s does not exist in the default language")
+	ErrNotJsonFile                   = util.MakeError("could not import the unit %s in the lang %s: its messages are in %s but only json files can be imported into")
+	ErrNestedSelectors               = util.MakeError("could not import the unit %s in the lang %s: nested conditionals or plurals can not be written in json")
+	ErrNoFileForLanguage             = util.MakeError("could not import the unit %s in the lang %s: there is no file of the default language to place the new file next to")
+	ErrReadImportedFile              = util.MakeError("could not read the file %s: %w")
+	ErrWriteImportedFile             = util.MakeError("could not write the file %s: %w")
+	ErrUnexpectedJsonNode            = util.MakeError("could not import the unit %s in the lang %s: the key %s of %s is not an object")
+)
+
+// Importer writes translated units back into the json message files of each language, keeping the order of the
+// existing keys. The structure of the messages (bags, conditionals and plurals) is taken from the default language
+type Importer struct {
+	*util.WarningsCollector
+	msgs    *types.MessageBag
+	files   []parse.FileEntry
+	defLang string
+	trees   map[string]*orderedmap.OrderedMap
+	order   []string
+}
+
+func NewImporter(msgs *types.MessageBag, files []parse.FileEntry, defLang string, wc *util.WarningsCollector) *Importer {
+	return &Importer{
+		WarningsCollector: wc,
+		msgs:              msgs,
+		files:             files,
+		defLang:           defLang,
+		trees:             make(map[string]*orderedmap.OrderedMap),
+	}
+}
+
+// Reference returns the value of the default language the unit of the language identified by key translates, see
+// SelectReference
+func (im *Importer) Reference(lang, key string) (*types.MessageInstance, types.MessageValue, bool) {
+	path, selectors, ok := ParseKey(key)
+	if !ok {
+		return nil, nil, false
+	}
+	instance, found := im.instance(path)
+	if !found {
+		return nil, nil, false
+	}
+	value, found := SelectReference(instance.MessageMust(im.defLang), selectors, lang)
+	return instance, value, found
+}
+
+// CompareArguments returns the arguments the text of the unit of the language identified by key drops and the ones
+// it uses that the message does not have, see CompareArguments. Nothing is returned for unknown units
+func (im *Importer) CompareArguments(lang, key, text string) ([]string, []string) {
+	instance, reference, found := im.Reference(lang, key)
+	if !found {
+		return nil, nil
+	}
+	_, selectors, _ := ParseKey(key)
+	return CompareArguments(text, instance, reference, len(selectors) > 0)
+}
+
+func (im *Importer) instance(path []string) (*types.MessageInstance, bool) {
+	bag := im.msgs
+	for i, part := range path {
+		entry, found := bag.GetEntry(part)
+		if !found {
+			return nil, false
+		}
+		if i == len(path)-1 {
+			if !entry.IsInstance() || !entry.Languages().Contains(im.defLang) {
+				return nil, false
+			}
+			return entry.AsInstance(), true
+		}
+		if !entry.IsBag() {
+			return nil, false
+		}
+		bag = entry.AsBag()
+	}
+	return nil, false
+}
+
+// Set writes the text of the unit identified by key in the language. The text uses {name} arguments, which get
+// the format they have in the default language
+func (im *Importer) Set(lang, key, text string) bool {
+	return im.set(lang, key, func(reference types.MessageValue) any { return EncodeText(text, reference) })
+}
+
+// SetUntranslated writes the unit identified by key in the language as null, not translated yet
+func (im *Importer) SetUntranslated(lang, key string) bool {
+	return im.set(lang, key, func(types.MessageValue) any { return nil })
+}
+
+func (im *Importer) set(lang, key string, encode func(reference types.MessageValue) any) bool {
+	path, selectors, _ := ParseKey(key)
+	instance, reference, found := im.Reference(lang, key)
+	if !found {
+		im.AddWarning(ErrUnknownUnit.WithArgs(key))
+		return false
+	}
+	if len(selectors) > 1 {
+		im.AddWarning(ErrNestedSelectors.WithArgs(key, lang))
+		return false
+	}
+
+	file, remaining, ok := im.fileFor(key, lang, path)
+	if !ok {
+		return false
+	}
+	tree, ok := im.load(file)
+	if !ok {
+		return false
+	}
+
+	// resolve the keys of the bags, which may have been renamed with key:name
+	keys := make([]string, 0, len(remaining)+2)
+	current := tree
+	for _, part := range remaining[:len(remaining)-1] {
+		key := part
+		var next *orderedmap.OrderedMap
+		if existing, found := findKey(current, part); found {
+			key = existing
+			value, _ := current.Get(existing)
+			child, isObject := value.(orderedmap.OrderedMap)
+			if !isObject {
+				im.AddWarning(ErrUnexpectedJsonNode.WithArgs(key, lang, existing, file))
+				return false
+			}
+			next = &child
+		}
+		keys = append(keys, key)
+		if next == nil {
+			next = orderedmap.New()
+		}
+		current = next
+	}
+
+	defValue := instance.MessageMust(im.defLang)
+	name := remaining[len(remaining)-1]
+	existing, found := findKey(current, name)
+	if !found {
+		switch defValue.(type) {
+		case *types.ValueConditional:
+			existing = "?" + name
+		case *types.ValuePlural:
+			existing = "#" + name
+		default:
+			existing = name
+		}
+		if len(selectors) > 0 { // only the imported branch is translated, the rest are written as untranslated
+			setNested(tree, append(keys, existing), withoutBranches(defValue))
+		}
+	}
+	keys = append(keys, existing)
+	if value, _ := current.Get(existing); value != nil && len(selectors) > 0 {
+		if _, isObject := value.(orderedmap.OrderedMap); !isObject { // an ICU message, its branches are not in json
+			im.AddWarning(ErrUnexpectedJsonNode.WithArgs(key, lang, existing, file))
+			return false
+		}
+	}
+	if value, _ := current.Get(existing); value != nil {
+		if object, isObject := value.(orderedmap.OrderedMap); isObject {
+			if _, declared := object.Get("_value"); declared && len(selectors) == 0 {
+				keys = append(keys, "_value")
+			}
+		}
+	}
+	if len(selectors) == 1 {
+		keys = append(keys, jsonSelectorKey(selectors[0]))
+	}
+	setNested(tree, keys, encode(reference))
+	return true
+}
+
+// fileFor returns the file where the messages of the path in the language are and the path inside that file.
+// If the language has no file for the path, a new json file is placed next to the one of the default language
+func (im *Importer) fileFor(key, lang string, path []string) (string, []string, bool) {
+	best := func(lang string) parse.FileEntry {
+		var found parse.FileEntry
+		for _, file := range im.files {
+			if file.Language() != lang || len(file.Path()) >= len(path) || !isPrefix(file.Path(), path) {
+				continue
+			}
+			if found == nil || len(file.Path()) > len(found.Path()) {
+				found = file
+			}
+		}
+		return found
+	}
+	if file := best(lang); file != nil {
+		if strings.ToLower(filepath.Ext(file.FullPath())) != ".json" {
+			im.AddWarning(ErrNotJsonFile.WithArgs(key, lang, file.FullPath()))
+			return "", nil, false
+		}
+		return file.FullPath(), path[len(file.Path()):], true
+	}
+	def := best(im.defLang)
+	if def == nil {
+		im.AddWarning(ErrNoFileForLanguage.WithArgs(key, lang))
+		return "", nil, false
+	}
+	return filepath.Join(filepath.Dir(def.FullPath()), lang+".json"), path[len(def.Path()):], true
+}
+
+func (im *Importer) load(file string) (*orderedmap.OrderedMap, bool) {
+	if tree, found := im.trees[file]; found {
+		return tree, true
+	}
+	tree := orderedmap.New()
+	content, err := os.ReadFile(file)
+	if err == nil {
+		tree, err = parse.DecodeJson(content)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		im.AddWarning(ErrReadImportedFile.WithArgs(file, err))
+		return nil, false
+	}
+	im.trees[file] = tree
+	im.order = append(im.order, file)
+	return tree, true
+}
+
+// Save writes all the modified files
+func (im *Importer) Save() error {
+	for _, file := range im.order {
+		content, err := parse.EncodeJson(im.trees[file])
+		if err != nil {
+			return ErrWriteImportedFile.WithArgs(file, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return ErrWriteImportedFile.WithArgs(file, err)
+		}
+		if err := os.WriteFile(file, content, 0644); err != nil {
+			return ErrWriteImportedFile.WithArgs(file, err)
+		}
+	}
+	return nil
+}
+
+// withoutBranches returns the json object of a conditional or plural value without translations: the branches of
+// conditionals are untranslated and plurals only keep the keys that are not forms, like _arg, since their forms
+// depend on the language
+func withoutBranches(value types.MessageValue) orderedmap.OrderedMap {
+	object := orderedmap.New()
+	switch v := value.(type) {
+	case *types.ValueConditional:
+		for _, condition := range v.Conditions {
+			object.Set(condition.Condition, nil)
+		}
+		if v.Else != nil {
+			object.Set("", nil)
+		}
+	case *types.ValuePlural:
+		encoded := Encode(v).(orderedmap.OrderedMap)
+		for _, key := range []string{"_arg", "_ordinal"} {
+			if option, found := encoded.Get(key); found {
+				object.Set(key, option)
+			}
+		}
+	}
+	return *object
+}
+
+// findKey finds the key of an entry in a json object, which may be prefixed by ? or # or followed by :name
+func findKey(object *orderedmap.OrderedMap, name string) (string, bool) {
+	for _, key := range object.Keys() {
+		if key == name || key == "?"+name || key == "#"+name || strings.HasPrefix(key, name+":") {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// jsonSelectorKey returns the key of the json object of a conditional or plural message for a selector
+func jsonSelectorKey(selector string) string {
+	return strings.TrimPrefix(selector, "#")
+}
+
+// setNested sets the value in the keys, creating the missing objects. Objects are stored as values
+// in their parents so each modified object has to be set again into its parent
+func setNested(object *orderedmap.OrderedMap, keys []string, value any) {
+	if len(keys) == 1 {
+		object.Set(keys[0], value)
+		return
+	}
+	child := orderedmap.New()
+	if existing, found := object.Get(keys[0]); found {
+		if existingObject, isObject := existing.(orderedmap.OrderedMap); isObject {
+			child = &existingObject
+		}
+	}
+	setNested(child, keys[1:], value)
+	object.Set(keys[0], *child)
+}
+
+func isPrefix(prefix, path []string) bool {
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
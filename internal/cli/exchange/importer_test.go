@@ -0,0 +1,191 @@
+package exchange
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/parse"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/pipeline"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+// roundTripMessage is a message exported and imported back by the round trip tests, written as the contents of the
+// json files of the default language en and of the translated language
+type roundTripMessage struct {
+	name       string
+	lang       string
+	en         string
+	translated string
+	units      []string // keys of units the export must have
+}
+
+// roundTripMessages are messages translated into es, which has the same plural categories as en
+var roundTripMessages = []roundTripMessage{
+	{name: "text", lang: "es", en: `"greet": "Hello"`, translated: `"greet": "Hola"`},
+	{name: "arguments", lang: "es", en: `"apples": "{name:str} has {count:int} apples"`, translated: `"apples": "{name:str} tiene {count:int} manzanas"`},
+	{name: "formats", lang: "es", en: `"price": "It costs {amount:float64:.2f}"`, translated: `"price": "Cuesta {amount:float64:.2f}"`},
+	{name: "multiline", lang: "es", en: `"lines": ["First {name:str}", "Second"]`, translated: `"lines": ["Primera {name:str}", "Segunda"]`},
+	{name: "escaped", lang: "es", en: `"quote": "Say \"hi\"\tnow"`, translated: `"quote": "Di \"hola\"\tya"`},
+	{name: "nested", lang: "es", en: `"user": {"greet": "Hello {name:str}"}`, translated: `"user": {"greet": "Hola {name:str}"}`},
+	{
+		name:       "conditional",
+		lang:       "es",
+		en:         `"?size": {"count > 10": "Many {count:int}", "count > 1": "Some", "": "Few"}`,
+		translated: `"?size": {"count > 10": "Muchos {count:int}", "count > 1": "Algunos", "": "Pocos"}`,
+	},
+	{
+		name:       "plural",
+		lang:       "es",
+		en:         `"#apples": {"_arg": "count", "=0": "No apples", "one": "One apple", "other": "{count} apples"}`,
+		translated: `"#apples": {"_arg": "count", "=0": "Sin manzanas", "one": "Una manzana", "other": "{count} manzanas"}`,
+	},
+	{
+		name:       "partial conditional",
+		lang:       "es",
+		en:         `"?size": {"count > 1": "Some {count:int}", "": "Few"}`,
+		translated: `"?size": {"": "Pocos"}`,
+	},
+	{
+		name:       "untranslated",
+		lang:       "es",
+		en:         `"greet": "Hello", "bye": "Bye"`,
+		translated: `"greet": "Hola", "bye": null`,
+	},
+}
+
+// pluralRoundTripMessages are plurals translated into languages with other plural categories than en
+var pluralRoundTripMessages = []roundTripMessage{
+	{
+		name:       "more categories",
+		lang:       "pl",
+		en:         `"#apples": {"_arg": "count", "one": "One apple", "other": "{count} apples"}`,
+		translated: `"#apples": {"_arg": "count", "one": "Jedno jabłko", "few": "{count} jabłka", "many": "{count} jabłek", "other": "{count} jabłka"}`,
+		units:      []string{"apples[#one]", "apples[#few]", "apples[#many]", "apples[#other]"},
+	},
+	{
+		name:       "more categories partially translated",
+		lang:       "pl",
+		en:         `"#apples": {"=0": "No apples", "one": "One apple", "other": "{count} apples"}`,
+		translated: `"#apples": {"few": "{count} jabłka", "many": "{count} jabłek"}`,
+		units:      []string{"apples[#=0]", "apples[#one]", "apples[#few]", "apples[#many]", "apples[#other]"},
+	},
+	{
+		name:       "more ordinal categories",
+		lang:       "cy",
+		en:         `"#place": {"_arg": "n", "_ordinal": true, "one": "{n}st", "two": "{n}nd", "few": "{n}rd", "other": "{n}th"}`,
+		translated: `"#place": {"_arg": "n", "_ordinal": true, "zero": "{n}fed", "one": "{n}af", "two": "{n}il", "few": "{n}ydd", "many": "{n}ed", "other": "{n}fed"}`,
+		units:      []string{"place[#zero]", "place[#many]"},
+	},
+	{
+		name:       "less categories",
+		lang:       "ja",
+		en:         `"#apples": {"one": "One apple", "other": "{count} apples"}`,
+		translated: `"#apples": {"other": "りんご{count}個"}`,
+	},
+}
+
+// testRoundTrip exports the messages of the translated language of each test, imports them into a directory that only
+// has the en messages and checks that exporting them again gives the same result
+func testRoundTrip(t *testing.T, tests []roundTripMessage, export func(msgs *types.MessageBag, lang string) string,
+	importInto func(im *Importer, lang, exported string)) {
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			translated := t.TempDir()
+			writeMessages(t, translated, "en", test.en)
+			writeMessages(t, translated, test.lang, test.translated)
+			msgs, _ := loadMessages(t, translated)
+			exported := export(msgs, test.lang)
+			for _, unit := range test.units {
+				if !strings.Contains(exported, unit) {
+					t.Errorf("the export has no unit %s:\n%s", unit, exported)
+				}
+			}
+
+			imported := t.TempDir()
+			writeMessages(t, imported, "en", test.en)
+			msgs, files := loadMessages(t, imported)
+			wc := util.NewWarningsCollector()
+			importer := NewImporter(msgs, files, "en", wc)
+			importInto(importer, test.lang, exported)
+			for _, warning := range wc.Warnings() {
+				t.Errorf("unexpected warning: %v", warning)
+			}
+			if err := importer.Save(); err != nil {
+				t.Fatal(err)
+			}
+
+			msgs, _ = loadMessages(t, imported)
+			diff, err := util.UnifiedDiff("exported", "imported", exported, export(msgs, test.lang))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff != "" {
+				t.Errorf("the imported messages are not the exported ones:\n%s", diff)
+			}
+		})
+	}
+}
+
+func writeMessages(t *testing.T, dir, lang, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, lang+".json"), []byte("{"+content+"}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func loadMessages(t *testing.T, dir string) (*types.MessageBag, []parse.FileEntry) {
+	t.Helper()
+	wc := util.NewWarningsCollector()
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	messages, err := pipeline.Load(context.Background(), log, pipeline.Options{MessagesDirectory: dir, DefaultLanguage: "en"}, wc)
+	for _, warning := range wc.Warnings() {
+		t.Error(warning)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return messages.Bag, messages.Files
+}
+
+func TestCompareArguments(t *testing.T) {
+	dir := t.TempDir()
+	writeMessages(t, dir, "en", `"apples": "{name:str} has {count:int} apples",
+		"#plural": {"_arg": "count", "one": "One apple", "other": "{count} apples"},
+		"?size": {"count > 1": "Some {count:int}", "": "Few"}`)
+	msgs, files := loadMessages(t, dir)
+	importer := NewImporter(msgs, files, "en", util.NewWarningsCollector())
+
+	tests := []struct {
+		name     string
+		key      string
+		text     string
+		dropped  []string
+		invented []string
+	}{
+		{name: "same arguments", key: "apples", text: "{name} tiene {count} manzanas"},
+		{name: "dropped argument", key: "apples", text: "{name} tiene manzanas", dropped: []string{"count"}},
+		{name: "unknown argument", key: "apples", text: "{name} tiene {count} {fruit}", invented: []string{"fruit"}},
+		{name: "plural form without the count", key: "plural[#one]", text: "Jedno jabłko"},
+		{name: "plural form of the language", key: "plural[#few]", text: "{count} jabłka"},
+		{name: "plural form with an unknown argument", key: "plural[#many]", text: "{count} {fruit}", invented: []string{"fruit"}},
+		{name: "conditional branch without the count", key: "size[count > 1]", text: "Kilka"},
+		{name: "conditional branch with an argument of the message", key: "size[]", text: "Mało ({count})"},
+		{name: "conditional branch with an unknown argument", key: "size[count > 1]", text: "{fruit}", invented: []string{"fruit"}},
+		{name: "unknown unit", key: "unknown", text: "{fruit}"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dropped, invented := importer.CompareArguments("pl", test.key, test.text)
+			if !slices.Equal(dropped, test.dropped) || !slices.Equal(invented, test.invented) {
+				t.Errorf("expected dropped %v and unknown %v but got %v and %v", test.dropped, test.invented, dropped, invented)
+			}
+		})
+	}
+}
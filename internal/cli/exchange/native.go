@@ -0,0 +1,185 @@
+package exchange
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+var (
+	ErrNativeUnsupported util.Error = util.MakeError("could not export the message %s in the lang %s to %s: %s")
+	ErrNativeExactForm              = util.MakeError("the exact form =%s of the message %s in the lang %s can not be exported to %s and is dropped")
+	ErrNativeNameCollide            = util.MakeError("the messages %s and %s have the same %s name %s, only the first one is exported")
+)
+
+// NativeString is a message rendered for the native platforms, with printf style positional arguments numbered by
+// the position of the argument in the message
+type NativeString struct {
+	Path        []string
+	Description string
+	Text        string
+	// Plural holds the forms of plural messages, nil for the rest
+	Plural *NativePlural
+}
+
+type NativePlural struct {
+	// Argument selects the form
+	Argument *types.MessageArgument
+	Forms    []NativePluralForm
+}
+
+type NativePluralForm struct {
+	Quantity string
+	Text     string
+}
+
+// nativePlatform describes how a platform writes the arguments
+type nativePlatform struct {
+	Name string
+	// Placeholder renders an argument given its position, starting at 1, and its printf flags and verb
+	Placeholder func(position int, arg *types.MessageArgument, flags string, verb rune) string
+	// ExactZero maps the exact form =0 to the zero quantity if the message has no zero form
+	ExactZero bool
+}
+
+// nativeStrings renders the messages of the language with their references inlined. Conditional messages, ordinal
+// plurals and nested plurals have no equivalent in the native platforms, so they are reported to the collector and
+// skipped
+func nativeStrings(msgs *types.MessageBag, lang, defLang string, platform nativePlatform, wc *util.WarningsCollector) []NativeString {
+	var strs []NativeString
+	for _, instance := range msgs.Instances() {
+		value, found := instance.Message(lang)
+		if !found || instance.Untranslated(lang, defLang) {
+			continue
+		}
+		str := NativeString{Path: instance.Path(), Description: instance.Description(lang)}
+		if str.Description == "" {
+			str.Description = instance.Description(defLang)
+		}
+		unsupported := func(reason string) {
+			wc.AddWarning(ErrNativeUnsupported.WithArgs(instance.PathAsStr(), lang, platform.Name, reason))
+		}
+		if missing := instance.MissingBranches(lang, defLang); len(missing) > 0 {
+			unsupported(ErrMissingBranches.WithArgs(strings.Join(missing, ", ")).Error())
+			continue
+		}
+		value, err := InlineReferences(value, lang, defLang)
+		if err != nil {
+			unsupported(err.Error())
+			continue
+		}
+		switch v := value.(type) {
+		case *types.ValueConditional:
+			unsupported("conditional messages are not supported")
+			continue
+		case *types.ValuePlural:
+			if v.Ordinal {
+				unsupported("ordinal plurals are not supported")
+				continue
+			}
+			plural, ok := nativePlural(instance, lang, v, platform, wc)
+			if !ok {
+				unsupported("plural forms can only be literal, parametrized or multiline messages")
+				continue
+			}
+			str.Plural = plural
+		default:
+			str.Text = renderNative(instance, value, platform)
+		}
+		strs = append(strs, str)
+	}
+	return strs
+}
+
+func nativePlural(instance *types.MessageInstance, lang string, plural *types.ValuePlural, platform nativePlatform, wc *util.WarningsCollector) (*NativePlural, bool) {
+	native := &NativePlural{Argument: plural.Argument}
+	if declared, found := instance.Args().GetArgument(plural.Argument.Name); found {
+		native.Argument = declared
+	}
+	hasZero := slices.ContainsFunc(plural.Forms, func(form types.PluralForm) bool { return form.Category == types.PluralZero })
+	for _, exact := range plural.Exact {
+		if platform.ExactZero && exact.Number == "0" && !hasZero {
+			if !isLeaf(AsValue(exact.Value)) {
+				return nil, false
+			}
+			native.Forms = append(native.Forms, NativePluralForm{Quantity: "zero", Text: renderNative(instance, AsValue(exact.Value), platform)})
+			continue
+		}
+		wc.AddWarning(ErrNativeExactForm.WithArgs(exact.Number, instance.PathAsStr(), lang, platform.Name))
+	}
+	for _, form := range plural.Forms {
+		if !isLeaf(AsValue(form.Value)) {
+			return nil, false
+		}
+		native.Forms = append(native.Forms, NativePluralForm{Quantity: form.Category.String(), Text: renderNative(instance, AsValue(form.Value), platform)})
+	}
+	return native, true
+}
+
+// renderNative renders a literal, parametrized or multiline value. The % are escaped if the message has arguments
+// since then its text is a format
+func renderNative(instance *types.MessageInstance, value types.MessageValue, platform nativePlatform) string {
+	text := func(segment *types.ValueString) string {
+		if len(instance.Args().Args) > 0 {
+			return strings.ReplaceAll(segment.Message(), "%", "%%")
+		}
+		return segment.Message()
+	}
+	switch v := value.(type) {
+	case *types.ValueString:
+		return text(v)
+	case *types.ValueParametrized:
+		sb := strings.Builder{}
+		for i, arg := range v.Args {
+			sb.WriteString(text(v.TextSegments[i]))
+			argument, flags, verb := nativeArgument(instance, arg)
+			sb.WriteString(platform.Placeholder(argumentPosition(instance, argument.Name), argument, flags, verb))
+		}
+		sb.WriteString(text(v.TextSegments[len(v.TextSegments)-1]))
+		return sb.String()
+	case *types.ValueMultiline:
+		lines := make([]string, len(v.Lines))
+		for i, line := range v.Lines {
+			lines[i] = renderNative(instance, AsValue(line), platform)
+		}
+		return strings.Join(lines, "\n")
+	default:
+		panic("renderNative called with a conditional or plural value")
+	}
+}
+
+// nativeArgument returns the argument of the message used by a value, which has the type merged from all the
+// languages, and its format split into the flags, width and precision and the verb
+func nativeArgument(instance *types.MessageInstance, arg *types.UsedArgument) (*types.MessageArgument, string, rune) {
+	argument := arg.Argument
+	if declared, found := instance.Args().GetArgument(arg.Argument.Name); found {
+		argument = declared
+	}
+	format := arg.Format
+	if format == "" {
+		format = argument.DefaultFormat()
+	}
+	if argument.Type.Localized {
+		// the formats of the localized types are not printf formats
+		format = "v"
+		if argument.Type.IsNumeric() {
+			format = "g"
+		}
+	}
+	flags, verb := splitFormat(format)
+	return argument, flags, verb
+}
+
+// splitFormat splits a printf format like .2f into its flags, width and precision and its verb
+func splitFormat(format string) (string, rune) {
+	verb, size := utf8.DecodeLastRuneInString(format)
+	return format[:len(format)-size], verb
+}
+
+// argumentPosition returns the position, starting at 1, of an argument in the arguments of the message
+func argumentPosition(instance *types.MessageInstance, name string) int {
+	return slices.IndexFunc(instance.Args().Args, func(arg *types.MessageArgument) bool { return arg.Name == name }) + 1
+}
@@ -0,0 +1,202 @@
+package exchange
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+var (
+	ErrPoSyntax      util.Error = util.MakeError("invalid po file, line %d: %s")
+	ErrPoUnquote                = util.MakeError("invalid po string in line %d: %w")
+	ErrPoPluralEntry            = util.MakeError("the entry %s uses gettext plurals, which are not supported. Plurals are exported as one entry for each form")
+)
+
+// PoEntry is an entry of a gettext po file. The context is the key of a unit and the id its text in the default language
+type PoEntry struct {
+	Comments []string
+	Flags    []string
+	Context  string
+	Id       string
+	IdPlural string
+	Str      string
+}
+
+func (e *PoEntry) IsFuzzy() bool { return slices.Contains(e.Flags, "fuzzy") }
+
+// PoEntries returns the entries of the po file of a language, one for each unit to translate into the language, see
+// TranslationUnits. The entries of the template, without language, are the units of the default language
+func PoEntries(msgs *types.MessageBag, lang, defLang string) []PoEntry {
+	var entries []PoEntry
+	var langs []string
+	if lang != "" {
+		langs = []string{lang}
+	}
+	for _, instance := range msgs.Instances() {
+		translated, hasLang := instance.Message(lang)
+		for _, unit := range TranslationUnits(instance, defLang, langs...) {
+			entry := PoEntry{Context: unit.Key(), Id: Text(unit.Value)}
+			if args := instance.Args().Args; len(args) > 0 {
+				entry.Comments = []string{"Arguments: " + strings.Join(util.Map(args, func(_ int, a **types.MessageArgument) string {
+					return "{" + (*a).Name + "} " + (*a).Type.Name
+				}), ", ")}
+			}
+			if hasLang {
+				if value, found := Select(translated, unit.Selectors); found && isLeaf(value) {
+					entry.Str = Text(value)
+				}
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+func isLeaf(value types.MessageValue) bool {
+	switch value.(type) {
+	case *types.ValueConditional, *types.ValuePlural:
+		return false
+	default:
+		return true
+	}
+}
+
+// WritePo writes a po file. If lang is empty, the file is written as a po template
+func WritePo(w io.Writer, lang string, entries []PoEntry) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# Generated using https://github.com/MrNemo64/go-n-i18n\n")
+	fmt.Fprintf(bw, "msgid \"\"\n")
+	fmt.Fprintf(bw, "msgstr \"\"\n")
+	fmt.Fprintf(bw, "\"Content-Type: text/plain; charset=UTF-8\\n\"\n")
+	if lang != "" {
+		fmt.Fprintf(bw, "\"Language: %s\\n\"\n", lang)
+	}
+	for _, entry := range entries {
+		fmt.Fprintf(bw, "\n")
+		for _, comment := range entry.Comments {
+			fmt.Fprintf(bw, "#. %s\n", comment)
+		}
+		if len(entry.Flags) > 0 {
+			fmt.Fprintf(bw, "#, %s\n", strings.Join(entry.Flags, ", "))
+		}
+		writePoString(bw, "msgctxt", entry.Context)
+		writePoString(bw, "msgid", entry.Id)
+		writePoString(bw, "msgstr", entry.Str)
+	}
+	return bw.Flush()
+}
+
+// writePoString writes a keyword and its string, splitting multi-line strings after each new line
+func writePoString(w io.Writer, keyword, str string) {
+	if !strings.Contains(str, "\n") || strings.Index(str, "\n") == len(str)-1 {
+		fmt.Fprintf(w, "%s %s\n", keyword, strconv.Quote(str))
+		return
+	}
+	fmt.Fprintf(w, "%s \"\"\n", keyword)
+	lines := strings.SplitAfter(str, "\n")
+	for _, line := range lines {
+		if line != "" {
+			fmt.Fprintf(w, "%s\n", strconv.Quote(line))
+		}
+	}
+}
+
+// ReadPo reads the entries of a po file and the fields of its header
+func ReadPo(r io.Reader) ([]PoEntry, map[string]string, error) {
+	var entries []PoEntry
+	header := make(map[string]string)
+	current := PoEntry{}
+	started := false // something of the current entry was read
+	seenStr := false // the msgstr of the current entry was read, anything but a string starts a new entry
+	var target *string
+	lineNumber := 0
+
+	flush := func() {
+		if started {
+			if current.Id == "" && current.Context == "" {
+				for _, line := range strings.Split(current.Str, "\n") {
+					if key, value, found := strings.Cut(line, ":"); found {
+						header[strings.TrimSpace(key)] = strings.TrimSpace(value)
+					}
+				}
+			} else {
+				entries = append(entries, current)
+			}
+		}
+		current = PoEntry{}
+		started = false
+		seenStr = false
+		target = nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "\"") {
+			if target == nil {
+				return nil, nil, ErrPoSyntax.WithArgs(lineNumber, "string without keyword")
+			}
+			str, err := strconv.Unquote(line)
+			if err != nil {
+				return nil, nil, ErrPoUnquote.WithArgs(lineNumber, err)
+			}
+			*target += str
+			continue
+		}
+		if seenStr {
+			flush()
+		}
+		started = true
+		if strings.HasPrefix(line, "#,") {
+			for _, flag := range strings.Split(line[2:], ",") {
+				current.Flags = append(current.Flags, strings.TrimSpace(flag))
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		keyword, rest, found := strings.Cut(line, " ")
+		if !found {
+			return nil, nil, ErrPoSyntax.WithArgs(lineNumber, line)
+		}
+		str, err := strconv.Unquote(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, nil, ErrPoUnquote.WithArgs(lineNumber, err)
+		}
+		switch {
+		case keyword == "msgctxt":
+			target = &current.Context
+		case keyword == "msgid":
+			target = &current.Id
+		case keyword == "msgid_plural":
+			target = &current.IdPlural
+		case keyword == "msgstr" || keyword == "msgstr[0]":
+			target = &current.Str
+			seenStr = true
+		case strings.HasPrefix(keyword, "msgstr["):
+			target = new(string) // only the first form is kept, gettext plurals are reported when importing
+			seenStr = true
+		default:
+			return nil, nil, ErrPoSyntax.WithArgs(lineNumber, line)
+		}
+		*target += str
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	flush()
+	return entries, header, nil
+}
@@ -0,0 +1,31 @@
+package exchange
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+)
+
+func TestPoRoundTrip(t *testing.T) {
+	testRoundTrip(t, append(roundTripMessages, pluralRoundTripMessages...), func(msgs *types.MessageBag, lang string) string {
+		sb := strings.Builder{}
+		if err := WritePo(&sb, lang, PoEntries(msgs, lang, "en")); err != nil {
+			t.Fatal(err)
+		}
+		return sb.String()
+	}, func(im *Importer, lang, exported string) {
+		entries, header, err := ReadPo(strings.NewReader(exported))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if header["Language"] != lang {
+			t.Errorf("expected the language %s, got %q", lang, header["Language"])
+		}
+		for _, entry := range entries {
+			if entry.Str != "" {
+				im.Set(lang, entry.Context, entry.Str)
+			}
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package exchange
+
+import (
+	"fmt"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/assert"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+var (
+	ErrInlineReference util.Error = util.MakeError("the referenced message %s can not be inlined since it is a %s message")
+)
+
+// InlineReferences returns the value with its references replaced by the message they reference in the language, or
+// in the default language if the referenced message does not have it. The references must be resolved and only
+// literal, parametrized and multiline messages can be inlined, multiline ones joining their lines with \n
+func InlineReferences(value types.MessageValue, lang, defLang string) (types.MessageValue, error) {
+	switch v := value.(type) {
+	case *types.ValueString:
+		return v, nil
+	case *types.ValueParametrized:
+		segments := &inlinedSegments{texts: []string{""}}
+		if err := segments.add(v, lang, defLang); err != nil {
+			return nil, err
+		}
+		return segments.value(), nil
+	case *types.ValueMultiline:
+		inlined := *v
+		inlined.Lines = make([]types.Multilineable, len(v.Lines))
+		for i, line := range v.Lines {
+			value, err := InlineReferences(AsValue(line), lang, defLang)
+			if err != nil {
+				return nil, err
+			}
+			inlined.Lines[i] = value.(types.Multilineable)
+		}
+		return &inlined, nil
+	case *types.ValueConditional:
+		inlined := *v
+		inlined.Conditions = make([]types.Condition, len(v.Conditions))
+		for i, condition := range v.Conditions {
+			value, err := InlineReferences(AsValue(condition.Value), lang, defLang)
+			if err != nil {
+				return nil, err
+			}
+			inlined.Conditions[i] = types.Condition{Condition: condition.Condition, Value: value.(types.Conditionable)}
+		}
+		if v.Else != nil {
+			value, err := InlineReferences(AsValue(v.Else), lang, defLang)
+			if err != nil {
+				return nil, err
+			}
+			inlined.Else = value.(types.Conditionable)
+		}
+		return &inlined, nil
+	case *types.ValuePlural:
+		inlined := *v
+		inlined.Exact = make([]types.PluralExact, len(v.Exact))
+		for i, exact := range v.Exact {
+			value, err := InlineReferences(AsValue(exact.Value), lang, defLang)
+			if err != nil {
+				return nil, err
+			}
+			inlined.Exact[i] = types.PluralExact{Number: exact.Number, Value: value.(types.Pluralizable)}
+		}
+		inlined.Forms = make([]types.PluralForm, len(v.Forms))
+		for i, form := range v.Forms {
+			value, err := InlineReferences(AsValue(form.Value), lang, defLang)
+			if err != nil {
+				return nil, err
+			}
+			inlined.Forms[i] = types.PluralForm{Category: form.Category, Value: value.(types.Pluralizable)}
+		}
+		return &inlined, nil
+	default:
+		panic(fmt.Errorf("unknown MessageValue type %+v", value))
+	}
+}
+
+// inlinedSegments collects the text segments and arguments of a parametrized value while its references are inlined
+type inlinedSegments struct {
+	texts []string
+	args  []*types.UsedArgument
+}
+
+func (s *inlinedSegments) add(value types.MessageValue, lang, defLang string) error {
+	switch v := value.(type) {
+	case *types.ValueString:
+		s.texts[len(s.texts)-1] += v.Message()
+	case *types.ValueParametrized:
+		for i, arg := range v.Args {
+			s.texts[len(s.texts)-1] += v.TextSegments[i].Message()
+			if !arg.IsReference() {
+				s.args = append(s.args, arg)
+				s.texts = append(s.texts, "")
+				continue
+			}
+			target := arg.Reference.Target
+			assert.True(target != nil, "references are resolved when the messages are loaded")
+			referenced, found := target.Message(lang)
+			if !found {
+				referenced = target.MessageMust(defLang)
+			}
+			switch referenced.(type) {
+			case *types.ValueConditional:
+				return ErrInlineReference.WithArgs(target.PathAsStr(), "conditional")
+			case *types.ValuePlural:
+				return ErrInlineReference.WithArgs(target.PathAsStr(), "plural")
+			}
+			if err := s.add(referenced, lang, defLang); err != nil {
+				return err
+			}
+		}
+		s.texts[len(s.texts)-1] += v.TextSegments[len(v.TextSegments)-1].Message()
+	case *types.ValueMultiline:
+		for i, line := range v.Lines {
+			if i > 0 {
+				s.texts[len(s.texts)-1] += "\n"
+			}
+			if err := s.add(AsValue(line), lang, defLang); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *inlinedSegments) value() types.MessageValue {
+	if len(s.args) == 0 {
+		return types.NewStringLiteralValue(s.texts[0])
+	}
+	parametrized, err := types.NewParametrizedStringValue(util.Map(s.texts, func(_ int, t *string) *types.ValueString { return types.NewStringLiteralValue(*t) }), s.args)
+	assert.NoError(err) // every argument is followed by a text segment
+	return parametrized
+}
@@ -0,0 +1,244 @@
+package exchange
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/cldr"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+var (
+	ErrMissingBranches util.Error = util.MakeError("the branches %s are not translated")
+)
+
+// Unit is a translatable text of a message. Conditional and plural messages are flattened into one unit per
+// branch, identified by a selector appended to the path of the message: `[condition]` for conditions, `[]` for
+// the else branch, `[#category]` for plural forms and `[#=number]` for exact plural forms
+type Unit struct {
+	Path      []string
+	Selectors []string
+	Value     types.MessageValue
+}
+
+func (u Unit) Key() string {
+	return types.PathAsStr(u.Path) + SelectorsAsStr(u.Selectors)
+}
+
+func SelectorsAsStr(selectors []string) string {
+	sb := strings.Builder{}
+	for _, selector := range selectors {
+		sb.WriteString("[" + selector + "]")
+	}
+	return sb.String()
+}
+
+func ConditionSelector(condition string) string           { return condition }
+func PluralSelector(category types.PluralCategory) string { return "#" + category.String() }
+func PluralExactSelector(number string) string            { return "#=" + number }
+
+// TranslationUnits returns the units of the message of the default language to translate into the languages. Plurals
+// have a unit for each CLDR category of any of the languages, the ones the default language does not have are its
+// other form, and the exact forms of the default language. Without languages the units are the ones of the default
+// language
+func TranslationUnits(instance *types.MessageInstance, defLang string, langs ...string) []Unit {
+	value, found := instance.Message(defLang)
+	if !found {
+		return nil
+	}
+	if len(langs) == 0 {
+		return flatten(instance.Path(), nil, value, nil)
+	}
+	return flatten(instance.Path(), nil, value, func(plural *types.ValuePlural) []types.PluralCategory {
+		var categories []types.PluralCategory
+		for _, category := range types.PluralCategories() {
+			_, inDefault := plural.Form(category)
+			if slices.ContainsFunc(langs, func(lang string) bool {
+				if lang == defLang {
+					return inDefault
+				}
+				return hasPluralCategory(lang, plural.Ordinal, category)
+			}) {
+				categories = append(categories, category)
+			}
+		}
+		return categories
+	})
+}
+
+// hasPluralCategory tells if the cardinal or ordinal plurals of the language use the category
+func hasPluralCategory(lang string, ordinal bool, category types.PluralCategory) bool {
+	rules, _ := cldr.CardinalRules(lang)
+	if ordinal {
+		rules, _ = cldr.OrdinalRules(lang)
+	}
+	for _, name := range rules.Categories() {
+		if name == category.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// flatten returns the units of the value. The plurals have a unit for each of their forms, or for each of the
+// categories given by categories if it is not nil
+func flatten(path []string, selectors []string, value types.MessageValue, categories func(*types.ValuePlural) []types.PluralCategory) []Unit {
+	nested := func(selector string, v any) []Unit {
+		return flatten(path, append(append([]string{}, selectors...), selector), AsValue(v), categories)
+	}
+	switch v := value.(type) {
+	case *types.ValueConditional:
+		var units []Unit
+		for _, condition := range v.Conditions {
+			units = append(units, nested(condition.Condition, condition.Value)...)
+		}
+		if v.Else != nil {
+			units = append(units, nested("", v.Else)...)
+		}
+		return units
+	case *types.ValuePlural:
+		var units []Unit
+		for _, exact := range v.Exact {
+			units = append(units, nested(PluralExactSelector(exact.Number), exact.Value)...)
+		}
+		if categories == nil {
+			for _, form := range v.Forms {
+				units = append(units, nested(PluralSelector(form.Category), form.Value)...)
+			}
+			return units
+		}
+		for _, category := range categories(v) {
+			form, found := v.Form(category)
+			if !found {
+				form = v.Other()
+			}
+			units = append(units, nested(PluralSelector(category), form)...)
+		}
+		return units
+	default:
+		return []Unit{{Path: path, Selectors: selectors, Value: value}}
+	}
+}
+
+// SelectReference returns the branch of the message of the default language translated by the unit of the language
+// identified by the selectors. The plural categories of the language that the default language does not have
+// translate its other form
+func SelectReference(value types.MessageValue, selectors []string, lang string) (types.MessageValue, bool) {
+	for _, selector := range selectors {
+		branch, found := Select(value, []string{selector})
+		if plural, isPlural := value.(*types.ValuePlural); !found && isPlural {
+			name, _ := strings.CutPrefix(selector, "#")
+			category, isCategory := types.ParsePluralCategory(name)
+			if isCategory && selector == PluralSelector(category) && hasPluralCategory(lang, plural.Ordinal, category) {
+				branch, found = AsValue(plural.Other()), true
+			}
+		}
+		if !found {
+			return nil, false
+		}
+		value = branch
+	}
+	return value, true
+}
+
+// Select returns the value of the branch identified by the selectors
+func Select(value types.MessageValue, selectors []string) (types.MessageValue, bool) {
+	for _, selector := range selectors {
+		found := false
+		switch v := value.(type) {
+		case *types.ValueConditional:
+			if selector == "" && v.Else != nil {
+				value, found = AsValue(v.Else), true
+			}
+			for _, condition := range v.Conditions {
+				if !found && condition.Condition == selector {
+					value, found = AsValue(condition.Value), true
+				}
+			}
+		case *types.ValuePlural:
+			for _, exact := range v.Exact {
+				if !found && PluralExactSelector(exact.Number) == selector {
+					value, found = AsValue(exact.Value), true
+				}
+			}
+			for _, form := range v.Forms {
+				if !found && PluralSelector(form.Category) == selector {
+					value, found = AsValue(form.Value), true
+				}
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// ParseKey splits a unit key into the path of the message and the selectors of the unit
+func ParseKey(key string) ([]string, []string, bool) {
+	idx := strings.Index(key, "[")
+	if idx < 0 {
+		return strings.Split(key, "."), nil, true
+	}
+	path := strings.Split(key[:idx], ".")
+	var selectors []string
+	depth := 0
+	start := 0
+	for i := idx; i < len(key); i++ {
+		switch key[i] {
+		case '[':
+			if depth == 0 {
+				start = i + 1
+			}
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				selectors = append(selectors, key[start:i])
+			}
+			if depth < 0 {
+				return nil, nil, false
+			}
+		default:
+			if depth == 0 {
+				return nil, nil, false
+			}
+		}
+	}
+	return path, selectors, depth == 0
+}
+
+// Text renders a literal, parametrized or multiline value with its arguments as {name} and its references as {@path}
+func Text(value types.MessageValue) string {
+	switch v := value.(type) {
+	case *types.ValueString:
+		return v.Message()
+	case *types.ValueParametrized:
+		sb := strings.Builder{}
+		for i, arg := range v.Args {
+			sb.WriteString(v.TextSegments[i].Message())
+			sb.WriteString(segmentPlaceholder(arg, ""))
+		}
+		sb.WriteString(v.TextSegments[len(v.TextSegments)-1].Message())
+		return sb.String()
+	case *types.ValueMultiline:
+		lines := make([]string, len(v.Lines))
+		for i, line := range v.Lines {
+			lines[i] = Text(AsValue(line))
+		}
+		return strings.Join(lines, "\n")
+	default:
+		panic(fmt.Errorf("can not render the value %+v as text", value))
+	}
+}
+
+// AsValue casts the content of a multiline, conditional or plural value to a MessageValue
+func AsValue(v any) types.MessageValue {
+	value, ok := v.(types.MessageValue)
+	if !ok {
+		panic(fmt.Errorf("%+v is not a MessageValue", v))
+	}
+	return value
+}
@@ -0,0 +1,59 @@
+package exchange
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTranslationUnits(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string // the en message apples
+		langs   []string
+		want    []string
+	}{
+		{
+			name:    "default language",
+			message: `"#apples": {"=0": "None", "one": "One", "other": "Many"}`,
+			want:    []string{"apples[#=0]", "apples[#one]", "apples[#other]"},
+		},
+		{
+			name:    "more categories",
+			message: `"#apples": {"=0": "None", "one": "One", "other": "Many"}`,
+			langs:   []string{"pl"},
+			want:    []string{"apples[#=0]", "apples[#one]", "apples[#few]", "apples[#many]", "apples[#other]"},
+		},
+		{
+			name:    "less categories",
+			message: `"#apples": {"one": "One", "other": "Many"}`,
+			langs:   []string{"ja"},
+			want:    []string{"apples[#other]"},
+		},
+		{
+			name:    "categories of every language",
+			message: `"#apples": {"one": "One", "other": "Many"}`,
+			langs:   []string{"en", "ar", "ja"},
+			want:    []string{"apples[#zero]", "apples[#one]", "apples[#two]", "apples[#few]", "apples[#many]", "apples[#other]"},
+		},
+		{
+			name:    "ordinal",
+			message: `"#apples": {"_ordinal": true, "one": "st", "two": "nd", "few": "rd", "other": "th"}`,
+			langs:   []string{"fr"},
+			want:    []string{"apples[#one]", "apples[#other]"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeMessages(t, dir, "en", test.message)
+			msgs, _ := loadMessages(t, dir)
+			var keys []string
+			for _, unit := range TranslationUnits(msgs.Instances()[0], "en", test.langs...) {
+				keys = append(keys, unit.Key())
+			}
+			if !slices.Equal(keys, test.want) {
+				t.Errorf("expected the units %v, got %v", test.want, keys)
+			}
+		})
+	}
+}
@@ -0,0 +1,462 @@
+package exchange
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/parse"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+const (
+	XliffNamespace   = "urn:oasis:names:tc:xliff:document:2.0"
+	xliffTypeSubtype = "gni:"
+	// xliffReferenceSubtype is the subtype of the placeholders of references, written as {@path}
+	xliffReferenceSubtype = "gni:reference"
+	xliffFileId           = "messages"
+)
+
+var (
+	ErrXliffSyntax      util.Error = util.MakeError("invalid xliff file: %w")
+	ErrXliffUnknownData            = util.MakeError("the unit %s has a placeholder with the unknown data %s")
+)
+
+// XliffUnit is a unit of a xliff file. The id of the unit is built from the path of the message and the branch of
+// conditional and plural messages (`.if-0`, `.else`, `.plural-one`, `.exact-0`), all the units of a conditional
+// or plural message are placed in a group identified by the path of the message
+type XliffUnit struct {
+	Id      string
+	Group   string
+	Key     string
+	Source  []XliffSegment
+	Target  []XliffSegment // nil if the language does not have the unit or it is untranslated
+	Data    []XliffData
+	Comment string
+}
+
+// XliffSegment is the content of a segment, made of text and placeholders. Each line of a multiline message is a segment
+type XliffSegment []XliffPiece
+
+// XliffPiece is either text or a placeholder referencing the data with the original argument
+type XliffPiece struct {
+	Text        string
+	Placeholder string // id of the placeholder, empty if the piece is text
+	DataRef     string
+	Disp        string
+	SubType     string
+}
+
+// XliffData is the original representation of an argument, `{name:type:format}`
+type XliffData struct {
+	Id       string
+	Original string
+}
+
+// XliffUnits returns the units of the xliff file of a language, one for each unit to translate into the language, see
+// TranslationUnits
+func XliffUnits(msgs *types.MessageBag, lang, defLang string) []XliffUnit {
+	var units []XliffUnit
+	for _, instance := range msgs.Instances() {
+		reference := instance.MessageMust(defLang)
+		translated, hasLang := instance.Message(lang)
+		unitsOfInstance := TranslationUnits(instance, defLang, lang)
+		for _, unit := range unitsOfInstance {
+			xu := XliffUnit{Id: XliffUnitId(reference, unit, lang), Key: unit.Key()}
+			if len(unitsOfInstance) > 1 || len(unit.Selectors) > 0 {
+				xu.Group = instance.PathAsStr()
+			}
+			if len(unit.Selectors) > 0 {
+				xu.Comment = "Branch " + SelectorsAsStr(unit.Selectors)
+			}
+			data := make(map[string]string)
+			xu.Source = xu.segments(instance, unit.Value, data)
+			if hasLang {
+				if value, found := Select(translated, unit.Selectors); found && isLeaf(value) && !types.IsUntranslated(value) {
+					xu.Target = xu.segments(instance, value, data)
+				}
+			}
+			matchPlaceholderIds(xu.Source, xu.Target)
+			units = append(units, xu)
+		}
+	}
+	return units
+}
+
+// XliffUnitId returns the id of the unit of the language of the message whose value in the default language is
+// reference
+func XliffUnitId(reference types.MessageValue, unit Unit, lang string) string {
+	sb := strings.Builder{}
+	sb.WriteString(types.PathAsStr(unit.Path))
+	value := reference
+	for _, selector := range unit.Selectors {
+		sb.WriteString(".")
+		switch v := value.(type) {
+		case *types.ValueConditional:
+			if selector == "" {
+				sb.WriteString("else")
+			}
+			for i, condition := range v.Conditions {
+				if condition.Condition == selector {
+					sb.WriteString("if-" + strconv.Itoa(i))
+					break
+				}
+			}
+		case *types.ValuePlural:
+			if number, isExact := strings.CutPrefix(selector, PluralExactSelector("")); isExact {
+				sb.WriteString("exact-" + number)
+			} else {
+				sb.WriteString("plural-" + strings.TrimPrefix(selector, "#"))
+			}
+		}
+		value, _ = SelectReference(value, []string{selector}, lang)
+	}
+	return sb.String()
+}
+
+// segments renders the lines of a value as segments, adding the arguments to the data of the unit
+func (u *XliffUnit) segments(instance *types.MessageInstance, value types.MessageValue, data map[string]string) []XliffSegment {
+	if multiline, ok := value.(*types.ValueMultiline); ok {
+		segments := make([]XliffSegment, len(multiline.Lines))
+		for i, line := range multiline.Lines {
+			segments[i] = u.segment(instance, AsValue(line), data)
+		}
+		return segments
+	}
+	return []XliffSegment{u.segment(instance, value, data)}
+}
+
+// reference returns the placeholder of a reference, whose original data is the {@path} reference itself
+func (u *XliffUnit) reference(reference *types.MessageReference, data map[string]string) XliffPiece {
+	original := "{@" + reference.PathAsStr() + "}"
+	return XliffPiece{DataRef: u.dataId(original, data), Disp: original, SubType: xliffReferenceSubtype}
+}
+
+// dataId returns the id of the original data of a placeholder, adding it to the unit the first time it is used
+func (u *XliffUnit) dataId(original string, data map[string]string) string {
+	id, found := data[original]
+	if !found {
+		id = "d" + strconv.Itoa(len(data)+1)
+		data[original] = id
+		u.Data = append(u.Data, XliffData{Id: id, Original: original})
+	}
+	return id
+}
+
+func (u *XliffUnit) segment(instance *types.MessageInstance, value types.MessageValue, data map[string]string) XliffSegment {
+	switch v := value.(type) {
+	case *types.ValueString:
+		return XliffSegment{{Text: v.Message()}}
+	case *types.ValueParametrized:
+		var segment XliffSegment
+		for i, used := range v.Args {
+			segment = append(segment, XliffPiece{Text: v.TextSegments[i].Message()})
+			if used.IsReference() {
+				segment = append(segment, u.reference(used.Reference, data))
+				continue
+			}
+			arg, found := instance.Args().GetArgument(used.Argument.Name)
+			if !found {
+				arg = used.Argument
+			}
+			original := "{" + arg.Name + ":" + arg.Type.Name
+			if used.Format != "" {
+				original += ":" + used.Format
+			}
+			original += "}"
+			segment = append(segment, XliffPiece{
+				DataRef: u.dataId(original, data),
+				Disp:    "{" + arg.Name + "}",
+				SubType: xliffTypeSubtype + arg.Type.Name,
+			})
+		}
+		return append(segment, XliffPiece{Text: v.TextSegments[len(v.TextSegments)-1].Message()})
+	default:
+		panic(fmt.Errorf("can not write the value %+v as a xliff segment", value))
+	}
+}
+
+// matchPlaceholderIds numbers the placeholders of the source and gives the placeholders of the target the id of
+// the placeholder of the source with the same data, as xliff requires
+func matchPlaceholderIds(source, target []XliffSegment) {
+	next := 1
+	byData := make(map[string][]string)
+	for _, segment := range source {
+		for i := range segment {
+			if segment[i].DataRef != "" {
+				segment[i].Placeholder = strconv.Itoa(next)
+				byData[segment[i].DataRef] = append(byData[segment[i].DataRef], segment[i].Placeholder)
+				next++
+			}
+		}
+	}
+	for _, segment := range target {
+		for i := range segment {
+			if segment[i].DataRef == "" {
+				continue
+			}
+			if ids := byData[segment[i].DataRef]; len(ids) > 0 {
+				segment[i].Placeholder = ids[0]
+				byData[segment[i].DataRef] = ids[1:]
+			} else {
+				segment[i].Placeholder = strconv.Itoa(next)
+				next++
+			}
+		}
+	}
+}
+
+// WriteXliff writes a xliff 2.0 file with the units
+func WriteXliff(w io.Writer, srcLang, trgLang string, units []XliffUnit) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	fmt.Fprintf(bw, "<!-- Generated using https://github.com/MrNemo64/go-n-i18n -->\n")
+	fmt.Fprintf(bw, "<xliff xmlns=\"%s\" version=\"2.0\" srcLang=\"%s\" trgLang=\"%s\">\n", XliffNamespace, xmlEscape(srcLang), xmlEscape(trgLang))
+	fmt.Fprintf(bw, "  <file id=\"%s\">\n", xliffFileId)
+	group := ""
+	for _, unit := range units {
+		if unit.Group != group {
+			if group != "" {
+				fmt.Fprintf(bw, "    </group>\n")
+			}
+			if unit.Group != "" {
+				fmt.Fprintf(bw, "    <group id=\"%s\" name=\"%s\">\n", xmlEscape(unit.Group), xmlEscape(unit.Group))
+			}
+			group = unit.Group
+		}
+		indent := "    "
+		if group != "" {
+			indent += "  "
+		}
+		writeXliffUnit(bw, indent, unit)
+	}
+	if group != "" {
+		fmt.Fprintf(bw, "    </group>\n")
+	}
+	fmt.Fprintf(bw, "  </file>\n")
+	fmt.Fprintf(bw, "</xliff>\n")
+	return bw.Flush()
+}
+
+func writeXliffUnit(w io.Writer, indent string, unit XliffUnit) {
+	fmt.Fprintf(w, "%s<unit id=\"%s\" name=\"%s\">\n", indent, xmlEscape(unit.Id), xmlEscape(unit.Key))
+	if unit.Comment != "" {
+		fmt.Fprintf(w, "%s  <notes>\n", indent)
+		fmt.Fprintf(w, "%s    <note category=\"location\">%s</note>\n", indent, xmlEscape(unit.Comment))
+		fmt.Fprintf(w, "%s  </notes>\n", indent)
+	}
+	if len(unit.Data) > 0 {
+		fmt.Fprintf(w, "%s  <originalData>\n", indent)
+		for _, data := range unit.Data {
+			fmt.Fprintf(w, "%s    <data id=\"%s\">%s</data>\n", indent, data.Id, xmlEscape(data.Original))
+		}
+		fmt.Fprintf(w, "%s  </originalData>\n", indent)
+	}
+	for i, source := range unit.Source {
+		state := "initial"
+		if unit.Target != nil {
+			state = "translated"
+		}
+		fmt.Fprintf(w, "%s  <segment state=\"%s\">\n", indent, state)
+		fmt.Fprintf(w, "%s    <source xml:space=\"preserve\">%s</source>\n", indent, xliffSegmentXml(source))
+		if unit.Target != nil {
+			target := XliffSegment{}
+			if i < len(unit.Target) {
+				target = unit.Target[i]
+			}
+			if i == len(unit.Source)-1 && len(unit.Target) > len(unit.Source) { // the translation has more lines than the source
+				for _, extra := range unit.Target[i+1:] {
+					target = append(append(target, XliffPiece{Text: "\n"}), extra...)
+				}
+			}
+			fmt.Fprintf(w, "%s    <target xml:space=\"preserve\">%s</target>\n", indent, xliffSegmentXml(target))
+		}
+		fmt.Fprintf(w, "%s  </segment>\n", indent)
+	}
+	fmt.Fprintf(w, "%s</unit>\n", indent)
+}
+
+func xliffSegmentXml(segment XliffSegment) string {
+	sb := strings.Builder{}
+	for _, piece := range segment {
+		if piece.DataRef == "" {
+			sb.WriteString(xmlEscape(piece.Text))
+			continue
+		}
+		fmt.Fprintf(&sb, "<ph id=\"%s\" dataRef=\"%s\" disp=\"%s\" type=\"fmt\" subType=\"%s\"/>",
+			piece.Placeholder, piece.DataRef, xmlEscape(piece.Disp), xmlEscape(piece.SubType))
+	}
+	return sb.String()
+}
+
+func xmlEscape(text string) string {
+	sb := strings.Builder{}
+	xml.EscapeText(&sb, []byte(text))
+	return sb.String()
+}
+
+type xliffDocument struct {
+	XMLName xml.Name    `xml:"xliff"`
+	SrcLang string      `xml:"srcLang,attr"`
+	TrgLang string      `xml:"trgLang,attr"`
+	Files   []xliffNode `xml:"file"`
+}
+
+// xliffNode is a file or a group
+type xliffNode struct {
+	Groups []xliffNode        `xml:"group"`
+	Units  []xliffUnitElement `xml:"unit"`
+}
+
+type xliffUnitElement struct {
+	Id   string `xml:"id,attr"`
+	Name string `xml:"name,attr"`
+	Data []struct {
+		Id       string `xml:"id,attr"`
+		Original string `xml:",chardata"`
+	} `xml:"originalData>data"`
+	Segments []struct {
+		Source struct {
+			Content string `xml:",innerxml"`
+		} `xml:"source"`
+		Target *struct {
+			Content string `xml:",innerxml"`
+		} `xml:"target"`
+	} `xml:"segment"`
+}
+
+// XliffTranslation is the translated text of a unit of a xliff file, with its arguments as {name}
+type XliffTranslation struct {
+	Id   string
+	Name string
+	Text string
+	// Missing is true if any segment of the unit has no target
+	Missing bool
+}
+
+// ReadXliff reads the translations of a xliff 2.0 file and its target language
+func ReadXliff(r io.Reader) ([]XliffTranslation, string, error) {
+	var document xliffDocument
+	if err := xml.NewDecoder(r).Decode(&document); err != nil {
+		return nil, "", ErrXliffSyntax.WithArgs(err)
+	}
+	var translations []XliffTranslation
+	var visit func(node xliffNode) error
+	visit = func(node xliffNode) error {
+		for _, unit := range node.Units {
+			translation, err := readXliffUnit(unit)
+			if err != nil {
+				return err
+			}
+			translations = append(translations, translation)
+		}
+		for _, group := range node.Groups {
+			if err := visit(group); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, file := range document.Files {
+		if err := visit(file); err != nil {
+			return nil, "", err
+		}
+	}
+	return translations, document.TrgLang, nil
+}
+
+func readXliffUnit(unit xliffUnitElement) (XliffTranslation, error) {
+	translation := XliffTranslation{Id: unit.Id, Name: unit.Name}
+	names := make(map[string]string)
+	for _, data := range unit.Data {
+		if path, isReference := strings.CutPrefix(data.Original, "{@"); isReference {
+			names[data.Id] = "@" + strings.TrimSuffix(path, "}")
+			continue
+		}
+		groups := parse.ArgumentExtractor.FindStringSubmatch(data.Original)
+		if groups == nil {
+			return translation, ErrXliffUnknownData.WithArgs(unit.Id, data.Id)
+		}
+		names[data.Id] = groups[1]
+	}
+	// placeholders of the target may only reference the placeholder of the source by id
+	sourceRefs := make(map[string]string)
+	for _, segment := range unit.Segments {
+		if err := xliffInlineText(segment.Source.Content, func(id, dataRef string) (string, error) {
+			sourceRefs[id] = dataRef
+			return "", nil
+		}, nil); err != nil {
+			return translation, ErrXliffSyntax.WithArgs(err)
+		}
+	}
+
+	lines := make([]string, 0, len(unit.Segments))
+	for _, segment := range unit.Segments {
+		if segment.Target == nil {
+			translation.Missing = true
+			continue
+		}
+		sb := strings.Builder{}
+		err := xliffInlineText(segment.Target.Content, func(id, dataRef string) (string, error) {
+			if dataRef == "" {
+				dataRef = sourceRefs[id]
+			}
+			name, found := names[dataRef]
+			if !found {
+				return "", ErrXliffUnknownData.WithArgs(unit.Id, dataRef)
+			}
+			return "{" + name + "}", nil
+		}, &sb)
+		if err != nil {
+			return translation, err
+		}
+		lines = append(lines, sb.String())
+	}
+	translation.Text = strings.Join(lines, "\n")
+	return translation, nil
+}
+
+// xliffInlineText writes the text of the content of a source or target into sb, replacing the placeholders with the
+// result of placeholder. The tags of other inline elements are dropped, keeping their text
+func xliffInlineText(content string, placeholder func(id, dataRef string) (string, error), sb *strings.Builder) error {
+	decoder := xml.NewDecoder(strings.NewReader(content))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.CharData:
+			if sb != nil {
+				sb.Write(t)
+			}
+		case xml.StartElement:
+			if t.Name.Local != "ph" {
+				continue
+			}
+			var id, dataRef string
+			for _, attr := range t.Attr {
+				switch attr.Name.Local {
+				case "id":
+					id = attr.Value
+				case "dataRef":
+					dataRef = attr.Value
+				}
+			}
+			text, err := placeholder(id, dataRef)
+			if err != nil {
+				return err
+			}
+			if sb != nil {
+				sb.WriteString(text)
+			}
+		}
+	}
+}
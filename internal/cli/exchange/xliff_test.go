@@ -0,0 +1,31 @@
+package exchange
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+)
+
+func TestXliffRoundTrip(t *testing.T) {
+	testRoundTrip(t, append(roundTripMessages, pluralRoundTripMessages...), func(msgs *types.MessageBag, lang string) string {
+		sb := strings.Builder{}
+		if err := WriteXliff(&sb, "en", lang, XliffUnits(msgs, lang, "en")); err != nil {
+			t.Fatal(err)
+		}
+		return sb.String()
+	}, func(im *Importer, lang, exported string) {
+		translations, trgLang, err := ReadXliff(strings.NewReader(exported))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if trgLang != lang {
+			t.Errorf("expected the language %s, got %q", lang, trgLang)
+		}
+		for _, translation := range translations {
+			if !translation.Missing {
+				im.Set(lang, translation.Name, translation.Text)
+			}
+		}
+	})
+}
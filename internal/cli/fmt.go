@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/parse"
+)
+
+// Fmt rewrites the json message files with the indentation used by the imports, keeping the order of their keys.
+// Files in other formats are left as they are
+func Fmt(args MessagesArgs) {
+	log := newLogger(args.LogLevel)
+	_, files := args.load(log)
+
+	for _, file := range files {
+		if strings.ToLower(filepath.Ext(file.FullPath())) != ".json" {
+			log.Debug("Skipping file that is not json", "file", file.FullPath())
+			continue
+		}
+		content, err := file.ReadContents()
+		if err != nil {
+			log.Error("Could not read file", "file", file.FullPath(), "err", err)
+			os.Exit(1)
+		}
+		tree, err := parse.DecodeJson(content)
+		if err != nil {
+			log.Error("Could not decode file", "file", file.FullPath(), "err", err)
+			os.Exit(1)
+		}
+		formatted, err := parse.EncodeJson(tree)
+		if err != nil {
+			log.Error("Could not encode file", "file", file.FullPath(), "err", err)
+			os.Exit(1)
+		}
+		if bytes.Equal(content, formatted) {
+			continue
+		}
+		if err := os.WriteFile(file.FullPath(), formatted, 0644); err != nil {
+			log.Error("Could not write file", "file", file.FullPath(), "err", err)
+			os.Exit(1)
+		}
+		log.Info("Formatted file", "file", file.FullPath())
+	}
+}
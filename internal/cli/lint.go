@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"os"
+	"slices"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/validate"
+)
+
+// Lint validates the messages like the code generation does, also reporting the entries missing in some languages
+// and the languages without the CLDR data the messages need. Exits with an error if anything was reported
+func Lint(args MessagesArgs) {
+	log := newLogger(args.LogLevel)
+	messages, _ := args.load(log)
+	langs := messages.Languages().Get()
+	slices.Sort(langs)
+
+	problems := false
+	missing := messages.MustHaveAllLangs(langs, args.DefaultLanguage)
+	for lang, branches := range messages.CompleteBranches(args.DefaultLanguage) {
+		missing[lang] = append(missing[lang], branches...)
+	}
+	for _, lang := range langs {
+		if entries := missing[lang]; len(entries) > 0 {
+			problems = true
+			log.Warn("Missing entries", "lang", lang, "entries", entries)
+		}
+	}
+	wc := util.NewWarningsCollector()
+	validate.LanguageData(messages, langs, wc)
+	for _, warning := range wc.Warnings() {
+		problems = true
+		log.Warn(warning.Error())
+	}
+
+	if problems {
+		log.Error("The messages have problems")
+		os.Exit(1)
+	}
+	log.Info("The messages have no problems")
+}
@@ -1,13 +1,20 @@
 package cli
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
 	"log/slog"
 	"os"
+	"strings"
 
+	"github.com/MrNemo64/go-n-i18n/generator"
 	"github.com/MrNemo64/go-n-i18n/internal/cli/parse"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/pipeline"
 	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
 	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
-	"github.com/MrNemo64/go-n-i18n/internal/cli/writing"
 )
 
 type CliArgs struct {
@@ -17,67 +24,142 @@ type CliArgs struct {
 	Package                  string
 	TopLevelInterfaceName    string
 	PublicNonNamedInterfaces bool
+	Icu                      bool
+	ArgumentTypes            []string
+	LanguageAliases          map[string]string
+	Check                    bool
 	LogLevel                 slog.Level
 }
 
-func Run(args CliArgs) {
-	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
-		AddSource: false,
-		Level:     args.LogLevel,
-	}))
-	wc := util.NewWarningsCollector()
+// MessagesArgs are the arguments of the commands that only load the messages
+type MessagesArgs struct {
+	MessagesDirectory string
+	DefaultLanguage   string
+	Icu               bool
+	ArgumentTypes     []string
+	LanguageAliases   map[string]string
+	LogLevel          slog.Level
+}
 
-	log.Info("Collecting files")
-	walker, err := parse.IoDirWalker(args.MessagesDirectory, args.DefaultLanguage)
+func (args MessagesArgs) load(log *slog.Logger) (*types.MessageBag, []parse.FileEntry) {
+	return loadMessages(log, args.MessagesDirectory, args.DefaultLanguage, args.ArgumentTypes, args.LanguageAliases, parse.Options{Icu: args.Icu})
+}
+
+func Run(args CliArgs) {
+	log := newLogger(args.LogLevel)
+	result, err := generator.Generate(context.Background(), generator.Options{
+		MessagesDirectory:        args.MessagesDirectory,
+		DefaultLanguage:          args.DefaultLanguage,
+		Package:                  args.Package,
+		TopLevelInterfaceName:    args.TopLevelInterfaceName,
+		PublicNonNamedInterfaces: args.PublicNonNamedInterfaces,
+		Icu:                      args.Icu,
+		ArgumentTypes:            args.ArgumentTypes,
+		LanguageAliases:          args.LanguageAliases,
+		Logger:                   log,
+	})
+	for _, diagnostic := range result.Diagnostics {
+		logDiagnostic(log, diagnostic)
+	}
 	if err != nil {
-		log.Error("Could not collect all files in the messages directory", "err", err)
+		log.Error("Could not generate the code", "err", err)
 		os.Exit(1)
 	}
+	code := result.Code
 
-	argProvider := types.NewArgumentProvider()
+	if args.Check {
+		checkOutFile(log, args.OutFile, code)
+		return
+	}
 
-	log.Info("Parsing files")
-	messages, err := parse.ParseJson(walker, wc, argProvider)
+	file, err := os.Create(args.OutFile)
 	if err != nil {
-		log.Error("Could not parse all files in the messages directory", "err", err)
+		log.Error("Could not open output file", "err", err)
 		os.Exit(1)
 	}
-
-	if !wc.IsEmpty() {
-		for _, warning := range wc.Warnings() {
-			log.Warn(warning.Error())
-		}
+	defer file.Close()
+	if _, err = file.WriteString(code); err != nil {
+		log.Error("Could not write to output file", "err", err)
 		os.Exit(1)
 	}
+}
 
-	allLangs := messages.Languages()
-	if !allLangs.Contains(args.DefaultLanguage) {
-		log.Error("Could not find messages of the default language")
+// checkOutFile prints the diff between the output file and the generated code and exits if they are not equal. If the
+// file does not exist or the diff is too large only a summary is printed
+func checkOutFile(log *slog.Logger, outFile, code string) {
+	current, err := os.ReadFile(outFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		fmt.Printf("%s does not exist, the generated code has %d lines\n", outFile, strings.Count(code, "\n"))
+		log.Error("The output file is not up to date", "out-file", outFile)
 		os.Exit(1)
 	}
-
-	removed := messages.RemoveEntriesWithoutLang(args.DefaultLanguage)
-	if len(removed) > 0 {
-		log.Warn("Removed entries without the default language", "default-language", args.DefaultLanguage,
-			"removed-entries", util.Map(removed, func(_ int, t *types.MessageEntry) string { return (*t).PathAsStr() }))
+	if err != nil {
+		log.Error("Could not read the output file", "err", err)
+		os.Exit(1)
 	}
-
-	filled := messages.MustHaveAllLangs(allLangs.Get(), args.DefaultLanguage)
-	if len(filled) > 0 {
-		log.Warn("Some entries were missing in some languages. Using the message of the default language", "missing-entries", filled)
+	diff, err := util.UnifiedDiff(outFile, outFile+" (generated)", string(current), code)
+	if err != nil {
+		fmt.Printf("%s differs from the generated code in more than %d lines\n", outFile, util.MaxDiffEdits)
+	} else {
+		fmt.Print(diff)
 	}
+	if err != nil || diff != "" {
+		log.Error("The output file is not up to date", "out-file", outFile)
+		os.Exit(1)
+	}
+	log.Info("The output file is up to date", "out-file", outFile)
+}
 
-	log.Info("Generating code")
-	code := writing.GenerateGoCode(messages, writing.GoNamer(args.TopLevelInterfaceName, args.PublicNonNamedInterfaces), allLangs.Get(), args.DefaultLanguage, args.Package)
+func newLogger(level slog.Level) *slog.Logger {
+	return newLoggerTo(os.Stdout, level)
+}
 
-	file, err := os.Create(args.OutFile)
+// newLoggerTo returns a logger that writes to w, the commands that print their result to stdout log to stderr
+func newLoggerTo(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{
+		AddSource: false,
+		Level:     level,
+	}))
+}
+
+// logDiagnostic logs a diagnostic of the generator at the level of its severity
+func logDiagnostic(log *slog.Logger, diagnostic generator.Diagnostic) {
+	var attrs []any
+	if diagnostic.Lang != "" {
+		attrs = append(attrs, "lang", diagnostic.Lang)
+	}
+	if len(diagnostic.Paths) > 0 {
+		attrs = append(attrs, "entries", diagnostic.Paths)
+	}
+	if diagnostic.Severity == generator.SeverityError {
+		log.Error(diagnostic.Message, attrs...)
+	} else {
+		log.Warn(diagnostic.Message, attrs...)
+	}
+}
+
+// loadMessages parses and validates all the message files, removing the entries without the default language.
+// Exits if the messages can not be loaded
+func loadMessages(log *slog.Logger, messagesDirectory, defaultLanguage string, argumentTypes []string, languageAliases map[string]string, options parse.Options) (*types.MessageBag, []parse.FileEntry) {
+	wc := util.NewWarningsCollector()
+	messages, err := pipeline.Load(context.Background(), log, pipeline.Options{
+		MessagesDirectory: messagesDirectory,
+		DefaultLanguage:   defaultLanguage,
+		ArgumentTypes:     argumentTypes,
+		LanguageAliases:   languageAliases,
+		Parse:             options,
+	}, wc)
+	for _, warning := range wc.Warnings() {
+		log.Warn(warning.Error())
+	}
 	if err != nil {
-		log.Error("Could not open output file", "err", err)
+		log.Error("Could not load the messages", "err", err)
 		os.Exit(1)
 	}
-	defer file.Close()
-	if _, err = file.WriteString(code); err != nil {
-		log.Error("Could not write to output file", "err", err)
-		os.Exit(1)
+
+	if len(messages.Removed) > 0 {
+		log.Warn("Removed entries without the default language", "default-language", defaultLanguage,
+			"removed-entries", util.Map(messages.Removed, func(_ int, t *types.MessageEntry) string { return (*t).PathAsStr() }))
 	}
+	return messages.Bag, messages.Files
 }
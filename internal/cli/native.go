@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/exchange"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+type NativeArgs struct {
+	MessagesArgs
+	OutDirectory string
+}
+
+// ExportAndroid writes a values-<lang>/strings.xml file for each language into the out directory, the strings of
+// the default language go to values/strings.xml
+func ExportAndroid(args NativeArgs) {
+	log := newLogger(args.LogLevel)
+	messages, _ := args.load(log)
+
+	wc := util.NewWarningsCollector()
+	for _, lang := range messages.Languages().Get() {
+		log.Info("Exporting language", "lang", lang)
+		strs := exchange.AndroidStrings(messages, lang, args.DefaultLanguage, wc)
+		dir := filepath.Join(args.OutDirectory, exchange.AndroidValuesDirectory(lang, args.DefaultLanguage))
+		writeNativeFile(log, dir, "strings.xml", func(w io.Writer) error { return exchange.WriteAndroidStrings(w, strs) })
+	}
+	for _, warning := range wc.Warnings() {
+		log.Warn(warning.Error())
+	}
+}
+
+// ExportApple writes a <lang>.lproj/Localizable.strings file and a <lang>.lproj/Localizable.stringsdict file with
+// the plural messages for each language into the out directory
+func ExportApple(args NativeArgs) {
+	log := newLogger(args.LogLevel)
+	messages, _ := args.load(log)
+
+	wc := util.NewWarningsCollector()
+	for _, lang := range messages.Languages().Get() {
+		log.Info("Exporting language", "lang", lang)
+		strs := exchange.AppleStrings(messages, lang, args.DefaultLanguage, wc)
+		dir := filepath.Join(args.OutDirectory, lang+".lproj")
+		writeNativeFile(log, dir, "Localizable.strings", func(w io.Writer) error { return exchange.WriteAppleStrings(w, strs) })
+		writeNativeFile(log, dir, "Localizable.stringsdict", func(w io.Writer) error { return exchange.WriteAppleStringsdict(w, strs) })
+	}
+	for _, warning := range wc.Warnings() {
+		log.Warn(warning.Error())
+	}
+}
+
+func writeNativeFile(log *slog.Logger, dir, name string, write func(w io.Writer) error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.Error("Could not create the directory", "dir", dir, "err", err)
+		os.Exit(1)
+	}
+	name = filepath.Join(dir, name)
+	file, err := os.Create(name)
+	if err != nil {
+		log.Error("Could not create file", "file", name, "err", err)
+		os.Exit(1)
+	}
+	defer file.Close()
+	if err := write(file); err != nil {
+		log.Error("Could not write file", "file", name, "err", err)
+		os.Exit(1)
+	}
+}
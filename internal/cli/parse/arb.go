@@ -0,0 +1,123 @@
+package parse
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+	"github.com/iancoleman/orderedmap"
+)
+
+var (
+	ErrArbInvalidMetadata    util.Error = util.MakeError("the metadata `@%s` must be an object")
+	ErrArbInvalidPlaceholder            = util.MakeError("the placeholder %s of the message %s must be an object")
+	ErrArbMessageNotString              = util.MakeError("the message %s must be a string")
+)
+
+// arbTypes maps the types of the placeholders of arb files to the argument types
+var arbTypes = map[string]string{
+	"String": "string",
+	"int":    "int",
+	"double": "float64",
+	"num":    "float64",
+	"Object": "any",
+	// the format of DateTime placeholders is a skeleton, which is kept as the format of the date argument
+	"DateTime": "date",
+}
+
+// arbDateStyles maps the arb date formats that are not skeletons of the date arguments to their styles
+var arbDateStyles = map[string]string{"yMMMMEEEEd": "full"}
+
+// ArbLanguage returns the language of an arb file: its `@@locale` if it has one or else the part of the name
+// after the first _, as in app_en_US.arb. The _ of the language are replaced by -
+func ArbLanguage(name string, content []byte) string {
+	var header struct {
+		Locale string `json:"@@locale"`
+	}
+	language := name
+	if err := json.Unmarshal(content, &header); err == nil && header.Locale != "" {
+		language = header.Locale
+	} else if idx := strings.Index(name, "_"); idx >= 0 {
+		language = name[idx+1:]
+	}
+	return strings.ReplaceAll(language, "_", "-")
+}
+
+// DecodeArb decodes a Flutter arb file. Its messages use the ICU MessageFormat syntax. Messages with a `@key`
+// metadata object are declared with the types of its placeholders as `_args` and its description as `_description`.
+// Keys starting with @@ are ignored
+func DecodeArb(content []byte) (*orderedmap.OrderedMap, error) {
+	entries, err := DecodeJson(content)
+	if err != nil {
+		return nil, err
+	}
+	root := orderedmap.New()
+	for _, key := range entries.Keys() {
+		if strings.HasPrefix(key, "@@") { // attributes of the file like @@locale
+			continue
+		}
+		if strings.HasPrefix(key, "@") {
+			metadata, _ := entries.Get(key)
+			if _, isObject := metadata.(orderedmap.OrderedMap); !isObject {
+				return nil, ErrArbInvalidMetadata.WithArgs(key[1:])
+			}
+			continue
+		}
+		value, _ := entries.Get(key)
+		message, ok := value.(string)
+		if !ok {
+			return nil, ErrArbMessageNotString.WithArgs(key)
+		}
+		metadata, found := entries.Get("@" + key)
+		if !found {
+			root.Set(key, message)
+			continue
+		}
+		declared, err := arbDeclaredMessage(key, message, metadata.(orderedmap.OrderedMap))
+		if err != nil {
+			return nil, err
+		}
+		root.Set(key, *declared)
+	}
+	return root, nil
+}
+
+func arbDeclaredMessage(key, message string, metadata orderedmap.OrderedMap) (*orderedmap.OrderedMap, error) {
+	declared := orderedmap.New()
+	if value, found := metadata.Get("placeholders"); found {
+		placeholders, ok := value.(orderedmap.OrderedMap)
+		if !ok {
+			return nil, ErrArbInvalidMetadata.WithArgs(key)
+		}
+		args := make([]any, 0, len(placeholders.Keys()))
+		for _, name := range placeholders.Keys() {
+			value, _ := placeholders.Get(name)
+			placeholder, ok := value.(orderedmap.OrderedMap)
+			if !ok {
+				return nil, ErrArbInvalidPlaceholder.WithArgs(name, key)
+			}
+			arg := name
+			typeName, _ := placeholder.Get("type")
+			if name, ok := typeName.(string); ok {
+				if mapped, known := arbTypes[name]; known {
+					arg += ":" + mapped
+					if format, ok := placeholder.Values()["format"].(string); ok && name == "DateTime" && format != "" {
+						if style, isStyle := arbDateStyles[format]; isStyle {
+							format = style
+						}
+						arg += ":" + format
+					}
+				}
+			}
+			args = append(args, arg)
+		}
+		declared.Set("_args", args)
+	}
+	declared.Set("_value", message)
+	if description, found := metadata.Get("description"); found {
+		if str, ok := description.(string); ok && str != "" {
+			declared.Set("_description", str)
+		}
+	}
+	return declared, nil
+}
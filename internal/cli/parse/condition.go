@@ -0,0 +1,220 @@
+package parse
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	gotypes "go/types"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+var (
+	ErrConditionArgumentNotTyped = util.MakeError("the type of the argument %s used in the conditions of the path %s can not be inferred, specify it with {%s:type} in the condition or with `_args`")
+)
+
+// ConditionPackages are the packages of the standard library, by name, that conditions can use without importing
+// them, like `strings.HasPrefix(name, "admin")`. The generated code imports the ones used
+var ConditionPackages = map[string]string{
+	"bytes":   "bytes",
+	"math":    "math",
+	"slices":  "slices",
+	"strconv": "strconv",
+	"strings": "strings",
+	"time":    "time",
+	"unicode": "unicode",
+	"utf8":    "unicode/utf8",
+}
+
+// ConditionIdentifiers returns the identifiers used in a condition expression that are not part of the go
+// universe (true, len, int...), the blank identifier, selected fields or methods or the packages of
+// ConditionPackages, in order of appearance and without duplicates
+func ConditionIdentifiers(expr ast.Expr) []string {
+	found := util.NewSet[string]()
+	var visit func(node ast.Node) bool
+	visit = func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.SelectorExpr:
+			if isConditionPackage(n.X) {
+				return false
+			}
+			ast.Inspect(n.X, visit) // the selected field or method is not an identifier of the scope
+			return false
+		case *ast.Ident:
+			if n.Name != "_" && gotypes.Universe.Lookup(n.Name) == nil {
+				found.Add(n.Name)
+			}
+		}
+		return true
+	}
+	ast.Inspect(expr, visit)
+	return found.Get()
+}
+
+// ConditionPackageNames returns the names of the packages of ConditionPackages whose members are selected in a
+// condition expression, in order of appearance and without duplicates. A name may also be an argument of the message,
+// which then hides the package
+func ConditionPackageNames(expr ast.Expr) []string {
+	found := util.NewSet[string]()
+	ast.Inspect(expr, func(node ast.Node) bool {
+		if selector, ok := node.(*ast.SelectorExpr); ok && isConditionPackage(selector.X) {
+			found.Add(selector.X.(*ast.Ident).Name)
+		}
+		return true
+	})
+	return found.Get()
+}
+
+func isConditionPackage(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	_, found := ConditionPackages[ident.Name]
+	return found
+}
+
+// ParseCondition removes the {name:type} argument declarations of a condition (`{count:int} > 5` -> `count > 5`)
+// and adds every argument used by the condition to argList. The types of the arguments are the declared ones or
+// unknown, which may later be inferred by InferConditionArguments
+func (p *JsonParser) ParseCondition(fullKey, condition string, argList *types.ArgumentList) (string, bool) {
+	declared := make(map[string]string)
+	cleaned := ArgumentExtractor.ReplaceAllStringFunc(condition, func(match string) string {
+		groups := ArgumentExtractor.FindStringSubmatch(match)
+		if groups[2] != "" {
+			declared[groups[1]] = groups[2]
+		}
+		return groups[1]
+	})
+
+	expr, err := parser.ParseExpr(cleaned)
+	if err != nil {
+		return cleaned, true // reported with the file and language when validating the conditions
+	}
+	names := ConditionIdentifiers(expr)
+	for _, name := range ConditionPackageNames(expr) {
+		if _, found := declared[name]; found {
+			names = append(names, name) // an argument declared with the name of a package hides it
+		}
+	}
+	ok := true
+	for _, name := range names {
+		argType := p.argProvider.UnknwonType()
+		if typeName, found := declared[name]; found {
+			if argType, found = p.argProvider.FindArgument(typeName); !found {
+				p.WarningsCollector.AddWarning(ErrUnknwonArgumentType.WithArgs(typeName, fullKey))
+				argType = p.argProvider.UnknwonType()
+			}
+		}
+		if _, err := argList.AddArgument(&types.MessageArgument{Name: name, Type: argType}); err != nil {
+			p.WarningsCollector.AddWarning(err)
+			ok = false
+		}
+	}
+	return cleaned, ok
+}
+
+// InferConditionArguments gives a type to the arguments used in conditions whose type was not specified in any
+// language, based on how the conditions use them, and reports the ones whose type can not be inferred
+func (p *JsonParser) InferConditionArguments(root *types.MessageBag) {
+	for _, instance := range root.Instances() {
+		notTyped := util.NewSet[string]()
+		for _, lang := range instance.Languages().Get() {
+			types.VisitValues(instance.MessageMust(lang), func(value types.MessageValue) {
+				conditional, ok := value.(*types.ValueConditional)
+				if !ok {
+					return
+				}
+				for _, condition := range conditional.Conditions {
+					expr, err := parser.ParseExpr(condition.Condition)
+					if err != nil {
+						continue
+					}
+					inferred := inferIdentifierTypes(expr)
+					for _, name := range ConditionIdentifiers(expr) {
+						arg, found := instance.Args().GetArgument(name)
+						if !found || !arg.Type.IsUnknown {
+							continue
+						}
+						if typeName, found := inferred[name]; found {
+							arg.Type = p.argProvider.FindArgumentOrUnknwonType(typeName)
+						} else {
+							notTyped.Add(name)
+						}
+					}
+				}
+			})
+		}
+		for _, name := range notTyped.Get() {
+			if arg, _ := instance.Args().GetArgument(name); arg.Type.IsUnknown {
+				p.AddWarning(ErrConditionArgumentNotTyped.WithArgs(name, instance.PathAsStr(), name))
+			}
+		}
+	}
+}
+
+// inferIdentifierTypes infers the type of the identifiers used as booleans (`a && !b`) or compared or operated
+// with literals (`count > 5`, `name == "x"`, `ratio * 2.5 > 1`)
+func inferIdentifierTypes(expr ast.Expr) map[string]string {
+	inferred := make(map[string]string)
+	setIdent := func(e ast.Expr, typeName string) {
+		if ident, ok := unparen(e).(*ast.Ident); ok && typeName != "" {
+			if _, found := inferred[ident.Name]; !found {
+				inferred[ident.Name] = typeName
+			}
+		}
+	}
+	setIdent(expr, "bool")
+	ast.Inspect(expr, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.UnaryExpr:
+			if n.Op == token.NOT {
+				setIdent(n.X, "bool")
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				setIdent(n.X, "bool")
+				setIdent(n.Y, "bool")
+			} else {
+				setIdent(n.X, literalType(n.Y))
+				setIdent(n.Y, literalType(n.X))
+			}
+		case *ast.SelectorExpr:
+			return false
+		}
+		return true
+	})
+	return inferred
+}
+
+func literalType(e ast.Expr) string {
+	e = unparen(e)
+	if unary, ok := e.(*ast.UnaryExpr); ok && (unary.Op == token.SUB || unary.Op == token.ADD) {
+		e = unparen(unary.X)
+	}
+	lit, ok := e.(*ast.BasicLit)
+	if !ok {
+		return ""
+	}
+	switch lit.Kind {
+	case token.INT:
+		return "int"
+	case token.FLOAT:
+		return "float64"
+	case token.STRING:
+		return "string"
+	default:
+		return ""
+	}
+}
+
+func unparen(e ast.Expr) ast.Expr {
+	for {
+		paren, ok := e.(*ast.ParenExpr)
+		if !ok {
+			return e
+		}
+		e = paren.X
+	}
+}
@@ -0,0 +1,39 @@
+package parse
+
+import (
+	"go/parser"
+	"slices"
+	"testing"
+)
+
+func TestConditionIdentifiers(t *testing.T) {
+	tests := []struct {
+		condition   string
+		identifiers []string
+		packages    []string
+	}{
+		{condition: "count > 1", identifiers: []string{"count"}},
+		{condition: `count > 1 && name == "admin" || count < 0`, identifiers: []string{"count", "name"}},
+		{condition: "len(names) > 0 && true", identifiers: []string{"names"}},
+		{condition: "user.Premium && !user.Banned()", identifiers: []string{"user"}},
+		{condition: "count > _", identifiers: []string{"count"}},
+		{condition: `strings.HasPrefix(name, "x")`, identifiers: []string{"name"}, packages: []string{"strings"}},
+		{condition: "utf8.RuneCountInString(name) > math.MaxInt8", identifiers: []string{"name"}, packages: []string{"utf8", "math"}},
+		{condition: "elapsed > time.Hour && time.Since(start) < elapsed", identifiers: []string{"elapsed", "start"}, packages: []string{"time"}},
+		{condition: "money.IsZero()", identifiers: []string{"money"}},
+	}
+	for _, test := range tests {
+		t.Run(test.condition, func(t *testing.T) {
+			expr, err := parser.ParseExpr(test.condition)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if identifiers := ConditionIdentifiers(expr); !slices.Equal(identifiers, test.identifiers) {
+				t.Errorf("expected the identifiers %v but got %v", test.identifiers, identifiers)
+			}
+			if packages := ConditionPackageNames(expr); !slices.Equal(packages, test.packages) {
+				t.Errorf("expected the packages %v but got %v", test.packages, packages)
+			}
+		})
+	}
+}
@@ -0,0 +1,839 @@
+package parse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+	"github.com/iancoleman/orderedmap"
+)
+
+var (
+	ErrFtlSyntax              util.Error = util.MakeError("invalid fluent file, line %d: %s")
+	ErrFtlPattern                        = util.MakeError("invalid fluent pattern in %s: %s at position %d")
+	ErrFtlUnknownReference               = util.MakeError("the message %s references the unknown %s %s")
+	ErrFtlReferenceCycle                 = util.MakeError("the message %s has a reference cycle: %s")
+	ErrFtlUnsupportedFunction            = util.MakeError("the message %s uses the function %s, only NUMBER is supported")
+	ErrFtlValueAttribute                 = util.MakeError("the message %s has a value and an attribute named `%s`, which is the name given to the value")
+	ErrFtlNoDefaultVariant               = util.MakeError("a select expression of the message %s has no default variant")
+)
+
+// FtlValueKey is the name of the entry with the value of a message that also has attributes
+const FtlValueKey = "value"
+
+var (
+	ftlEntryStart    = regexp.MustCompile(`^(-?[a-zA-Z][\w-]*)\s*=(.*)$`)
+	ftlAttributeLine = regexp.MustCompile(`^\s*\.([a-zA-Z][\w-]*)\s*=(.*)$`)
+	// ftlVariableType matches the `$name (Type)` declarations in the comments of the messages
+	ftlVariableType = regexp.MustCompile(`\$([a-zA-Z][\w-]*)\s*\(\s*(\w+)\s*\)`)
+)
+
+// ftlTypes maps the types used in the comments of fluent messages to the argument types
+var ftlTypes = map[string]string{"String": "string", "Number": "float64"}
+
+// ftlEntry is a message or term of a fluent file
+type ftlEntry struct {
+	Id         string
+	Term       bool
+	Comment    []string
+	Value      []ftlElement // nil if the entry only has attributes
+	Attributes *orderedmap.OrderedMap
+}
+
+type ftlElement interface{}
+
+type ftlText string
+
+type ftlPlaceable struct{ Expr ftlExpression }
+
+type ftlExpression interface{}
+
+type ftlLiteral struct {
+	Value  string
+	Number bool
+}
+
+type ftlVariable string
+
+type ftlReference struct {
+	Id        string
+	Attribute string
+	Term      bool
+	Args      map[string]ftlLiteral
+}
+
+type ftlFunction struct {
+	Name       string
+	Positional []ftlExpression
+	Named      map[string]ftlLiteral
+}
+
+type ftlSelect struct {
+	Selector ftlExpression
+	Variants []ftlVariant
+}
+
+type ftlVariant struct {
+	Key     string
+	Default bool
+	Pattern []ftlElement
+}
+
+// DecodeFluent decodes a fluent (.ftl) file. Each message becomes an ICU message, with terms and message references
+// inlined, variables turned into arguments and select expressions turned into select, plural or selectordinal
+// arguments. Messages with attributes become a group of messages. The types of the variables can be declared in
+// the comment of the message with `$name (Type)`
+func DecodeFluent(content []byte) (*orderedmap.OrderedMap, error) {
+	entries, err := parseFluentResource(strings.ReplaceAll(string(content), "\r\n", "\n"))
+	if err != nil {
+		return nil, err
+	}
+	converter := &ftlConverter{messages: make(map[string]*ftlEntry), terms: make(map[string]*ftlEntry)}
+	for _, entry := range entries {
+		if entry.Term {
+			converter.terms[entry.Id] = entry
+		} else {
+			converter.messages[entry.Id] = entry
+		}
+	}
+
+	root := orderedmap.New()
+	for _, entry := range entries {
+		if entry.Term {
+			continue
+		}
+		converter.declared = ftlDeclaredTypes(entry)
+		if len(entry.Attributes.Keys()) == 0 {
+			message, err := converter.message(entry.Id, entry.Value)
+			if err != nil {
+				return nil, err
+			}
+			root.Set(entry.Id, message)
+			continue
+		}
+		bag := orderedmap.New()
+		if entry.Value != nil {
+			if _, found := entry.Attributes.Get(FtlValueKey); found {
+				return nil, ErrFtlValueAttribute.WithArgs(entry.Id, FtlValueKey)
+			}
+			message, err := converter.message(entry.Id, entry.Value)
+			if err != nil {
+				return nil, err
+			}
+			bag.Set(FtlValueKey, message)
+		}
+		for _, name := range entry.Attributes.Keys() {
+			pattern, _ := entry.Attributes.Get(name)
+			message, err := converter.message(entry.Id+"."+name, pattern.([]ftlElement))
+			if err != nil {
+				return nil, err
+			}
+			bag.Set(name, message)
+		}
+		root.Set(entry.Id, *bag)
+	}
+	return root, nil
+}
+
+func ftlDeclaredTypes(entry *ftlEntry) map[string]string {
+	declared := make(map[string]string)
+	for _, line := range entry.Comment {
+		for _, match := range ftlVariableType.FindAllStringSubmatch(line, -1) {
+			typeName := match[2]
+			if mapped, found := ftlTypes[typeName]; found {
+				typeName = mapped
+			}
+			declared[ftlArgumentName(match[1])] = typeName
+		}
+	}
+	return declared
+}
+
+// ftlArgumentName turns a fluent variable name into an argument name, replacing the - with _
+func ftlArgumentName(variable string) string {
+	return strings.ReplaceAll(variable, "-", "_")
+}
+
+// parseFluentResource splits a fluent file in messages and terms and parses their patterns
+func parseFluentResource(content string) ([]*ftlEntry, error) {
+	lines := strings.Split(content, "\n")
+	var entries []*ftlEntry
+	var comment []string
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			comment = nil
+			i++
+		case strings.HasPrefix(line, "##"): // group and resource comments
+			comment = nil
+			i++
+		case strings.HasPrefix(line, "#"):
+			comment = append(comment, strings.TrimSpace(strings.TrimPrefix(line, "#")))
+			i++
+		case line[0] == ' ' || line[0] == '\t':
+			return nil, ErrFtlSyntax.WithArgs(i+1, "indented line outside of a message")
+		default:
+			match := ftlEntryStart.FindStringSubmatch(line)
+			if match == nil {
+				return nil, ErrFtlSyntax.WithArgs(i+1, "expected a message or a term")
+			}
+			start := i
+			// the entry continues in the indented lines and in any line inside of a placeable
+			end := i + 1
+			depth := ftlDepthAfter(match[2], 0)
+			for j := i + 1; j < len(lines); j++ {
+				if strings.TrimSpace(lines[j]) == "" {
+					continue
+				}
+				if depth == 0 && lines[j][0] != ' ' && lines[j][0] != '\t' {
+					break
+				}
+				depth = ftlDepthAfter(lines[j], depth)
+				end = j + 1
+			}
+			block := append([]string{match[2]}, lines[start+1:end]...)
+			entry, err := parseFluentEntry(match[1], block, comment)
+			if err != nil {
+				return nil, ErrFtlSyntax.WithArgs(start+1, err.Error())
+			}
+			entries = append(entries, entry)
+			comment = nil
+			i = end
+		}
+	}
+	return entries, nil
+}
+
+func parseFluentEntry(id string, block []string, comment []string) (*ftlEntry, error) {
+	entry := &ftlEntry{Id: strings.TrimPrefix(id, "-"), Term: strings.HasPrefix(id, "-"), Comment: comment, Attributes: orderedmap.New()}
+	// the attributes start in lines beginning with a dot outside of any placeable
+	var parts [][]string
+	var names []string
+	current := []string{}
+	depth := 0
+	for i, line := range block {
+		if i > 0 && depth == 0 {
+			if match := ftlAttributeLine.FindStringSubmatch(line); match != nil {
+				parts = append(parts, current)
+				names = append(names, match[1])
+				current = []string{match[2]}
+				continue
+			}
+		}
+		current = append(current, line)
+		depth = ftlDepthAfter(line, depth)
+	}
+	parts = append(parts, current)
+
+	for i, part := range parts {
+		text := strings.Join(part, "\n")
+		if strings.TrimSpace(text) == "" {
+			if i == 0 {
+				continue // message without value
+			}
+			return nil, fmt.Errorf("the attribute %s has no value", names[i-1])
+		}
+		pattern, err := (&ftlParser{src: []rune(text), name: id}).parse()
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			entry.Value = pattern
+		} else {
+			entry.Attributes.Set(names[i-1], pattern)
+		}
+	}
+	if entry.Value == nil && len(entry.Attributes.Keys()) == 0 {
+		return nil, fmt.Errorf("the entry %s has no value nor attributes", id)
+	}
+	if entry.Term && entry.Value == nil {
+		return nil, fmt.Errorf("the term %s has no value", id)
+	}
+	return entry, nil
+}
+
+// ftlDepthAfter returns the depth of placeables after the line, ignoring the braces in string literals
+func ftlDepthAfter(line string, depth int) int {
+	inString := false
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case inString && c == '\\':
+			i++
+		case depth > 0 && c == '"':
+			inString = !inString
+		case !inString && c == '{':
+			depth++
+		case !inString && c == '}' && depth > 0:
+			depth--
+		}
+	}
+	return depth
+}
+
+type ftlParser struct {
+	src  []rune
+	pos  int
+	name string
+}
+
+func (p *ftlParser) error(msg string) error {
+	return ErrFtlPattern.WithArgs(p.name, msg, p.pos)
+}
+
+func (p *ftlParser) peek() (rune, bool) {
+	if p.pos >= len(p.src) {
+		return 0, false
+	}
+	return p.src[p.pos], true
+}
+
+func (p *ftlParser) skipBlank() {
+	for p.pos < len(p.src) && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *ftlParser) parse() ([]ftlElement, error) {
+	pattern, err := p.pattern(false)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.src) {
+		return nil, p.error("unexpected '}'")
+	}
+	return pattern, nil
+}
+
+// pattern parses text and placeables. Patterns of variants end at the next variant or at the end of the select
+func (p *ftlParser) pattern(inVariant bool) ([]ftlElement, error) {
+	var elements []ftlElement
+	text := strings.Builder{}
+	flush := func() {
+		if text.Len() > 0 {
+			elements = append(elements, ftlText(text.String()))
+			text.Reset()
+		}
+	}
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch c {
+		case '{':
+			flush()
+			p.pos++
+			placeable, err := p.placeable()
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, placeable)
+			continue
+		case '}':
+			if inVariant {
+				flush()
+				return ftlDedent(elements), nil
+			}
+			return nil, p.error("unexpected '}'")
+		case '\n':
+			if inVariant {
+				rest := strings.TrimLeftFunc(string(p.src[p.pos:]), unicode.IsSpace)
+				if strings.HasPrefix(rest, "[") || strings.HasPrefix(rest, "*[") || strings.HasPrefix(rest, "}") {
+					flush()
+					return ftlDedent(elements), nil
+				}
+			}
+		}
+		text.WriteRune(c)
+		p.pos++
+	}
+	if inVariant {
+		return nil, p.error("unclosed select expression")
+	}
+	flush()
+	return ftlDedent(elements), nil
+}
+
+// ftlDedent removes the indentation common to all the lines of a pattern and its leading and trailing blanks
+func ftlDedent(elements []ftlElement) []ftlElement {
+	common := -1
+	for i, element := range elements {
+		text, ok := element.(ftlText)
+		if !ok {
+			continue
+		}
+		lines := strings.Split(string(text), "\n")
+		for j, line := range lines[1:] {
+			isLast := j == len(lines)-2
+			if strings.TrimSpace(line) == "" && (!isLast || i == len(elements)-1) {
+				continue // blank line
+			}
+			indent := len(line) - len(strings.TrimLeft(line, " "))
+			if common < 0 || indent < common {
+				common = indent
+			}
+		}
+	}
+	result := make([]ftlElement, 0, len(elements))
+	for _, element := range elements {
+		text, ok := element.(ftlText)
+		if !ok {
+			result = append(result, element)
+			continue
+		}
+		lines := strings.Split(string(text), "\n")
+		for j := 1; j < len(lines); j++ {
+			if len(lines[j]) >= common && common > 0 {
+				lines[j] = lines[j][common:]
+			} else if strings.TrimSpace(lines[j]) == "" {
+				lines[j] = ""
+			}
+		}
+		result = append(result, ftlText(strings.Join(lines, "\n")))
+	}
+	if len(result) > 0 {
+		if text, ok := result[0].(ftlText); ok {
+			result[0] = ftlText(strings.TrimLeftFunc(string(text), unicode.IsSpace))
+		}
+		if text, ok := result[len(result)-1].(ftlText); ok {
+			result[len(result)-1] = ftlText(strings.TrimRightFunc(string(text), unicode.IsSpace))
+		}
+	}
+	return result
+}
+
+func (p *ftlParser) placeable() (ftlElement, error) {
+	p.skipBlank()
+	expr, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	p.skipBlank()
+	if strings.HasPrefix(string(p.src[p.pos:]), "->") {
+		p.pos += 2
+		variants, err := p.variants()
+		if err != nil {
+			return nil, err
+		}
+		expr = ftlSelect{Selector: expr, Variants: variants}
+	}
+	p.skipBlank()
+	if c, found := p.peek(); !found || c != '}' {
+		return nil, p.error("expected '}'")
+	}
+	p.pos++
+	return ftlPlaceable{Expr: expr}, nil
+}
+
+func (p *ftlParser) variants() ([]ftlVariant, error) {
+	var variants []ftlVariant
+	for {
+		p.skipBlank()
+		c, found := p.peek()
+		if !found {
+			return nil, p.error("unclosed select expression")
+		}
+		if c == '}' {
+			return variants, nil
+		}
+		variant := ftlVariant{}
+		if c == '*' {
+			variant.Default = true
+			p.pos++
+		}
+		if c, found := p.peek(); !found || c != '[' {
+			return nil, p.error("expected a variant")
+		}
+		p.pos++
+		end := strings.IndexRune(string(p.src[p.pos:]), ']')
+		if end < 0 {
+			return nil, p.error("unclosed variant key")
+		}
+		variant.Key = strings.TrimSpace(string(p.src[p.pos : p.pos+end]))
+		p.pos += end + 1
+		pattern, err := p.pattern(true)
+		if err != nil {
+			return nil, err
+		}
+		variant.Pattern = pattern
+		variants = append(variants, variant)
+	}
+}
+
+func (p *ftlParser) identifier() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if !(unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '-') {
+			break
+		}
+		p.pos++
+	}
+	return string(p.src[start:p.pos])
+}
+
+func (p *ftlParser) expression() (ftlExpression, error) {
+	c, found := p.peek()
+	if !found {
+		return nil, p.error("expected an expression")
+	}
+	switch {
+	case c == '"':
+		return p.stringLiteral()
+	case c == '-' && p.pos+1 < len(p.src) && unicode.IsDigit(p.src[p.pos+1]), unicode.IsDigit(c):
+		start := p.pos
+		p.pos++
+		for p.pos < len(p.src) && (unicode.IsDigit(p.src[p.pos]) || p.src[p.pos] == '.') {
+			p.pos++
+		}
+		return ftlLiteral{Value: string(p.src[start:p.pos]), Number: true}, nil
+	case c == '$':
+		p.pos++
+		name := p.identifier()
+		if name == "" {
+			return nil, p.error("expected a variable name")
+		}
+		return ftlVariable(name), nil
+	case c == '{':
+		p.pos++
+		inner, err := p.placeable()
+		if err != nil {
+			return nil, err
+		}
+		return inner.(ftlPlaceable).Expr, nil
+	case c == '-' || unicode.IsLetter(c):
+		reference := ftlReference{Term: c == '-'}
+		if reference.Term {
+			p.pos++
+		}
+		reference.Id = p.identifier()
+		if c, found := p.peek(); found && c == '.' {
+			p.pos++
+			reference.Attribute = p.identifier()
+		}
+		if c, found := p.peek(); found && c == '(' {
+			positional, named, err := p.callArguments()
+			if err != nil {
+				return nil, err
+			}
+			if reference.Term {
+				reference.Args = named
+				return reference, nil
+			}
+			return ftlFunction{Name: reference.Id, Positional: positional, Named: named}, nil
+		}
+		return reference, nil
+	default:
+		return nil, p.error(fmt.Sprintf("unexpected '%c'", c))
+	}
+}
+
+func (p *ftlParser) stringLiteral() (ftlExpression, error) {
+	p.pos++ // "
+	sb := strings.Builder{}
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch c {
+		case '"':
+			p.pos++
+			return ftlLiteral{Value: sb.String()}, nil
+		case '\n':
+			return nil, p.error("unclosed string literal")
+		case '\\':
+			if p.pos+1 >= len(p.src) {
+				return nil, p.error("invalid escape sequence")
+			}
+			next := p.src[p.pos+1]
+			switch next {
+			case '"', '\\':
+				sb.WriteRune(next)
+				p.pos += 2
+			case 'u', 'U':
+				size := 4
+				if next == 'U' {
+					size = 6
+				}
+				if p.pos+2+size > len(p.src) {
+					return nil, p.error("invalid unicode escape sequence")
+				}
+				code, err := strconv.ParseUint(string(p.src[p.pos+2:p.pos+2+size]), 16, 32)
+				if err != nil {
+					return nil, p.error("invalid unicode escape sequence")
+				}
+				sb.WriteRune(rune(code))
+				p.pos += 2 + size
+			default:
+				return nil, p.error("invalid escape sequence")
+			}
+		default:
+			sb.WriteRune(c)
+			p.pos++
+		}
+	}
+	return nil, p.error("unclosed string literal")
+}
+
+func (p *ftlParser) callArguments() ([]ftlExpression, map[string]ftlLiteral, error) {
+	p.pos++ // (
+	var positional []ftlExpression
+	named := make(map[string]ftlLiteral)
+	for {
+		p.skipBlank()
+		c, found := p.peek()
+		if !found {
+			return nil, nil, p.error("unclosed call arguments")
+		}
+		if c == ')' {
+			p.pos++
+			return positional, named, nil
+		}
+		start := p.pos
+		expr, err := p.expression()
+		if err != nil {
+			return nil, nil, err
+		}
+		p.skipBlank()
+		if c, found := p.peek(); found && c == ':' {
+			reference, ok := expr.(ftlReference)
+			if !ok || reference.Term || reference.Attribute != "" {
+				p.pos = start
+				return nil, nil, p.error("invalid named argument")
+			}
+			p.pos++
+			p.skipBlank()
+			value, err := p.expression()
+			if err != nil {
+				return nil, nil, err
+			}
+			literal, ok := value.(ftlLiteral)
+			if !ok {
+				return nil, nil, p.error("named arguments must be literals")
+			}
+			named[reference.Id] = literal
+		} else {
+			positional = append(positional, expr)
+		}
+		p.skipBlank()
+		if c, found := p.peek(); found && c == ',' {
+			p.pos++
+		}
+	}
+}
+
+// ftlConverter converts fluent patterns into ICU messages
+type ftlConverter struct {
+	messages  map[string]*ftlEntry
+	terms     map[string]*ftlEntry
+	declared  map[string]string // types of the variables of the message being converted
+	current   string
+	resolving []string
+}
+
+func (c *ftlConverter) message(id string, pattern []ftlElement) (string, error) {
+	c.current = id
+	c.resolving = []string{"message " + id}
+	return c.icu(pattern, false, nil)
+}
+
+// icu converts a pattern. termArgs are the arguments of the term being inlined, nil outside of terms
+func (c *ftlConverter) icu(pattern []ftlElement, inPlural bool, termArgs map[string]ftlLiteral) (string, error) {
+	sb := strings.Builder{}
+	for _, element := range pattern {
+		switch e := element.(type) {
+		case ftlText:
+			sb.WriteString(EscapeIcuText(string(e), inPlural))
+		case ftlPlaceable:
+			converted, err := c.expression(e.Expr, inPlural, termArgs)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(converted)
+		}
+	}
+	return sb.String(), nil
+}
+
+func (c *ftlConverter) expression(expr ftlExpression, inPlural bool, termArgs map[string]ftlLiteral) (string, error) {
+	switch e := expr.(type) {
+	case ftlLiteral:
+		return EscapeIcuText(e.Value, inPlural), nil
+	case ftlVariable:
+		if termArgs != nil {
+			// terms only see the arguments they are given
+			return EscapeIcuText(termArgs[string(e)].Value, inPlural), nil
+		}
+		return c.argument(ftlArgumentName(string(e)), false), nil
+	case ftlFunction:
+		if e.Name != "NUMBER" || len(e.Positional) != 1 {
+			return "", ErrFtlUnsupportedFunction.WithArgs(c.current, e.Name)
+		}
+		if variable, ok := e.Positional[0].(ftlVariable); ok && termArgs == nil {
+			return c.argument(ftlArgumentName(string(variable)), true), nil
+		}
+		return c.expression(e.Positional[0], inPlural, termArgs)
+	case ftlReference:
+		return c.reference(e, inPlural)
+	case ftlSelect:
+		return c.selectExpression(e, inPlural, termArgs)
+	default:
+		panic(fmt.Errorf("unknown fluent expression %+v", expr))
+	}
+}
+
+func (c *ftlConverter) argument(name string, number bool) string {
+	if typeName, found := c.declared[name]; found {
+		return "{" + name + ":" + typeName + "}"
+	}
+	if number {
+		return "{" + name + ", number}"
+	}
+	return "{" + name + "}"
+}
+
+func (c *ftlConverter) reference(reference ftlReference, inPlural bool) (string, error) {
+	kind, entries := "message", c.messages
+	if reference.Term {
+		kind, entries = "term", c.terms
+	}
+	entry, found := entries[reference.Id]
+	var pattern []ftlElement
+	if found {
+		pattern = entry.Value
+		if reference.Attribute != "" {
+			attribute, _ := entry.Attributes.Get(reference.Attribute)
+			pattern, _ = attribute.([]ftlElement)
+		}
+	}
+	name := reference.Id
+	if reference.Attribute != "" {
+		name += "." + reference.Attribute
+	}
+	if pattern == nil {
+		return "", ErrFtlUnknownReference.WithArgs(c.current, kind, name)
+	}
+	key := kind + " " + name
+	for _, resolving := range c.resolving {
+		if resolving == key {
+			return "", ErrFtlReferenceCycle.WithArgs(c.current, strings.Join(append(c.resolving, key), " -> "))
+		}
+	}
+	c.resolving = append(c.resolving, key)
+	defer func() { c.resolving = c.resolving[:len(c.resolving)-1] }()
+
+	var termArgs map[string]ftlLiteral
+	if !reference.Term {
+		// the variables of the referenced message are variables of this one
+		for name, typeName := range ftlDeclaredTypes(entry) {
+			if _, found := c.declared[name]; !found {
+				c.declared[name] = typeName
+			}
+		}
+	} else {
+		termArgs = reference.Args
+		if termArgs == nil {
+			termArgs = make(map[string]ftlLiteral)
+		}
+	}
+	return c.icu(pattern, inPlural, termArgs)
+}
+
+// staticValue returns the value of a selector known when generating, like literals and attributes of terms
+func (c *ftlConverter) staticValue(selector ftlExpression, termArgs map[string]ftlLiteral) (string, bool, error) {
+	switch s := selector.(type) {
+	case ftlLiteral:
+		return s.Value, true, nil
+	case ftlVariable:
+		if termArgs != nil {
+			return termArgs[string(s)].Value, true, nil
+		}
+	case ftlReference:
+		if s.Term {
+			value, err := c.reference(s, false)
+			return value, true, err
+		}
+	}
+	return "", false, nil
+}
+
+func (c *ftlConverter) selectExpression(sel ftlSelect, inPlural bool, termArgs map[string]ftlLiteral) (string, error) {
+	defaultIdx := -1
+	for i, variant := range sel.Variants {
+		if variant.Default {
+			defaultIdx = i
+		}
+	}
+	if defaultIdx < 0 {
+		return "", ErrFtlNoDefaultVariant.WithArgs(c.current)
+	}
+
+	if value, static, err := c.staticValue(sel.Selector, termArgs); err != nil {
+		return "", err
+	} else if static {
+		chosen := sel.Variants[defaultIdx]
+		for _, variant := range sel.Variants {
+			if variant.Key == value {
+				chosen = variant
+				break
+			}
+		}
+		return c.icu(chosen.Pattern, inPlural, termArgs)
+	}
+
+	var name string
+	kind := ""
+	switch s := sel.Selector.(type) {
+	case ftlVariable:
+		name = ftlArgumentName(string(s))
+	case ftlFunction:
+		if s.Name != "NUMBER" || len(s.Positional) != 1 {
+			return "", ErrFtlUnsupportedFunction.WithArgs(c.current, s.Name)
+		}
+		variable, ok := s.Positional[0].(ftlVariable)
+		if !ok {
+			return "", ErrFtlPattern.WithArgs(c.current, "the selector must be a variable, NUMBER() of a variable or a term attribute", 0)
+		}
+		name = ftlArgumentName(string(variable))
+		kind = icuPlural
+		if s.Named["type"].Value == "ordinal" {
+			kind = icuSelectOrdinal
+		}
+	default:
+		return "", ErrFtlPattern.WithArgs(c.current, "the selector must be a variable, NUMBER() of a variable or a term attribute", 0)
+	}
+	if kind == "" {
+		kind = icuPlural
+		for _, variant := range sel.Variants {
+			if _, isCategory := types.ParsePluralCategory(variant.Key); !isCategory && !isNumber(variant.Key) {
+				kind = icuSelect
+			}
+		}
+	}
+
+	nestedInPlural := inPlural || kind != icuSelect
+	sb := strings.Builder{}
+	sb.WriteString("{" + name + ", " + kind + ",")
+	hasOther := false
+	for _, variant := range sel.Variants {
+		converted, err := c.icu(variant.Pattern, nestedInPlural, termArgs)
+		if err != nil {
+			return "", err
+		}
+		key := variant.Key
+		if kind != icuSelect && isNumber(key) {
+			key = "=" + key
+		}
+		hasOther = hasOther || key == "other"
+		sb.WriteString(" " + key + " {" + converted + "}")
+	}
+	if !hasOther { // the default variant is the other option of ICU
+		converted, err := c.icu(sel.Variants[defaultIdx].Pattern, nestedInPlural, termArgs)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(" other {" + converted + "}")
+	}
+	sb.WriteString("}")
+	return sb.String(), nil
+}
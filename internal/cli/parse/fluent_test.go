@@ -0,0 +1,189 @@
+package parse
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecodeFluent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string // the decoded ICU messages as json
+	}{
+		{
+			name:    "messages keep their order",
+			content: "zeta = Z\nalpha = A\n",
+			want:    `{"zeta": "Z", "alpha": "A"}`,
+		},
+		{
+			name:    "comments and blank lines",
+			content: "### Resource comment\n\n## Group comment\n# Message comment\nhello = Hello\n\n\nbye = Bye\n",
+			want:    `{"hello": "Hello", "bye": "Bye"}`,
+		},
+		{
+			name:    "variables",
+			content: "welcome = Welcome { $user-name }, you have { $count } points\n",
+			want:    `{"welcome": "Welcome {user_name}, you have {count} points"}`,
+		},
+		{
+			name:    "declared types",
+			content: "# $name (String) the name of the user\n# $points (Number) and $level (int)\nscore = { $name } has { $points } points at level { $level }\n",
+			want:    `{"score": "{name:string} has {points:float64} points at level {level:int}"}`,
+		},
+		{
+			name:    "NUMBER of a variable",
+			content: "points = { NUMBER($points) } points\n",
+			want:    `{"points": "{points, number} points"}`,
+		},
+		{
+			name:    "multiline pattern",
+			content: "about =\n    First line\n      indented line\n    Last line\n",
+			want:    `{"about": "First line\n  indented line\nLast line"}`,
+		},
+		{
+			name:    "string and number literals",
+			content: "literals = { \"{\" }braces{ \"}\" } { 42 } { \"\\u00E9\" } { \"a\\\"b\" }\n",
+			want:    `{"literals": "'{'braces'}' 42 é a\"b"}`,
+		},
+		{
+			name:    "message references",
+			content: "brand = Firefox\nwelcome = Welcome to { brand }\n",
+			want:    `{"brand": "Firefox", "welcome": "Welcome to Firefox"}`,
+		},
+		{
+			name:    "terms",
+			content: "-brand = Firefox\nabout = About { -brand }\n",
+			want:    `{"about": "About Firefox"}`,
+		},
+		{
+			name:    "parametrized terms",
+			content: "-brand = { $case ->\n    [genitive] Firefoxa\n   *[nominative] Firefox\n}\nabout = O { -brand(case: \"genitive\") }\nname = { -brand }\n",
+			want:    `{"about": "O Firefoxa", "name": "Firefox"}`,
+		},
+		{
+			name:    "term attributes as selectors",
+			content: "-brand = Firefox\n    .gender = masculine\nupdated = { -brand.gender ->\n    [masculine] { -brand } was updated\n   *[other] { -brand } has been updated\n}\n",
+			want:    `{"updated": "Firefox was updated"}`,
+		},
+		{
+			name:    "attributes",
+			content: "login = Log in\n    .title = Log in to your account\n    .placeholder = { $user }\nbutton =\n    .label = Click\n",
+			want:    `{"login": {"value": "Log in", "title": "Log in to your account", "placeholder": "{user}"}, "button": {"label": "Click"}}`,
+		},
+		{
+			name:    "plural select",
+			content: "emails = { $count ->\n    [0] No emails\n    [one] One email\n   *[other] { $count } emails\n}\n",
+			want:    `{"emails": "{count, plural, =0 {No emails} one {One email} other {{count} emails}}"}`,
+		},
+		{
+			name:    "select",
+			content: "liked = { $gender ->\n    [male] He liked it\n    [female] She liked it\n   *[unknown] They liked it\n}\n",
+			want:    `{"liked": "{gender, select, male {He liked it} female {She liked it} unknown {They liked it} other {They liked it}}"}`,
+		},
+		{
+			name:    "ordinal select",
+			content: "place = { NUMBER($pos, type: \"ordinal\") ->\n    [one] {$pos}st\n    [two] {$pos}nd\n   *[other] {$pos}th\n}\n",
+			want:    `{"place": "{pos, selectordinal, one {{pos}st} two {{pos}nd} other {{pos}th}}"}`,
+		},
+		{
+			name:    "text around select",
+			content: "cart = You have { $count ->\n    [one] one item\n   *[other] { $count } items\n} in the cart\n",
+			want:    `{"cart": "You have {count, plural, one {one item} other {{count} items}} in the cart"}`,
+		},
+		{
+			name:    "ICU special characters",
+			content: "quote = It's {\"#\"} { $n ->\n   *[other] # and '\n}\n",
+			want:    `{"quote": "It''s # {n, plural, other {'#' and ''}}"}`,
+		},
+		{
+			name:    "windows line endings",
+			content: "hello = Hello\r\nbye = Bye\r\n",
+			want:    `{"hello": "Hello", "bye": "Bye"}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entries, err := DecodeFluent([]byte(test.content))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			checkDecoded(t, entries, test.want)
+		})
+	}
+}
+
+func TestDecodeFluentErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    error
+	}{
+		{name: "indented line outside of a message", content: "  hello = Hello\n", want: ErrFtlSyntax},
+		{name: "not a message", content: "hello Hello\n", want: ErrFtlSyntax},
+		{name: "message without value", content: "hello =\n", want: ErrFtlSyntax},
+		{name: "term without value", content: "-brand =\n    .gender = masculine\n", want: ErrFtlSyntax},
+		{name: "attribute without value", content: "hello = Hello\n    .title =\n", want: ErrFtlSyntax},
+		{name: "unexpected }", content: "hello = Hello }\n", want: ErrFtlSyntax},
+		{name: "unclosed placeable", content: "hello = Hello { $name\n", want: ErrFtlSyntax},
+		{name: "unclosed select", content: "hello = { $n ->\n   *[other] items\n", want: ErrFtlSyntax},
+		{name: "variant without key", content: "hello = { $n ->\n   *other items\n}\n", want: ErrFtlSyntax},
+		{name: "unclosed variant key", content: "hello = { $n ->\n   *[other items\n}\n", want: ErrFtlSyntax},
+		{name: "unclosed string literal", content: "hello = { \"Hello }\n", want: ErrFtlSyntax},
+		{name: "invalid escape sequence", content: "hello = { \"\\n\" }\n", want: ErrFtlSyntax},
+		{name: "invalid unicode escape sequence", content: "hello = { \"\\u00ZZ\" }\n", want: ErrFtlSyntax},
+		{name: "variable without name", content: "hello = { $ }\n", want: ErrFtlSyntax},
+		{name: "named argument that is not a literal", content: "hello = { NUMBER($n, type: $kind) }\n", want: ErrFtlSyntax},
+		{name: "unclosed call arguments", content: "hello = { NUMBER($n\n", want: ErrFtlSyntax},
+		{name: "unknown message", content: "hello = { brand }\n", want: ErrFtlUnknownReference},
+		{name: "unknown term", content: "hello = { -brand }\n", want: ErrFtlUnknownReference},
+		{name: "unknown attribute", content: "brand = Firefox\nhello = { brand.title }\n", want: ErrFtlUnknownReference},
+		{name: "reference cycle", content: "a = { b }\nb = { a }\n", want: ErrFtlReferenceCycle},
+		{name: "unsupported function", content: "hello = { DATETIME($date) }\n", want: ErrFtlUnsupportedFunction},
+		{name: "unsupported selector function", content: "hello = { PLATFORM() ->\n   *[other] items\n}\n", want: ErrFtlUnsupportedFunction},
+		{name: "NUMBER selector of a literal", content: "hello = { NUMBER(1) ->\n   *[other] items\n}\n", want: ErrFtlPattern},
+		{name: "value and value attribute", content: "hello = Hello\n    .value = Hi\n", want: ErrFtlValueAttribute},
+		{name: "no default variant", content: "hello = { $n ->\n    [one] item\n    [other] items\n}\n", want: ErrFtlNoDefaultVariant},
+		{name: "invalid selector", content: "bye = { brand ->\n   *[other] items\n}\nbrand = Firefox\n", want: ErrFtlPattern},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := DecodeFluent([]byte(test.content))
+			if !errors.Is(err, test.want) {
+				t.Errorf("expected %v, got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestParseFluent(t *testing.T) {
+	messages, warnings := parseFile(t, "en.ftl", `
+# $count (int)
+emails = { $count ->
+    [0] No emails
+    [one] One email
+   *[other] { $count } emails
+}
+liked = { $gender ->
+    [male] He liked it
+   *[other] They liked it
+}
+login = Log in
+    .title = Log in as { $user }
+`, Options{})
+	checkWarnings(t, warnings)
+	want := map[string]string{
+		"emails":      "#count(=0: No emails; one: One email; other: {count:integer} emails)",
+		"liked":       `?(gender == "male": He liked it; else: They liked it)`,
+		"login.value": "Log in",
+		"login.title": "Log in as {user:any}",
+	}
+	for path, message := range want {
+		if messages[path] != message {
+			t.Errorf("%s: expected %q, got %q", path, message, messages[path])
+		}
+	}
+	if len(messages) != len(want) {
+		t.Errorf("expected %d messages, got %v", len(want), messages)
+	}
+}
@@ -0,0 +1,153 @@
+package parse
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+	"github.com/iancoleman/orderedmap"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrUnsupportedFormat  util.Error = util.MakeError("the file %s has an unsupported extension")
+	ErrYamlRootNotMapping            = util.MakeError("the root of the yaml document must be a mapping")
+	ErrYamlAlias                     = util.MakeError("yaml aliases are not supported (line %d)")
+)
+
+// Decoder turns the contents of a file into the ordered tree of messages of the json format,
+// where objects are orderedmap.OrderedMap values, arrays are []any and messages are strings
+type Decoder func(content []byte) (*orderedmap.OrderedMap, error)
+
+var decoders = map[string]Decoder{
+	".json": DecodeJson,
+	".yaml": DecodeYaml,
+	".yml":  DecodeYaml,
+	".toml": DecodeToml,
+	".ftl":  DecodeFluent,
+	".arb":  DecodeArb,
+}
+
+// icuFormats are the formats whose decoders produce ICU messages regardless of the -icu flag
+var icuFormats = map[string]bool{
+	".ftl": true,
+	".arb": true,
+}
+
+func IsSupportedFile(name string) bool {
+	_, found := decoders[strings.ToLower(filepath.Ext(name))]
+	return found
+}
+
+func DecoderFor(name string) (Decoder, error) {
+	decoder, found := decoders[strings.ToLower(filepath.Ext(name))]
+	if !found {
+		return nil, ErrUnsupportedFormat.WithArgs(name)
+	}
+	return decoder, nil
+}
+
+func UsesIcuGrammar(name string) bool {
+	return icuFormats[strings.ToLower(filepath.Ext(name))]
+}
+
+func DecodeJson(content []byte) (*orderedmap.OrderedMap, error) {
+	entries := orderedmap.New()
+	if err := json.Unmarshal(content, entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func DecodeYaml(content []byte) (*orderedmap.OrderedMap, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+	if len(document.Content) == 0 { // empty file
+		return orderedmap.New(), nil
+	}
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, ErrYamlRootNotMapping
+	}
+	decoded, err := yamlNodeToValue(root)
+	if err != nil {
+		return nil, err
+	}
+	entries := decoded.(orderedmap.OrderedMap)
+	return &entries, nil
+}
+
+// yamlNodeToValue converts a node to the values produced by the json decoder. All scalars are messages,
+// so they are kept as strings even if yaml would consider them numbers or booleans
+func yamlNodeToValue(node *yaml.Node) (any, error) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		entries := orderedmap.New()
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			value, err := yamlNodeToValue(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			entries.Set(node.Content[i].Value, value)
+		}
+		return *entries, nil
+	case yaml.SequenceNode:
+		values := make([]any, 0, len(node.Content))
+		for _, child := range node.Content {
+			value, err := yamlNodeToValue(child)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	case yaml.ScalarNode:
+		if node.Tag == "!!null" {
+			return nil, nil
+		}
+		return node.Value, nil
+	case yaml.AliasNode:
+		return nil, ErrYamlAlias.WithArgs(node.Line)
+	default:
+		panic(fmt.Errorf("unknown yaml node kind %d", node.Kind))
+	}
+}
+
+// EncodeJson encodes the messages with an indentation of two spaces and without escaping html characters
+// like the < and > of the conditions
+func EncodeJson(entries *orderedmap.OrderedMap) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(withoutHtmlEscaping(*entries)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func withoutHtmlEscaping(value any) any {
+	switch v := value.(type) {
+	case orderedmap.OrderedMap:
+		copied := orderedmap.New()
+		copied.SetEscapeHTML(false)
+		for _, key := range v.Keys() {
+			child, _ := v.Get(key)
+			copied.Set(key, withoutHtmlEscaping(child))
+		}
+		return *copied
+	case []any:
+		copied := make([]any, len(v))
+		for i := range v {
+			copied[i] = withoutHtmlEscaping(v[i])
+		}
+		return copied
+	default:
+		return v
+	}
+}
@@ -0,0 +1,111 @@
+package parse
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/iancoleman/orderedmap"
+)
+
+// errAny is expected by the tests where any error is valid
+var errAny = errors.New("any error")
+
+func TestDecodeYaml(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		keys    []string
+		want    error // nil if the file is valid, errAny for errors of the yaml library
+	}{
+		{name: "keeps the order of the keys", content: "zeta: Z\nalpha: A\nmiddle: M\n", keys: []string{"zeta", "alpha", "middle"}},
+		{name: "empty file", content: "", keys: []string{}},
+		{name: "root is not a mapping", content: "- one\n- two\n", want: ErrYamlRootNotMapping},
+		{name: "aliases", content: "base: &base Hello\ncopy: *base\n", want: ErrYamlAlias},
+		{name: "invalid yaml", content: "key: [unclosed\n", want: errAny},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entries, err := DecodeYaml([]byte(test.content))
+			if test.want == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !slices.Equal(entries.Keys(), test.keys) {
+					t.Errorf("expected the keys %v, got %v", test.keys, entries.Keys())
+				}
+				return
+			}
+			if err == nil || (test.want != errAny && !errors.Is(err, test.want)) {
+				t.Errorf("expected %v, got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestDecodeYamlScalars(t *testing.T) {
+	entries, err := DecodeYaml([]byte("number: 12\nbool: true\nnull: ~\nlines: [a, b]\nnested:\n  key: value\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]any{"number": "12", "bool": "true", "null": nil} {
+		if value, _ := entries.Get(key); value != want {
+			t.Errorf("%s: expected %#v, got %#v", key, want, value)
+		}
+	}
+	if lines, _ := entries.Get("lines"); !slices.Equal(lines.([]any), []any{"a", "b"}) {
+		t.Errorf("lines: expected [a b], got %v", lines)
+	}
+	nested, _ := entries.Get("nested")
+	nestedEntries := nested.(orderedmap.OrderedMap)
+	if value, _ := nestedEntries.Get("key"); value != "value" {
+		t.Errorf("nested.key: expected value, got %v", value)
+	}
+}
+
+func TestParseYaml(t *testing.T) {
+	messages, warnings := parseFile(t, "en.yaml", `
+literal: Hello
+block: |-
+  Hello {name:str}
+  Bye
+folded: >-
+  Folded
+  line
+quoted: "100% {amount:int}"
+multiline:
+  - First
+  - Second {name:str}
+"?conditional":
+  "{count:int} > 1": Many
+  "": Few
+"#plural":
+  _arg: n
+  "=0": None
+  one: One
+  other: "{n} items"
+untranslated: null
+app:Application:
+  name: App
+`, Options{})
+	checkWarnings(t, warnings)
+	want := map[string]string{
+		"literal":      "Hello",
+		"block":        "Hello {name:string}\nBye",
+		"folded":       "Folded line",
+		"quoted":       "100% {amount:integer}",
+		"multiline":    "[First|Second {name:string}]",
+		"conditional":  "?(count > 1: Many; else: Few)",
+		"plural":       "#n(=0: None; one: One; other: {n:integer} items)",
+		"untranslated": "null",
+		"app.name":     "App",
+	}
+	for path, message := range want {
+		if messages[path] != message {
+			t.Errorf("%s: expected %q, got %q", path, message, messages[path])
+		}
+	}
+	if len(messages) != len(want) {
+		t.Errorf("expected %d messages, got %v", len(want), messages)
+	}
+}
@@ -0,0 +1,588 @@
+package parse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+var (
+	ErrInvalidIcuMessage   util.Error = util.MakeError("invalid ICU message in the path %s: %w")
+	ErrIcuSyntax                      = util.MakeError("%s at position %d")
+	ErrIcuMissingOther                = util.MakeError("the %s of the argument %s has no 'other' option")
+	ErrIcuInvalidPluralKey            = util.MakeError("invalid %s option '%s' of the argument %s, expected zero, one, two, few, many, other or =<number>")
+	ErrIcuDuplicatedOption            = util.MakeError("the option '%s' of the argument %s is defined more than once")
+	ErrIcuUnsupportedStyle            = util.MakeError("unsupported style '%s' of the %s argument %s in the path %s, the default format is used")
+)
+
+const (
+	icuSelect        = "select"
+	icuPlural        = "plural"
+	icuSelectOrdinal = "selectordinal"
+	// icuCurrencySkeleton is the prefix of the style of number arguments like {price, number, ::currency/EUR}
+	icuCurrencySkeleton = "::currency/"
+	// icuSkeleton is the prefix of the style of date and time arguments given as a skeleton like {day, date, ::yMMMd}
+	icuSkeleton = "::"
+)
+
+// icuNode is a part of an ICU message: text, a simple argument, a message reference or a select/plural/selectordinal argument
+type icuNode interface{}
+
+type icuText string
+
+type icuArgument struct {
+	Name string
+	// Type and Style of ICU arguments like {price, number, integer}
+	Type  string
+	Style string
+	// Native is true for arguments written as {name:type:format}, in which case Type and Style are the type and format
+	Native bool
+}
+
+// icuReference is a {@path} reference to another message
+type icuReference struct {
+	Path string
+}
+
+type icuComplexArgument struct {
+	Name    string
+	Kind    string
+	Options []icuOption
+}
+
+type icuOption struct {
+	Key     string
+	Pattern []icuNode
+}
+
+// icuParser parses ICU MessageFormat patterns. The `#` of plural options is turned into an argument with the
+// name of the innermost plural argument
+type icuParser struct {
+	src     []rune
+	pos     int
+	plurals []string
+}
+
+// ParseIcuPattern parses an ICU message. Besides the ICU syntax, arguments can be written as {name:type:format}
+func ParseIcuPattern(message string) ([]icuNode, error) {
+	p := &icuParser{src: []rune(message)}
+	nodes, err := p.pattern(false)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.src) {
+		return nil, p.error("unexpected '}'")
+	}
+	return nodes, nil
+}
+
+func (p *icuParser) error(msg string) error {
+	return ErrIcuSyntax.WithArgs(msg, p.pos)
+}
+
+func (p *icuParser) peek(offset int) (rune, bool) {
+	if p.pos+offset >= len(p.src) {
+		return 0, false
+	}
+	return p.src[p.pos+offset], true
+}
+
+func (p *icuParser) skipSpaces() {
+	for p.pos < len(p.src) && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *icuParser) pattern(inOption bool) ([]icuNode, error) {
+	var nodes []icuNode
+	text := strings.Builder{}
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, icuText(text.String()))
+			text.Reset()
+		}
+	}
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '}':
+			flush()
+			if !inOption {
+				return nodes, p.error("unexpected '}'")
+			}
+			return nodes, nil
+		case c == '{':
+			flush()
+			node, err := p.argument()
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, node)
+		case c == '\'':
+			text.WriteString(p.quoted())
+		case c == '#' && len(p.plurals) > 0:
+			flush()
+			nodes = append(nodes, icuArgument{Name: p.plurals[len(p.plurals)-1]})
+			p.pos++
+		default:
+			text.WriteRune(c)
+			p.pos++
+		}
+	}
+	flush()
+	if inOption {
+		return nil, p.error("unclosed option, expected '}'")
+	}
+	return nodes, nil
+}
+
+// quoted reads the text started by an apostrophe: two apostrophes are an apostrophe, an apostrophe followed by a
+// special character starts a quoted literal until the next single apostrophe and any other apostrophe is literal
+func (p *icuParser) quoted() string {
+	next, found := p.peek(1)
+	if found && next == '\'' {
+		p.pos += 2
+		return "'"
+	}
+	if !found || (next != '{' && next != '}' && next != '|' && !(next == '#' && len(p.plurals) > 0)) {
+		p.pos++
+		return "'"
+	}
+	p.pos++
+	sb := strings.Builder{}
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == '\'' {
+			if next, found := p.peek(1); found && next == '\'' {
+				sb.WriteRune('\'')
+				p.pos += 2
+				continue
+			}
+			p.pos++
+			return sb.String()
+		}
+		sb.WriteRune(c)
+		p.pos++
+	}
+	return sb.String()
+}
+
+func (p *icuParser) word() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if unicode.IsSpace(c) || c == ',' || c == '{' || c == '}' || c == ':' {
+			break
+		}
+		p.pos++
+	}
+	return string(p.src[start:p.pos])
+}
+
+func (p *icuParser) expect(c rune) error {
+	p.skipSpaces()
+	if next, found := p.peek(0); !found || next != c {
+		return p.error(fmt.Sprintf("expected '%c'", c))
+	}
+	p.pos++
+	return nil
+}
+
+func (p *icuParser) argument() (icuNode, error) {
+	p.pos++ // {
+	p.skipSpaces()
+	if next, _ := p.peek(0); next == '@' {
+		p.pos++
+		path := p.word()
+		if !ReferencePath.MatchString(path) {
+			return nil, p.error(fmt.Sprintf("invalid message reference '@%s'", path))
+		}
+		if err := p.expect('}'); err != nil {
+			return nil, err
+		}
+		return icuReference{Path: path}, nil
+	}
+	name := p.word()
+	if !ArgumentName.MatchString(name) {
+		return nil, p.error(fmt.Sprintf("invalid argument name '%s'", name))
+	}
+	p.skipSpaces()
+	next, found := p.peek(0)
+	switch {
+	case !found:
+		return nil, p.error("unclosed argument")
+	case next == '}':
+		p.pos++
+		return icuArgument{Name: name}, nil
+	case next == ':':
+		start := p.pos
+		for p.pos < len(p.src) && p.src[p.pos] != '}' {
+			p.pos++
+		}
+		if p.pos == len(p.src) {
+			return nil, p.error("unclosed argument")
+		}
+		native := ArgumentExtractor.FindStringSubmatch("{" + name + string(p.src[start:p.pos]) + "}")
+		if native == nil {
+			return nil, p.error(fmt.Sprintf("invalid argument '{%s%s}'", name, string(p.src[start:p.pos])))
+		}
+		p.pos++
+		return icuArgument{Name: name, Type: native[2], Style: native[3], Native: true}, nil
+	case next != ',':
+		return nil, p.error("expected ',' or '}'")
+	}
+	p.pos++
+	p.skipSpaces()
+	argType := p.word()
+	p.skipSpaces()
+	next, found = p.peek(0)
+	if !found {
+		return nil, p.error("unclosed argument")
+	}
+	if next == '}' {
+		p.pos++
+		return icuArgument{Name: name, Type: argType}, nil
+	}
+	if next != ',' {
+		return nil, p.error("expected ',' or '}'")
+	}
+	p.pos++
+	switch argType {
+	case icuSelect, icuPlural, icuSelectOrdinal:
+		return p.options(name, argType)
+	}
+	start := p.pos
+	for p.pos < len(p.src) && p.src[p.pos] != '}' {
+		p.pos++
+	}
+	if p.pos == len(p.src) {
+		return nil, p.error("unclosed argument")
+	}
+	style := strings.TrimSpace(string(p.src[start:p.pos]))
+	p.pos++
+	return icuArgument{Name: name, Type: argType, Style: style}, nil
+}
+
+func (p *icuParser) options(name, kind string) (icuNode, error) {
+	arg := icuComplexArgument{Name: name, Kind: kind}
+	p.skipSpaces()
+	if kind != icuSelect && strings.HasPrefix(string(p.src[p.pos:]), "offset:") {
+		return nil, p.error("plural offsets are not supported")
+	}
+	if kind != icuSelect {
+		p.plurals = append(p.plurals, name)
+		defer func() { p.plurals = p.plurals[:len(p.plurals)-1] }()
+	}
+	for {
+		p.skipSpaces()
+		next, found := p.peek(0)
+		if !found {
+			return nil, p.error("unclosed argument")
+		}
+		if next == '}' {
+			p.pos++
+			return arg, nil
+		}
+		key := p.word()
+		if key == "" {
+			return nil, p.error("expected an option")
+		}
+		if err := p.expect('{'); err != nil {
+			return nil, err
+		}
+		pattern, err := p.pattern(true)
+		if err != nil {
+			return nil, err
+		}
+		p.pos++ // }
+		arg.Options = append(arg.Options, icuOption{Key: key, Pattern: pattern})
+	}
+}
+
+// isComplexIcuMessage reports if the nodes have select, plural or selectordinal arguments
+func isComplexIcuMessage(nodes []icuNode) bool {
+	for _, node := range nodes {
+		if _, ok := node.(icuComplexArgument); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseIcuMessage parses a message written with the ICU MessageFormat grammar. Select, plural and selectordinal
+// arguments are lifted to the top of the message, turning into conditional and plural values whose options have
+// the text around the argument
+func (p *JsonParser) ParseIcuMessage(fullKey, message string, argList *types.ArgumentList) (types.MessageValue, bool) {
+	nodes, err := ParseIcuPattern(message)
+	if err != nil {
+		p.AddWarning(ErrInvalidIcuMessage.WithArgs(fullKey, err))
+		return nil, false
+	}
+	return p.liftIcuMessage(fullKey, nodes, argList)
+}
+
+// ParseIcuLines parses the lines of a multiline message written with the ICU MessageFormat grammar. If any
+// line has select, plural or selectordinal arguments the lines are joined and parsed as a single message
+func (p *JsonParser) ParseIcuLines(fullKey string, lines []string, argList *types.ArgumentList) (types.MessageValue, bool) {
+	parsed := make([][]icuNode, len(lines))
+	for i, line := range lines {
+		nodes, err := ParseIcuPattern(line)
+		if err != nil {
+			p.AddWarning(ErrInvalidIcuMessage.WithArgs(fullKey, err))
+			return nil, false
+		}
+		if isComplexIcuMessage(nodes) {
+			return p.ParseIcuMessage(fullKey, strings.Join(lines, "\n"), argList)
+		}
+		parsed[i] = nodes
+	}
+	multilineable := make([]types.Multilineable, len(parsed))
+	for i, nodes := range parsed {
+		value, ok := p.liftIcuMessage(fullKey, nodes, argList)
+		if !ok {
+			return nil, false
+		}
+		multilineable[i] = value.(types.Multilineable)
+	}
+	multi, err := types.NewMultilineValue(multilineable)
+	if err != nil {
+		p.AddWarning(ErrInvalidIcuMessage.WithArgs(fullKey, err))
+		return nil, false
+	}
+	return multi, true
+}
+
+func (p *JsonParser) liftIcuMessage(fullKey string, nodes []icuNode, argList *types.ArgumentList) (types.MessageValue, bool) {
+	p.reportIcuArgumentTypes(fullKey, nodes)
+	// the arguments are added in order of appearance before lifting, which duplicates the text around the complex arguments
+	if !p.addIcuArguments(fullKey, nodes, argList) {
+		return nil, false
+	}
+	return p.liftIcuNodes(fullKey, nodes, argList)
+}
+
+func (p *JsonParser) addIcuArguments(fullKey string, nodes []icuNode, argList *types.ArgumentList) bool {
+	ok := true
+	for _, node := range nodes {
+		var arg *types.MessageArgument
+		switch n := node.(type) {
+		case icuArgument:
+			argType, _ := p.icuArgumentType(n)
+			arg = &types.MessageArgument{Name: n.Name, Type: argType}
+		case icuComplexArgument:
+			argType := p.argProvider.UnknwonType()
+			if n.Kind == icuSelect {
+				argType = p.argProvider.FindArgumentOrUnknwonType("string")
+			}
+			arg = &types.MessageArgument{Name: n.Name, Type: argType}
+		default:
+			continue
+		}
+		if _, err := argList.AddArgument(arg); err != nil {
+			p.AddWarning(err)
+			ok = false
+		}
+		if complex, isComplex := node.(icuComplexArgument); isComplex {
+			for _, option := range complex.Options {
+				ok = p.addIcuArguments(fullKey, option.Pattern, argList) && ok
+			}
+		}
+	}
+	return ok
+}
+
+// icuArgumentType returns the type and format of a simple argument
+func (p *JsonParser) icuArgumentType(arg icuArgument) (*types.ArgumentType, string) {
+	if arg.Native {
+		argType, found := p.argProvider.FindArgument(arg.Type)
+		if !found {
+			argType = p.argProvider.UnknwonType()
+		}
+		return argType, arg.Style
+	}
+	switch arg.Type {
+	case "":
+		return p.argProvider.UnknwonType(), ""
+	case "number":
+		switch {
+		case arg.Style == "integer":
+			return p.argProvider.FindArgumentOrUnknwonType("int"), ""
+		case arg.Style == "percent":
+			return p.argProvider.FindArgumentOrUnknwonType("percent"), ""
+		case strings.HasPrefix(arg.Style, icuCurrencySkeleton):
+			return p.argProvider.FindArgumentOrUnknwonType("currency"), strings.TrimPrefix(arg.Style, icuCurrencySkeleton)
+		default:
+			return p.argProvider.FindArgumentOrUnknwonType("number"), ""
+		}
+	case "date", "time":
+		return p.argProvider.FindArgumentOrUnknwonType(arg.Type), strings.TrimPrefix(arg.Style, icuSkeleton)
+	}
+	argType, found := p.argProvider.FindArgument(arg.Type)
+	if !found {
+		return p.argProvider.UnknwonType(), ""
+	}
+	return argType, arg.Style
+}
+
+// reportIcuArgumentTypes reports the unknown types and unsupported styles of the simple arguments, once per message
+func (p *JsonParser) reportIcuArgumentTypes(fullKey string, nodes []icuNode) {
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case icuArgument:
+			if n.Type == "" {
+				continue
+			}
+			if n.Type == "number" {
+				if n.Style != "" && n.Style != "integer" && n.Style != "percent" && !strings.HasPrefix(n.Style, icuCurrencySkeleton) {
+					p.AddWarning(ErrIcuUnsupportedStyle.WithArgs(n.Style, n.Type, n.Name, fullKey))
+				}
+			} else if _, found := p.argProvider.FindArgument(n.Type); !found {
+				p.AddWarning(ErrUnknwonArgumentType.WithArgs(n.Type, fullKey))
+			}
+		case icuComplexArgument:
+			for _, option := range n.Options {
+				p.reportIcuArgumentTypes(fullKey, option.Pattern)
+			}
+		}
+	}
+}
+
+func (p *JsonParser) liftIcuNodes(fullKey string, nodes []icuNode, argList *types.ArgumentList) (types.MessageValue, bool) {
+	idx := -1
+	for i, node := range nodes {
+		if _, ok := node.(icuComplexArgument); ok {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return p.icuLeaf(fullKey, nodes, argList)
+	}
+	complex := nodes[idx].(icuComplexArgument)
+	branches := make([]types.MessageValue, len(complex.Options))
+	seen := util.NewSet[string]()
+	for i, option := range complex.Options {
+		if seen.Contains(option.Key) {
+			p.AddWarning(ErrInvalidIcuMessage.WithArgs(fullKey, ErrIcuDuplicatedOption.WithArgs(option.Key, complex.Name)))
+			return nil, false
+		}
+		seen.Add(option.Key)
+		branch := make([]icuNode, 0, len(nodes)+len(option.Pattern))
+		branch = append(branch, nodes[:idx]...)
+		branch = append(branch, option.Pattern...)
+		branch = append(branch, nodes[idx+1:]...)
+		value, ok := p.liftIcuNodes(fullKey, branch, argList)
+		if !ok {
+			return nil, false
+		}
+		branches[i] = value
+	}
+	if !seen.Contains("other") {
+		p.AddWarning(ErrInvalidIcuMessage.WithArgs(fullKey, ErrIcuMissingOther.WithArgs(complex.Kind, complex.Name)))
+		return nil, false
+	}
+	arg, found := argList.GetArgument(complex.Name)
+	if !found {
+		panic(fmt.Errorf("the argument %s of the path %s was not added before lifting the ICU message", complex.Name, fullKey))
+	}
+
+	if complex.Kind == icuSelect {
+		var conditions []types.Condition
+		var elseValue types.Conditionable
+		for i, option := range complex.Options {
+			if option.Key == "other" {
+				elseValue = branches[i].(types.Conditionable)
+				continue
+			}
+			conditions = append(conditions, types.Condition{
+				Condition: complex.Name + " == " + strconv.Quote(option.Key),
+				Value:     branches[i].(types.Conditionable),
+			})
+		}
+		conditional, err := types.NewConditionalValue(conditions, elseValue)
+		if err != nil {
+			p.AddWarning(ErrInvalidIcuMessage.WithArgs(fullKey, err))
+			return nil, false
+		}
+		return conditional, true
+	}
+
+	var exact []types.PluralExact
+	var forms []types.PluralForm
+	for i, option := range complex.Options {
+		if strings.HasPrefix(option.Key, "=") && isNumber(option.Key[1:]) {
+			exact = append(exact, types.PluralExact{Number: option.Key[1:], Value: branches[i].(types.Pluralizable)})
+			continue
+		}
+		category, ok := types.ParsePluralCategory(option.Key)
+		if !ok {
+			p.AddWarning(ErrInvalidIcuMessage.WithArgs(fullKey, ErrIcuInvalidPluralKey.WithArgs(complex.Kind, option.Key, complex.Name)))
+			return nil, false
+		}
+		forms = append(forms, types.PluralForm{Category: category, Value: branches[i].(types.Pluralizable)})
+	}
+	plural, err := types.NewPluralValue(arg, exact, forms)
+	if err != nil {
+		p.AddWarning(ErrInvalidIcuMessage.WithArgs(fullKey, err))
+		return nil, false
+	}
+	plural.Ordinal = complex.Kind == icuSelectOrdinal
+	return plural, true
+}
+
+// icuLeaf turns text, simple arguments and references into a literal or parametrized value
+func (p *JsonParser) icuLeaf(fullKey string, nodes []icuNode, argList *types.ArgumentList) (types.MessageValue, bool) {
+	var textSegments []*types.ValueString
+	var usedArgs []*types.UsedArgument
+	text := strings.Builder{}
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case icuText:
+			text.WriteString(string(n))
+		case icuArgument:
+			arg, found := argList.GetArgument(n.Name)
+			if !found {
+				panic(fmt.Errorf("the argument %s of the path %s was not added before lifting the ICU message", n.Name, fullKey))
+			}
+			_, format := p.icuArgumentType(n)
+			textSegments = append(textSegments, types.NewStringLiteralValue(text.String()))
+			usedArgs = append(usedArgs, &types.UsedArgument{Argument: arg, Format: format})
+			text.Reset()
+		case icuReference:
+			textSegments = append(textSegments, types.NewStringLiteralValue(text.String()))
+			usedArgs = append(usedArgs, &types.UsedArgument{Reference: &types.MessageReference{Path: strings.Split(n.Path, ".")}})
+			text.Reset()
+		}
+	}
+	if len(usedArgs) == 0 {
+		return types.NewStringLiteralValue(text.String()), true
+	}
+	textSegments = append(textSegments, types.NewStringLiteralValue(text.String()))
+	parametrized, err := types.NewParametrizedStringValue(textSegments, usedArgs)
+	if err != nil {
+		p.AddWarning(ErrInvalidIcuMessage.WithArgs(fullKey, err))
+		return nil, false
+	}
+	return parametrized, true
+}
+
+// EscapeIcuText escapes text to be used as literal text of an ICU message. In the options of plural
+// arguments the # is escaped too
+func EscapeIcuText(text string, inPlural bool) string {
+	sb := strings.Builder{}
+	for _, c := range text {
+		switch {
+		case c == '\'':
+			sb.WriteString("''")
+		case c == '{' || c == '}' || (c == '#' && inPlural):
+			sb.WriteString("'" + string(c) + "'")
+		default:
+			sb.WriteRune(c)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,147 @@
+package parse
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseIcuMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		want     string  // the message written by describe, empty if it is not valid
+		warnings []error // the expected warnings
+	}{
+		{name: "literal", message: "Hello", want: "Hello"},
+		{name: "argument", message: "Hello {name}", want: "Hello {name:any}"},
+		{name: "native argument", message: "Hello {name:str}", want: "Hello {name:string}"},
+		{name: "reference", message: "Welcome to {@app.name}", want: "Welcome to {@app.name}"},
+		{name: "number", message: "{n, number}", want: "{n:number}"},
+		{name: "integer", message: "{n, number, integer}", want: "{n:integer}"},
+		{name: "percent", message: "{n, number, percent}", want: "{n:percent}"},
+		{name: "currency", message: "{n, number, ::currency/EUR}", want: "{n:currency:EUR}"},
+		{name: "date with style", message: "{d, date, long}", want: "{d:date:long}"},
+		{name: "date with skeleton", message: "{d, date, ::yMMMd}", want: "{d:date:yMMMd}"},
+		{name: "time", message: "{d, time, short}", want: "{d:time:short}"},
+		{
+			name:     "unsupported number style",
+			message:  "{n, number, #,##0.0}",
+			want:     "{n:number}",
+			warnings: []error{ErrIcuUnsupportedStyle},
+		},
+		{
+			name:    "select",
+			message: "{gender, select, male {He} female {She} other {They}} left",
+			want:    `?(gender == "male": He left; gender == "female": She left; else: They left)`,
+		},
+		{
+			name:    "plural",
+			message: "You have {count, plural, =0 {no messages} one {# message} other {# messages}}",
+			want:    "#count(=0: You have no messages; one: You have {count:integer} message; other: You have {count:integer} messages)",
+		},
+		{
+			name:    "selectordinal",
+			message: "{pos, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}",
+			want:    "#pos ordinal(one: {pos:integer}st; two: {pos:integer}nd; few: {pos:integer}rd; other: {pos:integer}th)",
+		},
+		{
+			name:    "nested plural",
+			message: "{gender, select, male {{n, plural, one {He has # cat} other {He has # cats}}} other {{n, plural, one {They have # cat} other {They have # cats}}}}",
+			want:    `?(gender == "male": #n(one: He has {n:integer} cat; other: He has {n:integer} cats); else: #n(one: They have {n:integer} cat; other: They have {n:integer} cats))`,
+		},
+		{
+			name:    "# of the innermost plural",
+			message: "{a, plural, other {{b, plural, other {# of {a}}}}}",
+			want:    "#a(other: #b(other: {b:integer} of {a:integer}))",
+		},
+		{name: "# outside of plurals", message: "Item #1", want: "Item #1"},
+		{name: "escaped apostrophe", message: "It''s {name}", want: "It's {name:any}"},
+		{name: "lone apostrophe", message: "It's here", want: "It's here"},
+		{name: "quoted braces", message: "'{name}' is {name}", want: "{name} is {name:any}"},
+		{name: "quoted apostrophe inside quotes", message: "'{It''s}'", want: "{It's}"},
+		{
+			name:    "quoted #",
+			message: "{n, plural, other {'#'#}}",
+			want:    "#n(other: #{n:integer})",
+		},
+		{name: "unclosed argument", message: "Hello {name", warnings: []error{ErrInvalidIcuMessage, ErrIcuSyntax}},
+		{name: "unexpected }", message: "Hello }", warnings: []error{ErrInvalidIcuMessage, ErrIcuSyntax}},
+		{name: "invalid argument name", message: "{1name}", warnings: []error{ErrInvalidIcuMessage, ErrIcuSyntax}},
+		{name: "invalid reference", message: "{@}", warnings: []error{ErrInvalidIcuMessage, ErrIcuSyntax}},
+		{name: "unclosed option", message: "{n, plural, other {items}", warnings: []error{ErrInvalidIcuMessage, ErrIcuSyntax}},
+		{name: "option without pattern", message: "{n, plural, other items}", warnings: []error{ErrInvalidIcuMessage, ErrIcuSyntax}},
+		{
+			name:     "plural offset",
+			message:  "{n, plural, offset:1 other {# more}}",
+			warnings: []error{ErrInvalidIcuMessage, ErrIcuSyntax},
+		},
+		{name: "missing other", message: "{n, plural, one {one}}", warnings: []error{ErrInvalidIcuMessage, ErrIcuMissingOther}},
+		{name: "select missing other", message: "{g, select, male {He}}", warnings: []error{ErrInvalidIcuMessage, ErrIcuMissingOther}},
+		{
+			name:     "invalid plural key",
+			message:  "{n, plural, lots {many} other {#}}",
+			warnings: []error{ErrInvalidIcuMessage, ErrIcuInvalidPluralKey},
+		},
+		{
+			name:     "duplicated option",
+			message:  "{n, plural, one {a} one {b} other {c}}",
+			warnings: []error{ErrInvalidIcuMessage, ErrIcuDuplicatedOption},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			message, _ := json.Marshal(test.message)
+			messages, warnings := parseFile(t, "en.json", `{"key": `+string(message)+`, "app": {"name": "App"}}`, Options{Icu: true})
+			checkWarnings(t, warnings, test.warnings...)
+			if messages["key"] != test.want {
+				t.Errorf("expected %q, got %q", test.want, messages["key"])
+			}
+		})
+	}
+}
+
+func TestParseIcuPattern(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string // the start of the error
+	}{
+		{message: "Hello {name", want: "unclosed argument"},
+		{message: "Hello {name, number", want: "unclosed argument"},
+		{message: "Hello }", want: "unexpected '}'"},
+		{message: "{name;}", want: "invalid argument name"},
+		{message: "{name number}", want: "expected ',' or '}'"},
+		{message: "{@.name}", want: "invalid message reference"},
+		{message: "{n, plural, offset:1 other {#}}", want: "plural offsets are not supported"},
+		{message: "{n, plural, other {#}", want: "unclosed argument"},
+		{message: "{n, plural, other {#", want: "unclosed option"},
+		{message: "{n, plural, other #}", want: "expected '{'"},
+		{message: "{n, plural, {#}}", want: "expected an option"},
+	}
+	for _, test := range tests {
+		t.Run(test.message, func(t *testing.T) {
+			_, err := ParseIcuPattern(test.message)
+			if !errors.Is(err, ErrIcuSyntax) || !strings.HasPrefix(err.Error(), test.want) {
+				t.Errorf("expected the error %q, got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestParseIcuLines(t *testing.T) {
+	messages, warnings := parseFile(t, "en.json", `{
+		"lines": ["Hello {name}", "It''s {n, number}"],
+		"plural": ["Hello", "{n, plural, one {# item} other {# items}}"]
+	}`, Options{Icu: true})
+	checkWarnings(t, warnings)
+	want := map[string]string{
+		"lines":  "[Hello {name:any}|It's {n:number}]",
+		"plural": "#n(one: Hello\n{n:integer} item; other: Hello\n{n:integer} items)",
+	}
+	for path, message := range want {
+		if messages[path] != message {
+			t.Errorf("%s: expected %q, got %q", path, message, messages[path])
+		}
+	}
+}
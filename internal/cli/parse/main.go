@@ -1,9 +1,9 @@
 package parse
 
 import (
-	"encoding/json"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/MrNemo64/go-n-i18n/internal/cli/assert"
@@ -25,6 +25,20 @@ var (
 	ErrInvalidConditionalEntry                = util.MakeError("the entry %s in the lang %s is marked as conditional but no conditions are provided")
 	ErrInvalidConditionalCondition            = util.MakeError("the condition %s in the path %s in the lang %s is not a valid conditional value")
 	ErrInvalidConditional                     = util.MakeError("the conditional in the path %s in the lang %s is not a valid: %w")
+	ErrInvalidPluralEntry                     = util.MakeError("the entry %s in the lang %s is marked as plural but no plural forms are provided")
+	ErrInvalidPluralForm                      = util.MakeError("the plural form '%s' in the path %s in the lang %s is not a valid, expected zero, one, two, few, many, other or =<number>")
+	ErrInvalidPluralFormValue                 = util.MakeError("the plural form %s in the path %s in the lang %s is not a valid plural value")
+	ErrInvalidPluralArgument                  = util.MakeError("the plural argument '%v' in the path %s in the lang %s is not a valid argument name")
+	ErrInvalidPluralOrdinal                   = util.MakeError("the `_ordinal` of the plural in the path %s in the lang %s must be a boolean but is %v")
+	ErrInvalidPlural                          = util.MakeError("the plural in the path %s in the lang %s is not a valid: %w")
+	ErrPluralArgumentNotNumeric               = util.MakeError("the plural argument %s in the path %s is of type %s but it must be an integer or a float")
+
+	ErrInvalidArgsDeclaration  = util.MakeError("the `_args` in the path %s in the lang %s must be a list of \"name:type:format\" strings but is %v")
+	ErrInvalidArgDeclaration   = util.MakeError("invalid argument declaration '%v' in the path %s in the lang %s, expected \"name:type:format\"")
+	ErrCouldNotDeclareArgs     = util.MakeError("could not declare the arguments in the path %s in the lang %s: %w")
+	ErrArgsWithoutValue        = util.MakeError("the entry %s in the lang %s declares its arguments or description but has no `_value`")
+	ErrUnknownDeclaredEntryKey = util.MakeError("unknown key '%s' in the entry %s in the lang %s, only `_args`, `_value` and `_description` are allowed")
+	ErrInvalidDescription      = util.MakeError("the `_description` of the entry %s in the lang %s must be a string but is %v")
 
 	ErrKeyIsConditionalButValueIsNotObject = util.MakeError("invalid key '%s': has the ? prefix so it's a conditional key but the value is not an object: %v")
 	ErrCouldNotAddEntry                    = util.MakeError("could not add %s entry %s: %w")
@@ -32,14 +46,34 @@ var (
 )
 
 var ArgumentExtractor = regexp.MustCompile(`\{([a-zA-Z_]\w*):?(\w*)?:?([\w\.]*)?\}`)
+var ArgumentName = regexp.MustCompile(`^[a-zA-Z_]\w*$`)
+var ArgumentDeclaration = regexp.MustCompile(`^([a-zA-Z_]\w*):?(\w*)?:?([\w\.]*)?$`)
+
+// ReferencePath matches the dot separated path of a message reference like {@common.product-name}
+var ReferencePath = regexp.MustCompile(`^[\w-]+(?:\.[\w-]+)*$`)
+
+// SegmentExtractor matches the message references, with the path in the first group, and the arguments, with the
+// groups of ArgumentExtractor after it
+var SegmentExtractor = regexp.MustCompile(`\{@([\w-]+(?:\.[\w-]+)*)\}|\{([a-zA-Z_]\w*):?(\w*)?:?([\w\.]*)?\}`)
+
+// DefaultPluralArgument is the argument used to select the plural form if the entry does not specify one with `_arg`
+const DefaultPluralArgument = "count"
+
+type Options struct {
+	// Icu parses the messages with the ICU MessageFormat grammar instead of the {name:type:format} one
+	Icu bool
+}
 
 type JsonParser struct {
 	*util.WarningsCollector
 	argProvider *types.ArgumentProvider
+	options     Options
+	source      string
+	icu         bool // the messages of the file being parsed use the ICU grammar
 }
 
-func ParseJson(walker DirWalker, wc *util.WarningsCollector, argProvider *types.ArgumentProvider) (*types.MessageBag, error) {
-	return (&JsonParser{WarningsCollector: wc, argProvider: argProvider}).ParseWalker(walker)
+func ParseJson(walker DirWalker, wc *util.WarningsCollector, argProvider *types.ArgumentProvider, options Options) (*types.MessageBag, error) {
+	return (&JsonParser{WarningsCollector: wc, argProvider: argProvider, options: options}).ParseWalker(walker)
 }
 
 func (p *JsonParser) ParseWalker(walker DirWalker) (*types.MessageBag, error) {
@@ -47,6 +81,8 @@ func (p *JsonParser) ParseWalker(walker DirWalker) (*types.MessageBag, error) {
 	for {
 		file, err := walker.Next()
 		if err == ErrNoMoreFiles {
+			p.InferConditionArguments(root)
+			p.DefaultPluralArguments(root)
 			return root, nil
 		}
 		if err != nil {
@@ -56,11 +92,17 @@ func (p *JsonParser) ParseWalker(walker DirWalker) (*types.MessageBag, error) {
 		if err != nil {
 			return nil, ErrIO.WithArgs(file.FullPath(), err)
 		}
-		entries := orderedmap.New()
-		if err := json.Unmarshal(content, entries); err != nil {
+		decoder, err := DecoderFor(file.FullPath())
+		if err != nil {
+			return nil, err
+		}
+		entries, err := decoder(content)
+		if err != nil {
 			return nil, ErrUnmarshal.WithArgs(file.FullPath(), err)
 		}
 
+		p.source = file.FullPath()
+		p.icu = p.options.Icu || UsesIcuGrammar(file.FullPath())
 		dest, err := root.FindOrCreateChildBag(file.Path()...)
 		if err != nil {
 			return nil, err
@@ -93,22 +135,47 @@ func (p *JsonParser) ParseGroupOfMessagesInto(dest *types.MessageBag, entries *o
 			}
 			args := types.NewArgumentList()
 			parsed, ok := p.ParseConditionalMessageValue(types.PathAsStr(types.ResolveFullPath(dest, key)), &mapValue, args, lang)
+			if ok {
+				p.addInstance(dest, key, lang, args, parsed)
+			}
+			continue
+		}
+
+		if strings.HasPrefix(key, "#") { // is plural?
+			key = key[1:]
+			if err := types.CheckKey(key); err != nil {
+				p.AddWarning(ErrInvalidKeyName.WithArgs(types.PathAsStr(types.ResolveFullPath(dest, key)), err))
+				continue
+			}
+			mapValue, ok := value.(orderedmap.OrderedMap)
+			if !ok {
+				p.WarningsCollector.AddWarning(ErrInvalidPluralEntry.WithArgs(types.PathAsStr(types.ResolveFullPath(dest, key)), lang))
+				continue
+			}
+			args := types.NewArgumentList()
+			description, ok := p.ParseDescription(types.PathAsStr(types.ResolveFullPath(dest, key)), &mapValue, lang)
 			if !ok {
 				continue
 			}
-			newEntry, err := types.NewMessageInstance(key)
-			assert.NoError(err)                                // key is valid, we checked it above
-			assert.NoError(newEntry.AddArgs(args))             // entry is empty, it must accept the new args
-			assert.NoError(newEntry.AddLanguage(lang, parsed)) // entry is empty, it must accept the new language
-			if err := dest.AddChildren(newEntry); err != nil {
-				p.AddWarning(ErrAddChildren.WithArgs(key, dest.PathAsStr(), err))
+			parsed, ok := p.ParsePluralMessageValue(types.PathAsStr(types.ResolveFullPath(dest, key)), &mapValue, args, lang)
+			if ok {
+				p.addDescribedInstance(dest, key, lang, args, parsed, description)
 			}
 			continue
 		}
 
 		if inner, ok := value.(orderedmap.OrderedMap); ok { // is bag or parametrized with `_args` to specify args
-			if _, found := inner.Get("_args"); found { // parametrized with `_args`
-				panic("todo")
+			if isDeclaredMessage(&inner) { // parametrized with `_args` or described with `_description`
+				if err := types.CheckKey(key); err != nil {
+					p.AddWarning(ErrInvalidKeyName.WithArgs(types.PathAsStr(types.ResolveFullPath(dest, key)), err))
+					continue
+				}
+				args := types.NewArgumentList()
+				parsed, description, ok := p.ParseDeclaredMessageValue(types.PathAsStr(types.ResolveFullPath(dest, key)), &inner, args, lang)
+				if ok {
+					p.addDescribedInstance(dest, key, lang, args, parsed, description)
+				}
+				continue
 			} else { // bag
 				name := ""
 				if strings.Contains(key, ":") {
@@ -155,24 +222,40 @@ func (p *JsonParser) ParseGroupOfMessagesInto(dest *types.MessageBag, entries *o
 
 		args := types.NewArgumentList()
 		parsed, ok := p.ParseMessageValue(types.PathAsStr(types.ResolveFullPath(dest, key)), value, args)
-		if !ok {
-			continue
-		}
-		newEntry, err := types.NewMessageInstance(key)
-		assert.NoError(err)                                // key is valid, we checked it above
-		assert.NoError(newEntry.AddArgs(args))             // entry is empty, it must accept the new args
-		assert.NoError(newEntry.AddLanguage(lang, parsed)) // entry is empty, it must accept the new language
-		if err := dest.AddChildren(newEntry); err != nil {
-			p.AddWarning(ErrAddChildren.WithArgs(key, dest.PathAsStr(), err))
+		if ok {
+			p.addInstance(dest, key, lang, args, parsed)
 		}
 	}
 	return nil
 }
 
+func (p *JsonParser) addInstance(dest *types.MessageBag, key, lang string, args *types.ArgumentList, parsed types.MessageValue) {
+	p.addDescribedInstance(dest, key, lang, args, parsed, "")
+}
+
+func (p *JsonParser) addDescribedInstance(dest *types.MessageBag, key, lang string, args *types.ArgumentList, parsed types.MessageValue, description string) {
+	newEntry, err := types.NewMessageInstance(key)
+	assert.NoError(err)                                // key is valid, it must be checked before parsing the value
+	assert.NoError(newEntry.AddArgs(args))             // entry is empty, it must accept the new args
+	assert.NoError(newEntry.AddLanguage(lang, parsed)) // entry is empty, it must accept the new language
+	newEntry.SetSource(lang, p.source)
+	if description != "" {
+		newEntry.SetDescription(lang, description)
+	}
+	if err := dest.AddChildren(newEntry); err != nil {
+		p.AddWarning(ErrAddChildren.WithArgs(key, dest.PathAsStr(), err))
+	}
+}
+
 func (p *JsonParser) ParseMessageValue(fullKey string, value any, argList *types.ArgumentList) (types.MessageValue, bool) {
 	switch value.(type) {
+	case nil: // a text that is not translated yet
+		return types.NewUntranslatedValue(), true
 	case string:
 		str := value.(string)
+		if p.icu {
+			return p.ParseIcuMessage(fullKey, str, argList)
+		}
 		if !p.HasArguments(str) {
 			return types.NewStringLiteralValue(str), true
 		}
@@ -183,6 +266,9 @@ func (p *JsonParser) ParseMessageValue(fullKey string, value any, argList *types
 			p.AddWarning(ErrUnknownEntryType.WithArgs(fullKey, value))
 			return nil, false
 		}
+		if p.icu {
+			return p.ParseIcuLines(fullKey, util.Map(arr, func(_ int, line *any) string { return (*line).(string) }), argList)
+		}
 		lines := make([]types.Multilineable, 0)
 		for _, line := range arr {
 			str := line.(string)
@@ -205,13 +291,100 @@ func (p *JsonParser) ParseMessageValue(fullKey string, value any, argList *types
 	}
 }
 
+// isDeclaredMessage reports if an object is a message of the form {"_args": [...], "_value": ..., "_description": ...}
+// instead of a bag. Bags can not have these keys since they start with _
+func isDeclaredMessage(value *orderedmap.OrderedMap) bool {
+	for _, key := range []string{"_args", "_value", "_description"} {
+		if _, found := value.Get(key); found {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseDeclaredMessageValue parses an entry of the form {"_args": [...], "_value": ..., "_description": ...}
+// where `_args` and `_description` are optional. Returns the message and its description
+func (p *JsonParser) ParseDeclaredMessageValue(fullKey string, value *orderedmap.OrderedMap, argList *types.ArgumentList, lang string) (types.MessageValue, string, bool) {
+	for _, key := range value.Keys() {
+		if key != "_args" && key != "_value" && key != "_description" {
+			p.AddWarning(ErrUnknownDeclaredEntryKey.WithArgs(key, fullKey, lang))
+			return nil, "", false
+		}
+	}
+	if declared, found := value.Get("_args"); found {
+		if !p.ParseArgsDeclaration(fullKey, declared, argList, lang) {
+			return nil, "", false
+		}
+	}
+	description, ok := p.ParseDescription(fullKey, value, lang)
+	if !ok {
+		return nil, "", false
+	}
+	message, found := value.Get("_value")
+	if !found {
+		p.AddWarning(ErrArgsWithoutValue.WithArgs(fullKey, lang))
+		return nil, "", false
+	}
+	parsed, ok := p.ParseMessageValue(fullKey, message, argList)
+	return parsed, description, ok
+}
+
+// ParseDescription returns the `_description` of a declared or plural message, empty if it has none
+func (p *JsonParser) ParseDescription(fullKey string, value *orderedmap.OrderedMap, lang string) (string, bool) {
+	specified, found := value.Get("_description")
+	if !found {
+		return "", true
+	}
+	description, ok := specified.(string)
+	if !ok {
+		p.AddWarning(ErrInvalidDescription.WithArgs(fullKey, lang, specified))
+		return "", false
+	}
+	return description, true
+}
+
+// ParseArgsDeclaration parses the `_args` list of "name:type:format" strings and declares them in argList
+func (p *JsonParser) ParseArgsDeclaration(fullKey string, value any, argList *types.ArgumentList, lang string) bool {
+	list, ok := value.([]any)
+	if !ok || !p.IsStringSlice(list) {
+		p.AddWarning(ErrInvalidArgsDeclaration.WithArgs(fullKey, lang, value))
+		return false
+	}
+	declared := make([]*types.MessageArgument, 0, len(list))
+	for _, spec := range list {
+		match := ArgumentDeclaration.FindStringSubmatch(spec.(string))
+		if match == nil {
+			p.AddWarning(ErrInvalidArgDeclaration.WithArgs(spec, fullKey, lang))
+			return false
+		}
+		argType, found := p.argProvider.FindArgument(match[2])
+		if !found {
+			if match[2] != "" {
+				p.WarningsCollector.AddWarning(ErrUnknwonArgumentType.WithArgs(match[2], fullKey))
+			}
+			argType = p.argProvider.UnknwonType()
+		}
+		declared = append(declared, &types.MessageArgument{Name: match[1], Type: argType, Format: match[3]})
+	}
+	if err := argList.Declare(declared); err != nil {
+		p.AddWarning(ErrCouldNotDeclareArgs.WithArgs(fullKey, lang, err))
+		return false
+	}
+	return true
+}
+
 func (p *JsonParser) ParseConditionalMessageValue(fullKey string, value *orderedmap.OrderedMap, argList *types.ArgumentList, lang string) (*types.ValueConditional, bool) {
 	finishOk := true
 	var conditions []types.Condition
 	var elseCondition types.Conditionable
+	if declared, found := value.Get("_args"); found {
+		if !p.ParseArgsDeclaration(fullKey, declared, argList, lang) {
+			return nil, false
+		}
+	}
 	for _, condition := range value.Keys() {
 		if condition == "_args" {
-			panic(fmt.Errorf("specifying the args in a `_args` entry is not yet supported"))
+			continue
 		}
 		value, found := value.Get(condition)
 		if !found {
@@ -237,6 +410,12 @@ func (p *JsonParser) ParseConditionalMessageValue(fullKey string, value *ordered
 			})
 		}
 	}
+	// the arguments only used in conditions are added after the ones used in the values
+	for i := range conditions {
+		cleaned, ok := p.ParseCondition(fullKey, conditions[i].Condition, argList)
+		finishOk = finishOk && ok
+		conditions[i].Condition = cleaned
+	}
 	if !finishOk {
 		return nil, false
 	}
@@ -248,12 +427,115 @@ func (p *JsonParser) ParseConditionalMessageValue(fullKey string, value *ordered
 	return cond, true
 }
 
+func (p *JsonParser) ParsePluralMessageValue(fullKey string, value *orderedmap.OrderedMap, argList *types.ArgumentList, lang string) (*types.ValuePlural, bool) {
+	argName := DefaultPluralArgument
+	if specified, found := value.Get("_arg"); found {
+		name, ok := specified.(string)
+		if !ok || !ArgumentName.MatchString(name) {
+			p.AddWarning(ErrInvalidPluralArgument.WithArgs(specified, fullKey, lang))
+			return nil, false
+		}
+		argName = name
+	}
+	ordinal := false
+	if specified, found := value.Get("_ordinal"); found {
+		switch specified {
+		case true, "true":
+			ordinal = true
+		case false, "false":
+		default:
+			p.AddWarning(ErrInvalidPluralOrdinal.WithArgs(fullKey, lang, specified))
+			return nil, false
+		}
+	}
+	if declared, found := value.Get("_args"); found {
+		if !p.ParseArgsDeclaration(fullKey, declared, argList, lang) {
+			return nil, false
+		}
+	}
+	// added before parsing the forms so the plural argument is the first one if the arguments are not declared
+	argument, err := argList.AddArgument(&types.MessageArgument{Name: argName, Type: p.argProvider.UnknwonType()})
+	assert.NoError(err) // the unknown type is compatible with any type
+
+	finishOk := true
+	var exact []types.PluralExact
+	var forms []types.PluralForm
+	for _, form := range value.Keys() {
+		if form == "_arg" || form == "_args" || form == "_ordinal" || form == "_description" {
+			continue
+		}
+		value, found := value.Get(form)
+		if !found {
+			panic(fmt.Errorf("the ordered map is missing the key '%s', this is a bug in the github.com/iancoleman/orderedmap library. Dest: %s", form, fullKey))
+		}
+		category, isCategory := types.ParsePluralCategory(form)
+		isExact := !isCategory && strings.HasPrefix(form, "=") && isNumber(form[1:])
+		if !isCategory && !isExact {
+			finishOk = false
+			p.AddWarning(ErrInvalidPluralForm.WithArgs(form, fullKey, lang))
+			continue
+		}
+		parsed, ok := p.ParseMessageValue(fullKey+"."+form, value, argList)
+		if !ok {
+			finishOk = false
+			continue
+		}
+		formValue, ok := parsed.(types.Pluralizable)
+		if !ok {
+			finishOk = false
+			p.AddWarning(ErrInvalidPluralFormValue.WithArgs(form, fullKey, lang))
+			continue
+		}
+		if isExact {
+			exact = append(exact, types.PluralExact{Number: form[1:], Value: formValue})
+		} else {
+			forms = append(forms, types.PluralForm{Category: category, Value: formValue})
+		}
+	}
+	if !finishOk {
+		return nil, false
+	}
+	plural, err := types.NewPluralValue(argument, exact, forms)
+	if err != nil {
+		p.WarningsCollector.AddWarning(ErrInvalidPlural.WithArgs(fullKey, lang, err))
+		return nil, false
+	}
+	plural.Ordinal = ordinal
+	return plural, true
+}
+
+// DefaultPluralArguments gives the integer type to plural arguments whose type was not specified in any language
+// and reports the ones that are not numeric
+func (p *JsonParser) DefaultPluralArguments(root *types.MessageBag) {
+	integer := p.argProvider.FindArgumentOrUnknwonType("int")
+	for _, instance := range root.Instances() {
+		for _, lang := range instance.Languages().Get() {
+			types.VisitValues(instance.MessageMust(lang), func(value types.MessageValue) {
+				plural, ok := value.(*types.ValuePlural)
+				if !ok {
+					return
+				}
+				arg, found := instance.Args().GetArgument(plural.Argument.Name)
+				assert.True(found, "plural arguments are always added to the argument list")
+				if arg.Type.IsUnknown {
+					arg.Type = integer
+				} else if !arg.Type.IsNumeric() {
+					p.AddWarning(ErrPluralArgumentNotNumeric.WithArgs(arg.Name, instance.PathAsStr(), arg.Type.Name))
+				}
+			})
+		}
+	}
+}
+
 func (p *JsonParser) ParseParametrizedMessage(fullKey string, str string, argList *types.ArgumentList) (*types.ValueParametrized, bool) {
 	textSegments, arguments := p.SeparateArgumentsFromText(str)
 	if len(textSegments) != len(arguments)+1 {
 		panic(fmt.Errorf("JsonParser.SeparateArgumentsFromText returned an unexpected amount of text segments (%d) and arguments (%d) for the path %s", len(textSegments), len(arguments), fullKey))
 	}
 	usedArgs := util.Map(arguments, func(index int, foundArg *foundArgument) *types.UsedArgument {
+		if foundArg.Reference != "" {
+			return &types.UsedArgument{Reference: &types.MessageReference{Path: strings.Split(foundArg.Reference, ".")}}
+		}
 		argType, found := p.argProvider.FindArgument(foundArg.Type)
 		if !found {
 			if foundArg.Type != "" {
@@ -289,6 +571,8 @@ type foundArgument struct {
 	Name   string
 	Type   string
 	Format string
+	// Reference is the path of the referenced message if the argument is a {@path} reference
+	Reference string
 }
 
 func (p *JsonParser) SeparateArgumentsFromText(message string) ([]string, []foundArgument) {
@@ -297,7 +581,7 @@ func (p *JsonParser) SeparateArgumentsFromText(message string) ([]string, []foun
 
 	// Track the position as we move through the string
 	lastIndex := 0
-	matches := ArgumentExtractor.FindAllStringSubmatchIndex(message, -1)
+	matches := SegmentExtractor.FindAllStringSubmatchIndex(message, -1)
 
 	// If the first match starts at index 0, add an empty text segment at the beginning
 	if len(matches) > 0 && matches[0][0] == 0 {
@@ -315,22 +599,25 @@ func (p *JsonParser) SeparateArgumentsFromText(message string) ([]string, []foun
 			textSegments = append(textSegments, "")
 		}
 
+		lastIndex = end
+		if match[2] != -1 {
+			arguments = append(arguments, foundArgument{Reference: message[match[2]:match[3]]})
+			continue
+		}
+
 		// Extract components based on regex capture groups
-		name := message[match[2]:match[3]]
+		name := message[match[4]:match[5]]
 		argType := ""
 		format := ""
-		if match[4] != -1 {
-			argType = message[match[4]:match[5]]
-		}
 		if match[6] != -1 {
-			format = message[match[6]:match[7]]
+			argType = message[match[6]:match[7]]
+		}
+		if match[8] != -1 {
+			format = message[match[8]:match[9]]
 		}
 
 		// Create an Argument and add to the list
 		arguments = append(arguments, foundArgument{Name: name, Type: argType, Format: format})
-
-		// Update lastIndex to continue after this match
-		lastIndex = end
 	}
 
 	// Append any remaining text after the last argument
@@ -344,7 +631,12 @@ func (p *JsonParser) SeparateArgumentsFromText(message string) ([]string, []foun
 	return textSegments, arguments
 }
 
-func (*JsonParser) HasArguments(str string) bool { return ArgumentExtractor.MatchString(str) }
+func (*JsonParser) HasArguments(str string) bool { return SegmentExtractor.MatchString(str) }
+func isNumber(str string) bool {
+	_, err := strconv.ParseFloat(str, 64)
+	return err == nil
+}
+
 func (*JsonParser) IsStringSlice(arr []any) bool {
 	for i := range arr {
 		if _, ok := arr[i].(string); !ok {
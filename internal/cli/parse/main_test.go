@@ -0,0 +1,186 @@
+package parse
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+	"github.com/iancoleman/orderedmap"
+)
+
+// testFile is a message file of the tests, its language is its name without extension
+type testFile struct {
+	name    string
+	content string
+}
+
+func (f *testFile) Path() []string                { return nil }
+func (f *testFile) Language() string              { return strings.TrimSuffix(f.name, filepath.Ext(f.name)) }
+func (f *testFile) FullPath() string              { return f.name }
+func (f *testFile) ReadContents() ([]byte, error) { return []byte(f.content), nil }
+
+type testWalker struct {
+	files   []*testFile
+	current int
+}
+
+func (w *testWalker) Next() (FileEntry, error) {
+	if w.current >= len(w.files) {
+		return nil, ErrNoMoreFiles
+	}
+	w.current++
+	return w.files[w.current-1], nil
+}
+
+// parseFile parses the file and returns its messages written by describe, by path, and the warnings
+func parseFile(t *testing.T, name, content string, options Options) (map[string]string, []error) {
+	t.Helper()
+	wc := util.NewWarningsCollector()
+	bag, err := ParseJson(&testWalker{files: []*testFile{{name: name, content: content}}}, wc, types.NewArgumentProvider(), options)
+	if err != nil {
+		return nil, append(wc.Warnings(), err)
+	}
+	lang := (&testFile{name: name}).Language()
+	messages := make(map[string]string)
+	for _, instance := range bag.Instances() {
+		messages[instance.PathAsStr()] = describe(instance.MessageMust(lang))
+	}
+	return messages, wc.Warnings()
+}
+
+// describe writes a parsed value in a compact form to compare it in the tests. Arguments are written as
+// {name:type:format} with the name of their type, multiline messages as [line|line], conditionals as
+// ?(condition: value; else: value) and plurals as #argument(=0: value; one: value), with ordinal after the argument of
+// ordinal plurals
+func describe(value any) string {
+	switch v := value.(type) {
+	case *types.ValueString:
+		if types.IsUntranslated(v) {
+			return "null"
+		}
+		return v.Message()
+	case *types.ValueParametrized:
+		sb := strings.Builder{}
+		for i, arg := range v.Args {
+			sb.WriteString(v.TextSegments[i].Message())
+			switch {
+			case arg.IsReference():
+				sb.WriteString("{@" + arg.Reference.PathAsStr() + "}")
+			case arg.Format != "":
+				sb.WriteString("{" + arg.Argument.Name + ":" + arg.Argument.Type.Name + ":" + arg.Format + "}")
+			default:
+				sb.WriteString("{" + arg.Argument.Name + ":" + arg.Argument.Type.Name + "}")
+			}
+		}
+		sb.WriteString(v.TextSegments[len(v.TextSegments)-1].Message())
+		return sb.String()
+	case *types.ValueMultiline:
+		return "[" + strings.Join(util.Map(v.Lines, func(_ int, line *types.Multilineable) string { return describe(*line) }), "|") + "]"
+	case *types.ValueConditional:
+		var branches []string
+		for _, condition := range v.Conditions {
+			branches = append(branches, condition.Condition+": "+describe(condition.Value))
+		}
+		if v.Else != nil {
+			branches = append(branches, "else: "+describe(v.Else))
+		}
+		return "?(" + strings.Join(branches, "; ") + ")"
+	case *types.ValuePlural:
+		var forms []string
+		for _, exact := range v.Exact {
+			forms = append(forms, "="+exact.Number+": "+describe(exact.Value))
+		}
+		for _, form := range v.Forms {
+			forms = append(forms, form.Category.String()+": "+describe(form.Value))
+		}
+		ordinal := ""
+		if v.Ordinal {
+			ordinal = " ordinal"
+		}
+		return "#" + v.Argument.Name + ordinal + "(" + strings.Join(forms, "; ") + ")"
+	}
+	return "?"
+}
+
+// checkDecoded checks that the decoded messages are the expected json
+func checkDecoded(t *testing.T, entries *orderedmap.OrderedMap, want string) {
+	t.Helper()
+	encoded, err := EncodeJson(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotCompact, wantCompact := &bytes.Buffer{}, &bytes.Buffer{}
+	if err := json.Compact(gotCompact, encoded); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Compact(wantCompact, []byte(want)); err != nil {
+		t.Fatal(err)
+	}
+	if gotCompact.String() != wantCompact.String() {
+		t.Errorf("expected %s, got %s", wantCompact, gotCompact)
+	}
+}
+
+// checkWarnings checks that the warnings are the expected ones, in any order
+func checkWarnings(t *testing.T, warnings []error, want ...error) {
+	t.Helper()
+	for _, warning := range warnings {
+		expected := false
+		for _, target := range want {
+			expected = expected || errors.Is(warning, target)
+		}
+		if !expected {
+			t.Errorf("unexpected warning: %v", warning)
+		}
+	}
+	for _, target := range want {
+		found := false
+		for _, warning := range warnings {
+			found = found || errors.Is(warning, target)
+		}
+		if !found {
+			t.Errorf("expected the warning %v, got %v", target, warnings)
+		}
+	}
+}
+
+func TestParseJson(t *testing.T) {
+	messages, warnings := parseFile(t, "en.json", `{
+		"literal": "Hello",
+		"parametrized": "Hello {name:str}, you have {amount:float64:.2f}",
+		"reference": "Welcome to {@app.name}",
+		"multiline": ["First {name:str}", "Second"],
+		"?conditional": {"{count:int} > 1": "Many", "": "Few"},
+		"#plural": {"_arg": "n", "=0": "None", "one": "One", "other": "{n} items"},
+		"#ordinal": {"_ordinal": true, "one": "{count}st", "other": "{count}th"},
+		"declared": {"_args": ["count:int"], "_value": "Items"},
+		"untranslated": null,
+		"app": {"name": "App"}
+	}`, Options{})
+	checkWarnings(t, warnings)
+	want := map[string]string{
+		"literal":      "Hello",
+		"parametrized": "Hello {name:string}, you have {amount:float64:.2f}",
+		"reference":    "Welcome to {@app.name}",
+		"multiline":    "[First {name:string}|Second]",
+		"conditional":  "?(count > 1: Many; else: Few)",
+		"plural":       "#n(=0: None; one: One; other: {n:integer} items)",
+		"ordinal":      "#count ordinal(one: {count:integer}st; other: {count:integer}th)",
+		"declared":     "Items",
+		"untranslated": "null",
+		"app.name":     "App",
+	}
+	for path, message := range want {
+		if messages[path] != message {
+			t.Errorf("%s: expected %q, got %q", path, message, messages[path])
+		}
+	}
+	if len(messages) != len(want) {
+		t.Errorf("expected %d messages, got %v", len(want), messages)
+	}
+}
@@ -0,0 +1,132 @@
+package parse
+
+import (
+	"strings"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+var (
+	ErrUnknownReference  util.Error = util.MakeError("the message %s in the lang %s references the message %s, which does not exist")
+	ErrReferenceToBag               = util.MakeError("the message %s in the lang %s references %s, which is a group of messages and not a message")
+	ErrReferenceCycle               = util.MakeError("the message %s references itself: %s")
+	ErrReferenceArgument            = util.MakeError("could not forward the arguments of the message %s to the message %s that references it: %w")
+)
+
+// referenceState is the state of a message while resolving the references
+type referenceState int
+
+const (
+	referenceUnresolved referenceState = iota
+	referenceResolving
+	referenceResolved
+)
+
+type referenceResolver struct {
+	*util.WarningsCollector
+	root   *types.MessageBag
+	states map[*types.MessageInstance]referenceState
+	stack  []*types.MessageInstance
+}
+
+// ResolveReferences sets the target of every {@path} reference, looked up from the root, and adds the arguments the
+// referenced messages need to the arguments of the messages that reference them. Unknown references and reference
+// cycles are reported
+func ResolveReferences(root *types.MessageBag, wc *util.WarningsCollector) {
+	r := &referenceResolver{WarningsCollector: wc, root: root, states: make(map[*types.MessageInstance]referenceState)}
+	for _, instance := range root.Instances() {
+		r.resolve(instance)
+	}
+}
+
+// resolve resolves the references of an instance, resolving first the referenced messages so their arguments
+// are complete when forwarded
+func (r *referenceResolver) resolve(instance *types.MessageInstance) {
+	if r.states[instance] != referenceUnresolved {
+		return
+	}
+	r.states[instance] = referenceResolving
+	r.stack = append(r.stack, instance)
+	defer func() {
+		r.stack = r.stack[:len(r.stack)-1]
+		r.states[instance] = referenceResolved
+	}()
+
+	// the same message is usually referenced in every language, it is resolved once
+	targets := make(map[string]*types.MessageInstance)
+	for _, lang := range instance.Languages().Get() {
+		types.VisitValues(instance.MessageMust(lang), func(value types.MessageValue) {
+			parametrized, ok := value.(*types.ValueParametrized)
+			if !ok {
+				return
+			}
+			for _, used := range parametrized.Args {
+				if !used.IsReference() {
+					continue
+				}
+				path := used.Reference.PathAsStr()
+				target, found := targets[path]
+				if !found {
+					target = r.resolveReference(instance, lang, used.Reference)
+					targets[path] = target
+				}
+				used.Reference.Target = target
+			}
+		})
+	}
+}
+
+// resolveReference returns the referenced message once its arguments are added to the instance, nil if it can
+// not be referenced
+func (r *referenceResolver) resolveReference(instance *types.MessageInstance, lang string, reference *types.MessageReference) *types.MessageInstance {
+	target, ok := r.find(instance, lang, reference)
+	if !ok {
+		return nil
+	}
+	if r.states[target] == referenceResolving {
+		r.reportCycle(target)
+		return nil
+	}
+	r.resolve(target)
+	for _, arg := range target.Args().Args {
+		if _, err := instance.Args().AddArgument(&types.MessageArgument{Name: arg.Name, Type: arg.Type}); err != nil {
+			r.AddWarning(ErrReferenceArgument.WithArgs(target.PathAsStr(), instance.PathAsStr(), err))
+		}
+	}
+	return target
+}
+
+func (r *referenceResolver) find(instance *types.MessageInstance, lang string, reference *types.MessageReference) (*types.MessageInstance, bool) {
+	var entry types.MessageEntry = r.root
+	for _, key := range reference.Path {
+		if !entry.IsBag() {
+			r.AddWarning(ErrUnknownReference.WithArgs(instance.PathAsStr(), lang, reference.PathAsStr()))
+			return nil, false
+		}
+		child, found := entry.AsBag().GetEntry(key)
+		if !found {
+			r.AddWarning(ErrUnknownReference.WithArgs(instance.PathAsStr(), lang, reference.PathAsStr()))
+			return nil, false
+		}
+		entry = child
+	}
+	if entry.IsBag() {
+		r.AddWarning(ErrReferenceToBag.WithArgs(instance.PathAsStr(), lang, reference.PathAsStr()))
+		return nil, false
+	}
+	return entry.AsInstance(), true
+}
+
+// reportCycle reports the cycle that goes from the instance, which is being resolved, to the top of the stack
+// and back to the instance
+func (r *referenceResolver) reportCycle(instance *types.MessageInstance) {
+	var chain []string
+	for i := len(r.stack) - 1; i >= 0; i-- {
+		chain = append([]string{r.stack[i].PathAsStr()}, chain...)
+		if r.stack[i] == instance {
+			break
+		}
+	}
+	r.AddWarning(ErrReferenceCycle.WithArgs(instance.PathAsStr(), strings.Join(append(chain, instance.PathAsStr()), " -> ")))
+}
@@ -0,0 +1,171 @@
+package parse
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/iancoleman/orderedmap"
+)
+
+// goI18nTemplateArgument matches the {{.Name}} arguments of the go-i18n templates
+var goI18nTemplateArgument = regexp.MustCompile(`\{\{\s*\.([a-zA-Z_]\w*)\s*\}\}`)
+
+// goI18nMessageKeys are the keys a go-i18n message table may have
+var goI18nMessageKeys = []string{"id", "hash", "description", "leftdelim", "rightdelim", "zero", "one", "two", "few", "many", "other"}
+
+// DecodeToml decodes a toml file keeping the order in which the keys are defined. Tables written like go-i18n
+// messages are turned into their equivalent messages: tables with only an `other` message are literal or
+// parametrized messages and tables with plural forms are plural messages. Their `{{.Name}}` arguments are
+// turned into `{Name}` arguments and their `description` into the `_description` of the message
+func DecodeToml(content []byte) (*orderedmap.OrderedMap, error) {
+	var values map[string]any
+	meta, err := toml.Decode(string(content), &values)
+	if err != nil {
+		return nil, err
+	}
+	root := orderedmap.New()
+	for _, key := range meta.Keys() {
+		value := lookupTomlKey(values, key)
+		parent := root
+		for _, part := range key[:len(key)-1] {
+			child, found := parent.Get(part)
+			if !found { // implicit tables of dotted keys like a.b = "" or headers like [a.b]
+				child = orderedmap.New()
+				parent.Set(part, child)
+			}
+			parent = child.(*orderedmap.OrderedMap)
+		}
+		name := key[len(key)-1]
+		if _, found := parent.Get(name); found {
+			continue // tables can be reopened with dotted keys
+		}
+		if _, isTable := value.(map[string]any); isTable {
+			parent.Set(name, orderedmap.New())
+		} else {
+			parent.Set(name, tomlValue(value))
+		}
+	}
+	return convertGoI18nMessages(root), nil
+}
+
+func lookupTomlKey(values map[string]any, key toml.Key) any {
+	var current any = values
+	for _, part := range key {
+		current = current.(map[string]any)[part]
+	}
+	return current
+}
+
+// tomlValue converts a toml value into a message. Non string scalars are kept as their text
+func tomlValue(value any) any {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []any:
+		converted := make([]any, len(v))
+		for i := range v {
+			converted[i] = tomlValue(v[i])
+		}
+		return converted
+	case []map[string]any:
+		return v // arrays of tables are not messages, reported when parsing
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// convertGoI18nMessages converts the go-i18n message tables and templates and turns
+// the *orderedmap.OrderedMap tables into orderedmap.OrderedMap values like the json decoder does
+func convertGoI18nMessages(table *orderedmap.OrderedMap) *orderedmap.OrderedMap {
+	converted := orderedmap.New()
+	for _, key := range table.Keys() {
+		value, _ := table.Get(key)
+		switch v := value.(type) {
+		case *orderedmap.OrderedMap:
+			if !isGoI18nMessage(v) {
+				converted.Set(key, *convertGoI18nMessages(v))
+				continue
+			}
+			description, hasDescription := v.Get("description")
+			if plural, isPlural := goI18nPlural(v); isPlural {
+				if hasDescription {
+					plural.Set("_description", description)
+				}
+				converted.Set("#"+key, *plural)
+			} else if hasDescription {
+				declared := orderedmap.New()
+				other, _ := v.Get("other")
+				declared.Set("_value", convertGoI18nValue(other))
+				declared.Set("_description", description)
+				converted.Set(key, *declared)
+			} else {
+				other, _ := v.Get("other")
+				converted.Set(key, convertGoI18nValue(other))
+			}
+		default:
+			converted.Set(key, convertGoI18nValue(v))
+		}
+	}
+	return converted
+}
+
+func isGoI18nMessage(table *orderedmap.OrderedMap) bool {
+	if _, found := table.Get("other"); !found {
+		return false
+	}
+	for _, key := range table.Keys() {
+		if !slices.Contains(goI18nMessageKeys, key) {
+			return false
+		}
+	}
+	return true
+}
+
+// goI18nPlural returns the plural message of a go-i18n message table with plural forms. The argument of the plural
+// is PluralCount if the forms use it, go-i18n passes it separately from the template data, or Count otherwise
+func goI18nPlural(table *orderedmap.OrderedMap) (*orderedmap.OrderedMap, bool) {
+	plural := orderedmap.New()
+	plural.Set("_arg", "Count")
+	usesPluralCount := false
+	for _, key := range table.Keys() {
+		if key == "other" || !slices.Contains(pluralFormKeys, key) {
+			continue
+		}
+		value, _ := table.Get(key)
+		plural.Set(key, convertGoI18nValue(value))
+	}
+	if len(plural.Keys()) == 1 {
+		return nil, false
+	}
+	other, _ := table.Get("other")
+	plural.Set("other", convertGoI18nValue(other))
+	for _, key := range plural.Keys() {
+		if str, ok := plural.Values()[key].(string); ok && strings.Contains(str, "{PluralCount}") {
+			usesPluralCount = true
+		}
+	}
+	if usesPluralCount {
+		plural.Set("_arg", "PluralCount")
+	}
+	return plural, true
+}
+
+var pluralFormKeys = []string{"zero", "one", "two", "few", "many", "other"}
+
+func convertGoI18nValue(value any) any {
+	switch v := value.(type) {
+	case string:
+		return goI18nTemplateArgument.ReplaceAllString(v, "{$1}")
+	case []any:
+		converted := make([]any, len(v))
+		for i := range v {
+			converted[i] = convertGoI18nValue(v[i])
+		}
+		return converted
+	default:
+		return v
+	}
+}
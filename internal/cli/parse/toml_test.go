@@ -0,0 +1,114 @@
+package parse
+
+import "testing"
+
+func TestDecodeToml(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string // the decoded messages as json
+	}{
+		{
+			name:    "keys keep their order",
+			content: "zeta = \"Z\"\nalpha = \"A\"\n[menu]\nopen = \"Open\"\nclose = \"Close\"\n",
+			want:    `{"zeta": "Z", "alpha": "A", "menu": {"open": "Open", "close": "Close"}}`,
+		},
+		{
+			name:    "dotted keys",
+			content: "menu.open = \"Open\"\nmenu.close = \"Close\"\n",
+			want:    `{"menu": {"open": "Open", "close": "Close"}}`,
+		},
+		{
+			name:    "implicit parent tables",
+			content: "[menu.file]\nopen = \"Open\"\n",
+			want:    `{"menu": {"file": {"open": "Open"}}}`,
+		},
+		{
+			name:    "scalars and arrays",
+			content: "count = 12\nenabled = true\nlines = [\"First\", \"Second\"]\n",
+			want:    `{"count": "12", "enabled": "true", "lines": ["First", "Second"]}`,
+		},
+		{
+			name:    "go-i18n message with only other",
+			content: "[Welcome]\nother = \"Welcome {{.Name}}\"\n",
+			want:    `{"Welcome": "Welcome {Name}"}`,
+		},
+		{
+			name:    "go-i18n message with description",
+			content: "[Welcome]\ndescription = \"Shown after login\"\nother = \"Welcome {{ .Name }}\"\n",
+			want:    `{"Welcome": {"_value": "Welcome {Name}", "_description": "Shown after login"}}`,
+		},
+		{
+			name:    "go-i18n plural",
+			content: "[Cats]\none = \"One cat\"\nother = \"{{.Count}} cats\"\n",
+			want:    `{"#Cats": {"_arg": "Count", "one": "One cat", "other": "{Count} cats"}}`,
+		},
+		{
+			name:    "go-i18n plural with description",
+			content: "[Cats]\ndescription = \"Cats in the house\"\nzero = \"No cats\"\none = \"One cat\"\nother = \"{{.Count}} cats\"\n",
+			want:    `{"#Cats": {"_arg": "Count", "zero": "No cats", "one": "One cat", "other": "{Count} cats", "_description": "Cats in the house"}}`,
+		},
+		{
+			name:    "go-i18n plural using PluralCount",
+			content: "[Cats]\none = \"{{.PluralCount}} cat\"\nother = \"{{.PluralCount}} cats\"\n",
+			want:    `{"#Cats": {"_arg": "PluralCount", "one": "{PluralCount} cat", "other": "{PluralCount} cats"}}`,
+		},
+		{
+			name:    "go-i18n fields without plural forms",
+			content: "[Hello]\nid = \"Hello\"\nhash = \"sha1-abc\"\nother = \"Hello\"\n",
+			want:    `{"Hello": "Hello"}`,
+		},
+		{
+			name:    "tables with other keys are bags",
+			content: "[cats]\none = \"One cat\"\nother = \"Cats\"\nname = \"Cat\"\n",
+			want:    `{"cats": {"one": "One cat", "other": "Cats", "name": "Cat"}}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entries, err := DecodeToml([]byte(test.content))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			checkDecoded(t, entries, test.want)
+		})
+	}
+}
+
+func TestDecodeTomlInvalid(t *testing.T) {
+	if _, err := DecodeToml([]byte("key = \"unclosed\n")); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestParseToml(t *testing.T) {
+	messages, warnings := parseFile(t, "en.toml", `
+literal = "Hello"
+
+[Welcome]
+description = "Shown after login"
+other = "Welcome {{.Name}}"
+
+[Cats]
+one = "One cat"
+other = "{{.Count}} cats"
+
+[menu]
+open = "Open"
+`, Options{})
+	checkWarnings(t, warnings)
+	want := map[string]string{
+		"literal":   "Hello",
+		"Welcome":   "Welcome {Name:any}",
+		"Cats":      "#Count(one: One cat; other: {Count:integer} cats)",
+		"menu.open": "Open",
+	}
+	for path, message := range want {
+		if messages[path] != message {
+			t.Errorf("%s: expected %q, got %q", path, message, messages[path])
+		}
+	}
+	if len(messages) != len(want) {
+		t.Errorf("expected %d messages, got %v", len(want), messages)
+	}
+}
@@ -10,6 +10,10 @@ import (
 
 var ErrNoMoreFiles = errors.New("no more files in the walker")
 
+// ConfigFiles are the names of the config files of the cli, they are not message files if found in the root of the
+// messages directory
+var ConfigFiles = []string{"i18n.yaml", "i18n.yml", "i18n.json"}
+
 type DirWalker interface {
 	Next() (FileEntry, error)
 }
@@ -34,12 +38,15 @@ func (fe *IOFileEntry) ReadContents() ([]byte, error) { return os.ReadFile(fe.fu
 
 type ioDirWalker struct {
 	Origin  string
+	aliases map[string]string
 	files   []IOFileEntry
 	current int
 }
 
-func IoDirWalker(dir string, defLang string) (*ioDirWalker, error) {
-	walker := &ioDirWalker{Origin: dir, current: -1}
+// IoDirWalker walks the message files in dir. The languages of the file names found in aliases are replaced by their
+// value
+func IoDirWalker(dir string, defLang string, aliases map[string]string) (*ioDirWalker, error) {
+	walker := &ioDirWalker{Origin: dir, aliases: aliases, current: -1}
 	err := walker.loadFiles()
 	if err != nil {
 		return nil, err
@@ -65,11 +72,14 @@ func (walker *ioDirWalker) loadFiles() error {
 		if err != nil {
 			return err
 		}
-		if !d.IsDir() && strings.HasSuffix(d.Name(), ".json") {
+		if !d.IsDir() && IsSupportedFile(d.Name()) {
 			relPath, err := filepath.Rel(walker.Origin, path)
 			if err != nil {
 				return err
 			}
+			if slices.Contains(ConfigFiles, relPath) {
+				return nil
+			}
 
 			// Get the path components and remove the extension from the name
 			dirPath := filepath.Dir(relPath)
@@ -87,6 +97,21 @@ func (walker *ioDirWalker) loadFiles() error {
 
 			// Save only the file name without the extension
 			fileNameWithoutExt := strings.TrimSuffix(d.Name(), filepath.Ext(d.Name()))
+			// Files named like go-i18n's active.en.toml use the last part of the name as language
+			if idx := strings.LastIndex(fileNameWithoutExt, "."); idx >= 0 && strings.ToLower(filepath.Ext(d.Name())) == ".toml" {
+				fileNameWithoutExt = fileNameWithoutExt[idx+1:]
+			}
+			// Flutter's app_en.arb files have the language in their @@locale or after the first _ of their name
+			if strings.ToLower(filepath.Ext(d.Name())) == ".arb" {
+				content, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				fileNameWithoutExt = ArbLanguage(fileNameWithoutExt, content)
+			}
+			if alias, found := walker.aliases[fileNameWithoutExt]; found {
+				fileNameWithoutExt = alias
+			}
 
 			walker.files = append(walker.files, IOFileEntry{
 				path:     relativePath,
@@ -106,3 +131,11 @@ func (walker *ioDirWalker) Next() (FileEntry, error) {
 	}
 	return &walker.files[walker.current], nil
 }
+
+func (walker *ioDirWalker) Files() []FileEntry {
+	files := make([]FileEntry, len(walker.files))
+	for i := range walker.files {
+		files[i] = &walker.files[i]
+	}
+	return files
+}
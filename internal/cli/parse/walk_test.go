@@ -0,0 +1,79 @@
+package parse
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestIoDirWalker(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"es.json":                  "{}",
+		"active.en.toml":           "",
+		"translate.de.toml":        "",
+		"fr.toml":                  "",
+		"app_pt_BR.arb":            "{}",
+		"app.arb":                  `{"@@locale": "it"}`,
+		"menu/en.yaml":             "",
+		"menu/sub/en.json":         "{}",
+		"i18n.yaml":                "",
+		"notes.txt":                "",
+		"menu/active.es.json":      "{}",
+		"legacy/active.en_GB.toml": "",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	walker, err := IoDirWalker(dir, "en", map[string]string{"en_GB": "en-GB"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]string)
+	var order []string
+	for {
+		file, err := walker.Next()
+		if err == ErrNoMoreFiles {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		relPath, _ := filepath.Rel(dir, file.FullPath())
+		got[filepath.ToSlash(relPath)] = strings.Join(append(slices.Clone(file.Path()), file.Language()), "/")
+		order = append(order, file.Language())
+	}
+
+	want := map[string]string{
+		"es.json":                  "es",
+		"active.en.toml":           "en",
+		"translate.de.toml":        "de",
+		"fr.toml":                  "fr",
+		"app_pt_BR.arb":            "pt-BR",
+		"app.arb":                  "it",
+		"menu/en.yaml":             "menu/en",
+		"menu/sub/en.json":         "menu/sub/en",
+		"menu/active.es.json":      "menu/active.es", // only toml files use the go-i18n naming
+		"legacy/active.en_GB.toml": "legacy/en-GB",
+	}
+	for name, language := range want {
+		if got[name] != language {
+			t.Errorf("%s: expected %q, got %q", name, language, got[name])
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("expected %d files, got %v", len(want), got)
+	}
+	if firstOther := slices.IndexFunc(order, func(lang string) bool { return lang != "en" }); slices.Contains(order[firstOther:], "en") {
+		t.Errorf("expected the files of the default language first, got %v", order)
+	}
+}
@@ -0,0 +1,102 @@
+package pipeline
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/parse"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/validate"
+)
+
+var (
+	ErrCollectFiles          util.Error = util.MakeError("could not collect all files in the messages directory: %w")
+	ErrRegisterArgumentTypes            = util.MakeError("could not register the custom argument types: %w")
+	ErrParseFiles                       = util.MakeError("could not parse all files in the messages directory: %w")
+	ErrInvalidMessages                  = util.MakeError("found %d problems in the messages")
+	ErrNoDefaultLanguage                = util.MakeError("could not find messages of the default language %s")
+)
+
+type Options struct {
+	MessagesDirectory string
+	DefaultLanguage   string
+	ArgumentTypes     []string
+	LanguageAliases   map[string]string
+	Parse             parse.Options
+}
+
+// Messages are the loaded messages and the files they were read from
+type Messages struct {
+	Bag   *types.MessageBag
+	Files []parse.FileEntry
+	// Removed are the entries that were removed because they have no message in the default language
+	Removed []types.MessageEntry
+}
+
+// Load parses and validates all the message files, removing the entries without the default language. The problems
+// found in the messages are added to wc and fail the load with ErrInvalidMessages. Each step is logged at info level
+func Load(ctx context.Context, log *slog.Logger, opts Options, wc *util.WarningsCollector) (*Messages, error) {
+	log.Info("Collecting files")
+	walker, err := parse.IoDirWalker(opts.MessagesDirectory, opts.DefaultLanguage, opts.LanguageAliases)
+	if err != nil {
+		return nil, ErrCollectFiles.WithArgs(err)
+	}
+
+	argProvider := types.NewArgumentProvider()
+	if err := argProvider.RegisterAll(opts.ArgumentTypes); err != nil {
+		return nil, ErrRegisterArgumentTypes.WithArgs(err)
+	}
+
+	log.Info("Parsing files")
+	bag, err := parse.ParseJson(walker, wc, argProvider, opts.Parse)
+	if err != nil {
+		return nil, ErrParseFiles.WithArgs(err)
+	}
+	if err := check(ctx, wc); err != nil {
+		return nil, err
+	}
+
+	if !bag.Languages().Contains(opts.DefaultLanguage) {
+		return nil, ErrNoDefaultLanguage.WithArgs(opts.DefaultLanguage)
+	}
+	messages := &Messages{
+		Bag:     bag,
+		Files:   walker.Files(),
+		Removed: bag.RemoveEntriesWithoutLang(opts.DefaultLanguage),
+	}
+
+	validate.Untranslated(bag, opts.DefaultLanguage, wc)
+	validate.Plurals(bag, opts.DefaultLanguage, wc)
+	if err := check(ctx, wc); err != nil {
+		return nil, err
+	}
+
+	log.Info("Resolving references")
+	parse.ResolveReferences(bag, wc)
+	if err := check(ctx, wc); err != nil {
+		return nil, err
+	}
+
+	log.Info("Validating conditions")
+	validate.Conditions(bag, wc)
+	if err := check(ctx, wc); err != nil {
+		return nil, err
+	}
+
+	log.Info("Validating formats")
+	validate.Formats(bag, wc)
+	if err := check(ctx, wc); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// check stops the load if the context is done or if any problem was found in the messages
+func check(ctx context.Context, wc *util.WarningsCollector) error {
+	if !wc.IsEmpty() {
+		return ErrInvalidMessages.WithArgs(len(wc.Warnings()))
+	}
+	return ctx.Err()
+}
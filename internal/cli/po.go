@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/exchange"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+type PoArgs struct {
+	MessagesArgs
+	PoDirectory string
+}
+
+// ExportPo writes a <lang>.po file for each language and a messages.pot template into the po directory
+func ExportPo(args PoArgs) {
+	log := newLogger(args.LogLevel)
+	messages, _ := args.load(log)
+
+	if err := os.MkdirAll(args.PoDirectory, 0755); err != nil {
+		log.Error("Could not create the po directory", "err", err)
+		os.Exit(1)
+	}
+	write := func(name, lang string, entries []exchange.PoEntry) {
+		file, err := os.Create(filepath.Join(args.PoDirectory, name))
+		if err != nil {
+			log.Error("Could not create po file", "file", name, "err", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		if err := exchange.WritePo(file, lang, entries); err != nil {
+			log.Error("Could not write po file", "file", name, "err", err)
+			os.Exit(1)
+		}
+	}
+
+	template := exchange.PoEntries(messages, "", args.DefaultLanguage)
+	write("messages.pot", "", template)
+	for _, lang := range messages.Languages().Get() {
+		log.Info("Exporting language", "lang", lang)
+		write(lang+".po", lang, exchange.PoEntries(messages, lang, args.DefaultLanguage))
+	}
+}
+
+// ImportPo merges the translations of the .po files of the po directory into the json files of each language
+func ImportPo(args PoArgs) {
+	log := newLogger(args.LogLevel)
+	messages, files := args.load(log)
+
+	poFiles, err := filepath.Glob(filepath.Join(args.PoDirectory, "*.po"))
+	if err != nil {
+		log.Error("Could not list the po files", "err", err)
+		os.Exit(1)
+	}
+
+	wc := util.NewWarningsCollector()
+	importer := exchange.NewImporter(messages, files, args.DefaultLanguage, wc)
+	for _, poFile := range poFiles {
+		file, err := os.Open(poFile)
+		if err != nil {
+			log.Error("Could not open po file", "file", poFile, "err", err)
+			os.Exit(1)
+		}
+		entries, header, err := exchange.ReadPo(file)
+		file.Close()
+		if err != nil {
+			log.Error("Could not read po file", "file", poFile, "err", err)
+			os.Exit(1)
+		}
+
+		lang := header["Language"]
+		if lang == "" {
+			lang = strings.TrimSuffix(filepath.Base(poFile), filepath.Ext(poFile))
+		}
+		if lang == args.DefaultLanguage {
+			log.Info("Skipping the po file of the default language", "file", poFile)
+			continue
+		}
+		log.Info("Importing language", "lang", lang, "file", poFile)
+
+		var fuzzy, missing []string
+		for _, entry := range entries {
+			switch {
+			case entry.IdPlural != "":
+				wc.AddWarning(exchange.ErrPoPluralEntry.WithArgs(entry.Context))
+			case entry.IsFuzzy():
+				fuzzy = append(fuzzy, entry.Context)
+			case entry.Str == "":
+				missing = append(missing, entry.Context)
+			default:
+				if dropped, invented := importer.CompareArguments(lang, entry.Context, entry.Str); len(dropped) > 0 || len(invented) > 0 {
+					log.Warn("Refusing translation that does not use the arguments of the message", "lang", lang, "entry", entry.Context,
+						"dropped-arguments", dropped, "unknown-arguments", invented)
+					continue
+				}
+				importer.Set(lang, entry.Context, entry.Str)
+			}
+		}
+		if len(fuzzy) > 0 {
+			log.Warn("Skipped fuzzy translations", "lang", lang, "entries", fuzzy)
+		}
+		if len(missing) > 0 {
+			log.Warn("Missing translations", "lang", lang, "entries", missing)
+		}
+	}
+	for _, warning := range wc.Warnings() {
+		log.Warn(warning.Error())
+	}
+
+	if err := importer.Save(); err != nil {
+		log.Error("Could not save the imported translations", "err", err)
+		os.Exit(1)
+	}
+}
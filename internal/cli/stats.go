@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// Stats prints a table with the amount of translated messages of each language, the ones it is missing and how much
+// of the default language it translates. Untranslated messages and messages with untranslated branches are missing.
+// The logs are written to stderr so stdout only has the table
+func Stats(args MessagesArgs) {
+	log := newLoggerTo(os.Stderr, args.LogLevel)
+	messages, _ := args.load(log)
+	instances := messages.Instances()
+
+	langs := messages.Languages().Get()
+	slices.SortFunc(langs, func(a, b string) int {
+		switch {
+		case a == b:
+			return 0
+		case a == args.DefaultLanguage:
+			return -1
+		case b == args.DefaultLanguage:
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LANGUAGE\tMESSAGES\tMISSING\tTRANSLATED")
+	for _, lang := range langs {
+		count := 0
+		for _, instance := range instances {
+			if instance.Languages().Contains(lang) && !instance.Untranslated(lang, args.DefaultLanguage) &&
+				len(instance.MissingBranches(lang, args.DefaultLanguage)) == 0 {
+				count++
+			}
+		}
+		translated := "-" // there is nothing to translate
+		if len(instances) > 0 {
+			translated = fmt.Sprintf("%.1f%%", 100*float64(count)/float64(len(instances)))
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", lang, count, len(instances)-count, translated)
+	}
+	w.Flush()
+}
@@ -2,14 +2,20 @@ package types
 
 import (
 	"errors"
+	"slices"
+	"strings"
 
 	"github.com/MrNemo64/go-n-i18n/internal/cli/assert"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/cldr"
 	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
 )
 
 var (
-	ErrArgumentCollition util.Error = util.MakeError("argument %s has a type colition: %s != %s")
-	ErrMergeArgumentList            = util.MakeError("could not merge argument lists: %w")
+	ErrArgumentCollition           util.Error = util.MakeError("argument %s has a type colition: %s != %s")
+	ErrMergeArgumentList                      = util.MakeError("could not merge argument lists: %w")
+	ErrArgumentFormatCollition                = util.MakeError("argument %s has a default format colition: %s != %s")
+	ErrArgumentDeclarationConflict            = util.MakeError("the declared arguments [%s] do not match the already declared arguments [%s]")
+	ErrArgumentRedeclaration                  = util.MakeError("argument %s is declared more than once")
 )
 
 type ArgumentType struct {
@@ -18,6 +24,15 @@ type ArgumentType struct {
 	Type          string
 	DefaultFormat string
 	IsUnknown     bool
+	// Import is the path of the package of Type, empty if Type does not need an import, and Package the name Type
+	// qualifies it with
+	Import  string
+	Package string
+	// Localized types are rendered by the generated code with the CLDR data of each language instead of with a fmt
+	// verb, their format selects how
+	Localized bool
+	// CheckFormat reports why a format can not be used with the type, nil if any format can
+	CheckFormat func(format string) error
 }
 
 func (t *ArgumentType) Is(name string) bool {
@@ -32,6 +47,21 @@ func (t *ArgumentType) Is(name string) bool {
 	return false
 }
 
+var numericTypes = []string{
+	"int", "int8", "int16", "int32", "int64",
+	"uint", "uint8", "uint16", "uint32", "uint64",
+	"float32", "float64",
+}
+
+func (t *ArgumentType) IsNumeric() bool {
+	for _, numeric := range numericTypes {
+		if t.Type == numeric {
+			return true
+		}
+	}
+	return false
+}
+
 type ArgumentProvider struct {
 	types []*ArgumentType
 }
@@ -69,6 +99,75 @@ func NewArgumentProvider() *ArgumentProvider {
 		Type:          "float64",
 		DefaultFormat: "g",
 	})
+	p.Register(&ArgumentType{
+		Name:      "number",
+		Aliases:   []string{"number", "num"},
+		Type:      "float64",
+		Localized: true,
+		CheckFormat: func(format string) error {
+			_, _, err := cldr.ParseNumberFormat(format)
+			return err
+		},
+	})
+	p.Register(&ArgumentType{
+		Name:      "currency",
+		Aliases:   []string{"currency"},
+		Type:      "float64",
+		Localized: true,
+		CheckFormat: func(format string) error {
+			_, _, err := cldr.ParseCurrencyFormat(format)
+			return err
+		},
+	})
+	p.Register(&ArgumentType{
+		Name:      "percent",
+		Aliases:   []string{"percent"},
+		Type:      "float64",
+		Localized: true,
+		CheckFormat: func(format string) error {
+			_, err := cldr.ParsePercentFormat(format)
+			return err
+		},
+	})
+	for _, name := range []string{"date", "time", "datetime"} {
+		p.Register(&ArgumentType{
+			Name:        name,
+			Aliases:     []string{name},
+			Type:        "time.Time",
+			Import:      "time",
+			Package:     "time",
+			Localized:   true,
+			CheckFormat: cldr.CheckDateFormat,
+		})
+	}
+	p.Register(&ArgumentType{
+		Name:        "duration",
+		Aliases:     []string{"duration"},
+		Type:        "time.Duration",
+		Import:      "time",
+		Package:     "time",
+		Localized:   true,
+		CheckFormat: cldr.CheckDurationFormat,
+	})
+	p.Register(&ArgumentType{
+		Name:        "reltime",
+		Aliases:     []string{"reltime"},
+		Type:        "time.Time",
+		Import:      "time",
+		Package:     "time",
+		Localized:   true,
+		CheckFormat: cldr.CheckDurationFormat,
+	})
+	p.Register(&ArgumentType{
+		Name:      "list",
+		Aliases:   []string{"list"},
+		Type:      "[]string",
+		Localized: true,
+		CheckFormat: func(format string) error {
+			_, err := cldr.ParseListFormat(format)
+			return err
+		},
+	})
 	return p
 }
 
@@ -108,10 +207,21 @@ func (p *ArgumentProvider) FindArgumentOrUnknwonType(name string) *ArgumentType
 type MessageArgument struct {
 	Name string
 	Type *ArgumentType
+	// Format is the format declared in `_args`, used instead of Type.DefaultFormat if not empty
+	Format string
+}
+
+func (a *MessageArgument) DefaultFormat() string {
+	if a.Format != "" {
+		return a.Format
+	}
+	return a.Type.DefaultFormat
 }
 
 type ArgumentList struct {
 	Args []*MessageArgument
+	// Declaration holds the names of the arguments declared with `_args` in the declared order, nil if not declared
+	Declaration []string
 }
 
 func NewArgumentList() *ArgumentList {
@@ -125,6 +235,14 @@ func (l *ArgumentList) Merge(other *ArgumentList) error {
 			errs = append(errs, err)
 		}
 	}
+	if other.Declaration != nil {
+		if l.Declaration == nil {
+			l.Declaration = other.Declaration
+			l.sortDeclaredFirst()
+		} else if !slices.Equal(l.Declaration, other.Declaration) {
+			errs = append(errs, ErrArgumentDeclarationConflict.WithArgs(strings.Join(other.Declaration, ", "), strings.Join(l.Declaration, ", ")))
+		}
+	}
 	if len(errs) == 0 {
 		return nil
 	}
@@ -139,6 +257,13 @@ func (l *ArgumentList) AddArgument(arg *MessageArgument) (*MessageArgument, erro
 		return arg, nil
 	}
 
+	if arg.Format != "" {
+		if existing.Format != "" && existing.Format != arg.Format {
+			return nil, ErrArgumentFormatCollition.WithArgs(arg.Name, arg.Format, existing.Format)
+		}
+		existing.Format = arg.Format
+	}
+
 	if arg.Type.IsUnknown {
 		return existing, nil
 	}
@@ -152,6 +277,39 @@ func (l *ArgumentList) AddArgument(arg *MessageArgument) (*MessageArgument, erro
 	return existing, nil
 }
 
+// Declare adds the arguments declared with `_args` and places them first, in the declared order
+func (l *ArgumentList) Declare(args []*MessageArgument) error {
+	var errs []error
+	declaration := make([]string, 0, len(args))
+	for _, arg := range args {
+		if slices.Contains(declaration, arg.Name) {
+			errs = append(errs, ErrArgumentRedeclaration.WithArgs(arg.Name))
+			continue
+		}
+		declaration = append(declaration, arg.Name)
+		if _, err := l.AddArgument(arg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if l.Declaration != nil && !slices.Equal(l.Declaration, declaration) {
+		errs = append(errs, ErrArgumentDeclarationConflict.WithArgs(strings.Join(declaration, ", "), strings.Join(l.Declaration, ", ")))
+	} else {
+		l.Declaration = declaration
+		l.sortDeclaredFirst()
+	}
+	return errors.Join(errs...)
+}
+
+func (l *ArgumentList) sortDeclaredFirst() {
+	position := func(arg *MessageArgument) int {
+		if idx := slices.Index(l.Declaration, arg.Name); idx >= 0 {
+			return idx
+		}
+		return len(l.Declaration)
+	}
+	slices.SortStableFunc(l.Args, func(a, b *MessageArgument) int { return position(a) - position(b) })
+}
+
 func (l *ArgumentList) GetArgument(name string) (*MessageArgument, bool) {
 	for i := range l.Args {
 		if l.Args[i].Name == name {
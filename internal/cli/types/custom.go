@@ -0,0 +1,123 @@
+package types
+
+import (
+	"fmt"
+	"go/token"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+var (
+	ErrInvalidArgumentTypeSpec  util.Error = util.MakeError("invalid argument type '%s', expected name[,alias...]=type[:verb] like money=github.com/acme/money.Amount:v")
+	ErrArgumentTypeRedefinition            = util.MakeError("the argument type %s can not be registered since its name or one of its aliases is already used by another type")
+)
+
+var (
+	// argumentTypeSpec matches name[,alias...]=type[:verb], the type being prefixed by any amount of * and [] and
+	// followed by its identifier after the import path of its package, if it is not predeclared
+	argumentTypeSpec = regexp.MustCompile(`^([\w-]+)((?:,[\w-]+)*)=((?:\*|\[\])*)(?:([\w.~/-]+)\.)?([A-Za-z_]\w*)(?::([\w.+# -]+))?$`)
+	// majorVersion matches the last element of import paths like github.com/acme/money/v2 and gopkg.in/money.v2
+	majorVersion = regexp.MustCompile(`^v\d+$|\.v\d+$`)
+	// generatedPackages are the packages of the standard library that the generated code may import, by name, which
+	// the packages of the custom types can not be imported as
+	generatedPackages = map[string]string{
+		"bytes":   "bytes",
+		"fmt":     "fmt",
+		"math":    "math",
+		"slices":  "slices",
+		"strconv": "strconv",
+		"strings": "strings",
+		"time":    "time",
+		"unicode": "unicode",
+		"utf8":    "unicode/utf8",
+	}
+)
+
+// ParseArgumentType parses a custom argument type written as name[,alias...]=type[:verb]. The type is written with
+// the import path of its package, like github.com/acme/money.Amount, and is used in the generated code qualified with
+// the name given by PackageName. The default verb is v
+func ParseArgumentType(spec string) (*ArgumentType, error) {
+	groups := argumentTypeSpec.FindStringSubmatch(spec)
+	if groups == nil {
+		return nil, ErrInvalidArgumentTypeSpec.WithArgs(spec)
+	}
+	name, aliases, prefix, importPath, identifier, verb := groups[1], groups[2], groups[3], groups[4], groups[5], groups[6]
+	argType := &ArgumentType{
+		Name:          name,
+		Aliases:       []string{name},
+		Type:          prefix + identifier,
+		DefaultFormat: verb,
+		Import:        importPath,
+	}
+	if aliases != "" {
+		argType.Aliases = append(argType.Aliases, strings.Split(aliases[1:], ",")...)
+	}
+	if argType.DefaultFormat == "" {
+		argType.DefaultFormat = "v"
+	}
+	if importPath != "" {
+		argType.Package = PackageName(importPath)
+		argType.Type = prefix + argType.Package + "." + identifier
+	}
+	return argType, nil
+}
+
+// PackageName returns the name the generated code imports the package with the import path as: its last element
+// without the major version, without a go- prefix and up to its first character that can not be in an identifier,
+// like goimports does. The names that are not identifiers are prefixed by pkg
+func PackageName(importPath string) string {
+	elements := strings.Split(importPath, "/")
+	name := elements[len(elements)-1]
+	if len(elements) > 1 && majorVersion.MatchString(name) && !strings.Contains(name, ".") {
+		name = elements[len(elements)-2]
+	}
+	name = strings.TrimPrefix(majorVersion.ReplaceAllString(name, ""), "go-")
+	if i := strings.IndexFunc(name, func(r rune) bool { return r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) }); i >= 0 {
+		name = name[:i]
+	}
+	if !token.IsIdentifier(name) {
+		name = "pkg" + name
+	}
+	return name
+}
+
+// packageAlias returns the name of PackageName for the package with the import path, followed by a number if the
+// generated code or another registered type already imports a different package with it
+func (p *ArgumentProvider) packageAlias(importPath string) string {
+	name := PackageName(importPath)
+	alias := name
+	for i := 2; ; i++ {
+		path, used := generatedPackages[alias]
+		for _, registered := range p.types {
+			if !used && registered.Package == alias {
+				path, used = registered.Import, true
+			}
+		}
+		if !used || path == importPath {
+			return alias
+		}
+		alias = fmt.Sprintf("%s%d", name, i)
+	}
+}
+
+// RegisterAll parses and registers the custom argument types
+func (p *ArgumentProvider) RegisterAll(specs []string) error {
+	for _, spec := range specs {
+		argType, err := ParseArgumentType(spec)
+		if err != nil {
+			return err
+		}
+		if argType.Import != "" {
+			alias := p.packageAlias(argType.Import)
+			argType.Type = strings.Replace(argType.Type, argType.Package+".", alias+".", 1)
+			argType.Package = alias
+		}
+		if !p.Register(argType) {
+			return ErrArgumentTypeRedefinition.WithArgs(argType.Name)
+		}
+	}
+	return nil
+}
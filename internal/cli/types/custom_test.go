@@ -0,0 +1,64 @@
+package types
+
+import "testing"
+
+func TestPackageName(t *testing.T) {
+	tests := []struct {
+		importPath string
+		want       string
+	}{
+		{importPath: "time", want: "time"},
+		{importPath: "github.com/acme/money", want: "money"},
+		{importPath: "github.com/acme/money/v2", want: "money"},
+		{importPath: "gopkg.in/money.v2", want: "money"},
+		{importPath: "github.com/Rhymond/go-money", want: "money"},
+		{importPath: "github.com/acme/money-utils", want: "money"},
+		{importPath: "github.com/acme/3d", want: "pkg3d"},
+		{importPath: "github.com/acme/type", want: "pkgtype"},
+	}
+	for _, test := range tests {
+		t.Run(test.importPath, func(t *testing.T) {
+			if name := PackageName(test.importPath); name != test.want {
+				t.Errorf("expected %s, got %s", test.want, name)
+			}
+		})
+	}
+}
+
+func TestRegisterAllPackages(t *testing.T) {
+	p := NewArgumentProvider()
+	err := p.RegisterAll([]string{
+		"money=github.com/Rhymond/go-money.Money",
+		"amount=github.com/acme/money.Amount",
+		"cents=*github.com/acme/money.Cents",
+		"other=[]github.com/other/money/v2.Amount",
+		"stamp=github.com/acme/time.Stamp",
+		"elapsed=time.Duration",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		typeName string
+		pkg      string
+	}{
+		{name: "money", typeName: "money.Money", pkg: "money"},
+		{name: "amount", typeName: "money2.Amount", pkg: "money2"},
+		{name: "cents", typeName: "*money2.Cents", pkg: "money2"},
+		{name: "other", typeName: "[]money3.Amount", pkg: "money3"},
+		{name: "stamp", typeName: "time2.Stamp", pkg: "time2"},
+		{name: "elapsed", typeName: "time.Duration", pkg: "time"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			argType, found := p.FindArgument(test.name)
+			if !found {
+				t.Fatal("the type is not registered")
+			}
+			if argType.Type != test.typeName || argType.Package != test.pkg {
+				t.Errorf("expected the type %s of the package %s, got %s of %s", test.typeName, test.pkg, argType.Type, argType.Package)
+			}
+		})
+	}
+}
@@ -155,6 +155,15 @@ func (m *MessageBag) MustHaveAllLangs(langs []string, defLang string) map[string
 	return ret
 }
 
+// CompleteBranches completes the branches of the messages of every instance, see MessageInstance.CompleteBranches
+func (m *MessageBag) CompleteBranches(defLang string) map[string][]string {
+	ret := make(map[string][]string)
+	for _, instance := range m.Instances() {
+		util.MergeIntoA(ret, instance.CompleteBranches(defLang), func(v1, v2 *[]string) []string { return append(*v1, *v2...) })
+	}
+	return ret
+}
+
 func (m *MessageBag) Languages() *util.Set[string] {
 	set := util.NewSet[string]()
 	for _, child := range m.children {
@@ -162,3 +171,18 @@ func (m *MessageBag) Languages() *util.Set[string] {
 	}
 	return set
 }
+
+func (m *MessageBag) Instances() []*MessageInstance {
+	var instances []*MessageInstance
+	for _, child := range m.children {
+		switch child.Type() {
+		case MessageEntryBag:
+			instances = append(instances, child.AsBag().Instances()...)
+		case MessageEntryInstance:
+			instances = append(instances, child.AsInstance())
+		default:
+			panic(fmt.Errorf("unknown message entry type %d", child.Type()))
+		}
+	}
+	return instances
+}
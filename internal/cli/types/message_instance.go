@@ -3,6 +3,7 @@ package types
 import (
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/MrNemo64/go-n-i18n/internal/cli/assert"
 	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
@@ -16,7 +17,10 @@ var (
 type MessageInstance struct {
 	messageEntry
 	message map[string]MessageValue
-	args    *ArgumentList
+	sources map[string]string
+	// descriptions document the message, written as the doc comment of its method
+	descriptions map[string]string
+	args         *ArgumentList
 }
 
 func NewMessageInstance(key string) (*MessageInstance, error) {
@@ -27,8 +31,10 @@ func NewMessageInstance(key string) (*MessageInstance, error) {
 		messageEntry: messageEntry{
 			key: key,
 		},
-		message: make(map[string]MessageValue),
-		args:    NewArgumentList(),
+		message:      make(map[string]MessageValue),
+		sources:      make(map[string]string),
+		descriptions: make(map[string]string),
+		args:         NewArgumentList(),
 	}, nil
 }
 
@@ -50,6 +56,18 @@ func (m *MessageInstance) MessageMust(lang string) MessageValue {
 	return v
 }
 
+// Source returns the file where the message of the language was defined, empty if unknown
+func (m *MessageInstance) Source(lang string) string { return m.sources[lang] }
+func (m *MessageInstance) SetSource(lang, source string) {
+	m.sources[lang] = source
+}
+
+// Description returns the description given to the message in the language, empty if none
+func (m *MessageInstance) Description(lang string) string { return m.descriptions[lang] }
+func (m *MessageInstance) SetDescription(lang, description string) {
+	m.descriptions[lang] = description
+}
+
 func (m *MessageInstance) AddArgs(args *ArgumentList) error {
 	return m.args.Merge(args)
 }
@@ -71,6 +89,13 @@ func (m *MessageInstance) Merge(other *MessageInstance) error {
 	for lang, value := range other.message {
 		if err := m.AddLanguage(lang, value); err != nil {
 			errs = append(errs, err)
+		} else {
+			if source, found := other.sources[lang]; found {
+				m.sources[lang] = source
+			}
+			if description, found := other.descriptions[lang]; found {
+				m.descriptions[lang] = description
+			}
 		}
 	}
 	if len(errs) == 0 {
@@ -95,10 +120,150 @@ func (m *MessageInstance) MustHaveAllLangs(langs []string, defLang string) map[s
 	missing := make(map[string][]string)
 	path := m.PathAsStr()
 	for _, lang := range langs {
-		if _, hasIt := m.message[lang]; !hasIt {
+		if _, hasIt := m.message[lang]; !hasIt || m.Untranslated(lang, defLang) {
 			m.message[lang] = defMsg
+			m.sources[lang] = m.sources[defLang]
 			missing[lang] = []string{path}
 		}
 	}
 	return missing
 }
+
+// Untranslated tells if the message of lang is not translated yet, written as null. Untranslated messages and
+// branches of the languages other than the default one use the ones of the default language
+func (m *MessageInstance) Untranslated(lang, defLang string) bool {
+	value, found := m.message[lang]
+	return found && lang != defLang && IsUntranslated(value)
+}
+
+// IsUntranslated tells if a message or branch is not translated yet. Empty texts are translations like any other
+func IsUntranslated(value any) bool {
+	str, ok := value.(*ValueString)
+	return ok && str.untranslated
+}
+
+// withoutUntranslated returns the conditional or plural value without its untranslated branches and the selectors
+// of the removed branches
+func withoutUntranslated(value MessageValue) (MessageValue, []string) {
+	var removed []string
+	switch v := value.(type) {
+	case *ValueConditional:
+		translated := &ValueConditional{Else: v.Else}
+		for _, condition := range v.Conditions {
+			if IsUntranslated(condition.Value) {
+				removed = append(removed, condition.Condition)
+			} else {
+				translated.Conditions = append(translated.Conditions, condition)
+			}
+		}
+		if v.Else != nil && IsUntranslated(v.Else) {
+			translated.Else = nil
+			removed = append(removed, "")
+		}
+		return translated, removed
+	case *ValuePlural:
+		translated := *v
+		translated.Exact, translated.Forms = nil, nil
+		for _, exact := range v.Exact {
+			if IsUntranslated(exact.Value) {
+				removed = append(removed, "#="+exact.Number)
+			} else {
+				translated.Exact = append(translated.Exact, exact)
+			}
+		}
+		for _, form := range v.Forms {
+			if IsUntranslated(form.Value) {
+				removed = append(removed, "#"+form.Category.String())
+			} else {
+				translated.Forms = append(translated.Forms, form)
+			}
+		}
+		return &translated, removed
+	}
+	return value, nil
+}
+
+// MissingBranches returns the branches of the conditional or plural message of lang that are untranslated and, if
+// it is a plural, its other form if it does not have one, written as the path followed by [condition], [] for the else
+// branch, [#category] or [#=number]. The plural categories depend on the language and exact forms are optional, so
+// only the other form can be missing
+func (m *MessageInstance) MissingBranches(lang, defLang string) []string {
+	_, selectors := m.missingSelectors(lang, defLang)
+	return util.Map(selectors, func(_ int, selector *string) string { return m.PathAsStr() + "[" + *selector + "]" })
+}
+
+// missingSelectors returns the message of lang without its untranslated branches and the selectors of the branches
+// it is missing, see MissingBranches
+func (m *MessageInstance) missingSelectors(lang, defLang string) (MessageValue, []string) {
+	value, found := m.message[lang]
+	if !found || lang == defLang {
+		return value, nil
+	}
+	value, missing := withoutUntranslated(value)
+	if plural, ok := value.(*ValuePlural); ok && plural.Other() == nil {
+		missing = append(missing, "#"+PluralOther.String())
+	}
+	return value, missing
+}
+
+// CompleteBranches replaces the untranslated branches of the conditional messages of each language with the same
+// branch of the message of the default language, removes the untranslated forms of the plural messages and gives
+// them the other form of the default language if they have none. Returns the missing branches of each language, see
+// MissingBranches
+func (m *MessageInstance) CompleteBranches(defLang string) map[string][]string {
+	added := make(map[string][]string)
+	def := m.message[defLang]
+	for lang, original := range m.message {
+		value, missing := m.missingSelectors(lang, defLang)
+		if len(missing) == 0 {
+			continue
+		}
+		added[lang] = m.MissingBranches(lang, defLang)
+		switch v := value.(type) {
+		case *ValueConditional:
+			completed := &ValueConditional{}
+			untranslated := original.(*ValueConditional)
+			for _, condition := range untranslated.Conditions {
+				if !IsUntranslated(condition.Value) {
+					completed.Conditions = append(completed.Conditions, condition)
+				} else if branch, found := defaultBranch(def, condition.Condition); found {
+					completed.Conditions = append(completed.Conditions, Condition{Condition: condition.Condition, Value: branch})
+				}
+			}
+			completed.Else = v.Else
+			if untranslated.Else != nil && v.Else == nil {
+				completed.Else, _ = defaultBranch(def, "")
+			}
+			m.message[lang] = completed
+		case *ValuePlural:
+			completed := *v
+			completed.Forms = slices.Clone(v.Forms)
+			if defPlural, ok := def.(*ValuePlural); ok && v.Other() == nil {
+				completed.Forms = append(completed.Forms, PluralForm{Category: PluralOther, Value: defPlural.Other()})
+			} else if other, ok := def.(Pluralizable); ok && v.Other() == nil {
+				completed.Forms = append(completed.Forms, PluralForm{Category: PluralOther, Value: other})
+			}
+			m.message[lang] = &completed
+		}
+	}
+	return added
+}
+
+// defaultBranch returns the branch of the condition, empty for the else branch, of the default message def. If it is
+// not a conditional the whole message is the branch
+func defaultBranch(def MessageValue, condition string) (Conditionable, bool) {
+	conditional, ok := def.(*ValueConditional)
+	if !ok {
+		branch, ok := def.(Conditionable)
+		return branch, ok
+	}
+	if condition == "" {
+		return conditional.Else, conditional.Else != nil
+	}
+	for _, c := range conditional.Conditions {
+		if c.Condition == condition {
+			return c.Value, true
+		}
+	}
+	return nil, false
+}
@@ -5,4 +5,35 @@ type MessageValue interface {
 	AsValueParametrized() *ValueParametrized
 	AsMultiline() *ValueMultiline
 	AsConditional() *ValueConditional
+	AsPlural() *ValuePlural
+}
+
+// VisitValues calls visit with value and with every value nested inside of it
+func VisitValues(value MessageValue, visit func(MessageValue)) {
+	visit(value)
+	visitNested := func(nested any) {
+		if v, ok := nested.(MessageValue); ok {
+			VisitValues(v, visit)
+		}
+	}
+	switch v := value.(type) {
+	case *ValueMultiline:
+		for _, line := range v.Lines {
+			visitNested(line)
+		}
+	case *ValueConditional:
+		for _, condition := range v.Conditions {
+			visitNested(condition.Value)
+		}
+		if v.Else != nil {
+			visitNested(v.Else)
+		}
+	case *ValuePlural:
+		for _, exact := range v.Exact {
+			visitNested(exact.Value)
+		}
+		for _, form := range v.Forms {
+			visitNested(form.Value)
+		}
+	}
 }
@@ -21,6 +21,9 @@ func NewConditionalValue(conditions []Condition, elseCondition Conditionable) (*
 	}, nil
 }
 
+// conditionals can be nested inside other conditionals and plurals, the ICU grammar produces them
+func (*ValueConditional) conditionableMarker()               {}
+func (*ValueConditional) pluralizableMarker()                {}
 func (c *ValueConditional) AsConditional() *ValueConditional { return c }
 func (*ValueConditional) AsValueString() *ValueString {
 	panic("called AsValueString on a ValueConditional")
@@ -31,3 +34,6 @@ func (*ValueConditional) AsValueParametrized() *ValueParametrized {
 func (*ValueConditional) AsMultiline() *ValueMultiline {
 	panic("called AsMultiline on a ValueConditional")
 }
+func (*ValueConditional) AsPlural() *ValuePlural {
+	panic("called AsPlural on a ValueConditional")
+}
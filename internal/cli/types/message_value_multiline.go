@@ -22,6 +22,7 @@ func NewMultilineValue(lines []Multilineable) (*ValueMultiline, error) {
 }
 
 func (*ValueMultiline) conditionableMarker()           {}
+func (*ValueMultiline) pluralizableMarker()            {}
 func (s *ValueMultiline) AsMultiline() *ValueMultiline { return s }
 func (*ValueMultiline) AsValueString() *ValueString {
 	panic("called AsValueString on a ValueMultiline")
@@ -32,3 +33,6 @@ func (*ValueMultiline) AsValueParametrized() *ValueParametrized {
 func (*ValueMultiline) AsConditional() *ValueConditional {
 	panic("called AsConditional on a ValueMultiline")
 }
+func (*ValueMultiline) AsPlural() *ValuePlural {
+	panic("called AsPlural on a ValueMultiline")
+}
@@ -13,11 +13,25 @@ type ValueParametrized struct {
 	Args         []*UsedArgument
 }
 
+// UsedArgument is an argument or a reference to another message used in a parametrized value.
+// Argument is nil for references
 type UsedArgument struct {
-	Argument *MessageArgument
-	Format   string
+	Argument  *MessageArgument
+	Format    string
+	Reference *MessageReference
 }
 
+func (u *UsedArgument) IsReference() bool { return u.Reference != nil }
+
+// MessageReference is a {@path} segment, replaced by the referenced message
+type MessageReference struct {
+	Path []string
+	// Target is the referenced message, set once the references are resolved
+	Target *MessageInstance
+}
+
+func (r *MessageReference) PathAsStr() string { return PathAsStr(r.Path) }
+
 func NewParametrizedStringValue(textSegments []*ValueString, args []*UsedArgument) (*ValueParametrized, error) {
 	if len(textSegments) != len(args)+1 {
 		return nil, ErrInvalidAmountOfTextSegmentsAndArguments.WithArgs(len(textSegments), len(args))
@@ -30,6 +44,7 @@ func NewParametrizedStringValue(textSegments []*ValueString, args []*UsedArgumen
 
 func (*ValueParametrized) multilineMarker()                          {}
 func (*ValueParametrized) conditionableMarker()                      {}
+func (*ValueParametrized) pluralizableMarker()                       {}
 func (s *ValueParametrized) AsValueParametrized() *ValueParametrized { return s }
 func (*ValueParametrized) AsValueString() *ValueString {
 	panic("called AsValueString on a ParametrizedString")
@@ -40,3 +55,6 @@ func (*ValueParametrized) AsMultiline() *ValueMultiline {
 func (*ValueParametrized) AsConditional() *ValueConditional {
 	panic("called AsConditional on a ValueParametrized")
 }
+func (*ValueParametrized) AsPlural() *ValuePlural {
+	panic("called AsPlural on a ValueParametrized")
+}
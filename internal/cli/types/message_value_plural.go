@@ -0,0 +1,108 @@
+package types
+
+import (
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+var (
+	ErrPluralFormRedefinion util.Error = util.MakeError("the plural form '%s' is defined more than once")
+)
+
+type PluralCategory int
+
+const (
+	PluralZero PluralCategory = iota
+	PluralOne
+	PluralTwo
+	PluralFew
+	PluralMany
+	PluralOther
+)
+
+var pluralCategoryNames = []string{"zero", "one", "two", "few", "many", "other"}
+
+func PluralCategories() []PluralCategory {
+	return []PluralCategory{PluralZero, PluralOne, PluralTwo, PluralFew, PluralMany, PluralOther}
+}
+
+func ParsePluralCategory(name string) (PluralCategory, bool) {
+	for i, n := range pluralCategoryNames {
+		if n == name {
+			return PluralCategory(i), true
+		}
+	}
+	return PluralOther, false
+}
+
+func (c PluralCategory) String() string { return pluralCategoryNames[c] }
+
+type Pluralizable interface {
+	pluralizableMarker()
+}
+
+type PluralForm struct {
+	Category PluralCategory
+	Value    Pluralizable
+}
+
+// PluralExact is a form used when the argument is exactly Number, checked before the CLDR categories
+type PluralExact struct {
+	Number string
+	Value  Pluralizable
+}
+
+type ValuePlural struct {
+	Argument *MessageArgument
+	// Ordinal plurals select the form with the ordinal rules (1st, 2nd, 3rd) instead of the cardinal ones
+	Ordinal bool
+	Exact   []PluralExact
+	Forms   []PluralForm
+}
+
+// NewPluralValue creates a plural with the forms. The other form may be missing in the translations, which use the
+// one of the default language, so it is checked once the default language is known
+func NewPluralValue(argument *MessageArgument, exact []PluralExact, forms []PluralForm) (*ValuePlural, error) {
+	seen := make(map[PluralCategory]bool)
+	for _, form := range forms {
+		if seen[form.Category] {
+			return nil, ErrPluralFormRedefinion.WithArgs(form.Category.String())
+		}
+		seen[form.Category] = true
+	}
+	return &ValuePlural{
+		Argument: argument,
+		Exact:    exact,
+		Forms:    forms,
+	}, nil
+}
+
+func (p *ValuePlural) Form(category PluralCategory) (Pluralizable, bool) {
+	for _, form := range p.Forms {
+		if form.Category == category {
+			return form.Value, true
+		}
+	}
+	return nil, false
+}
+
+func (p *ValuePlural) Other() Pluralizable {
+	other, _ := p.Form(PluralOther)
+	return other
+}
+
+// plurals can be nested inside other plurals and conditionals, the ICU grammar produces them
+func (*ValuePlural) conditionableMarker()     {}
+func (*ValuePlural) pluralizableMarker()      {}
+func (p *ValuePlural) AsPlural() *ValuePlural { return p }
+func (*ValuePlural) AsValueString() *ValueString {
+	panic("called AsValueString on a ValuePlural")
+}
+func (*ValuePlural) AsValueParametrized() *ValueParametrized {
+	panic("called AsValueParametrized on a ValuePlural")
+}
+func (*ValuePlural) AsMultiline() *ValueMultiline {
+	panic("called AsMultiline on a ValuePlural")
+}
+func (*ValuePlural) AsConditional() *ValueConditional {
+	panic("called AsConditional on a ValuePlural")
+}
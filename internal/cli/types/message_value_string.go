@@ -1,17 +1,28 @@
 package types
 
-import "strings"
+import (
+	"strconv"
+	"strings"
+)
 
 type ValueString struct {
 	message string
+	// untranslated texts are written as null and use the text of the default language
+	untranslated bool
 }
 
 func NewStringLiteralValue(message string) *ValueString {
 	return &ValueString{message: message}
 }
 
+// NewUntranslatedValue creates the empty text of a message or branch that is not translated yet
+func NewUntranslatedValue() *ValueString {
+	return &ValueString{untranslated: true}
+}
+
 func (*ValueString) multilineMarker()              {}
 func (*ValueString) conditionableMarker()          {}
+func (*ValueString) pluralizableMarker()           {}
 func (s *ValueString) AsValueString() *ValueString { return s }
 func (*ValueString) AsValueParametrized() *ValueParametrized {
 	panic("called AsValueParametrized on a ValueString")
@@ -22,6 +33,18 @@ func (*ValueString) AsMultiline() *ValueMultiline {
 func (*ValueString) AsConditional() *ValueConditional {
 	panic("called AsConditional on a ValueString")
 }
+func (*ValueString) AsPlural() *ValuePlural {
+	panic("called AsPlural on a ValueString")
+}
+func (s *ValueString) Message() string { return s.message }
+
+// Escaped returns the message escaped to be placed inside a go string literal delimited by quote
 func (s *ValueString) Escaped(quote string) string {
-	return strings.ReplaceAll(s.message, quote, "\\\"")
+	quoted := strconv.Quote(s.message)
+	escaped := quoted[1 : len(quoted)-1]
+	if quote != "\"" {
+		escaped = strings.ReplaceAll(escaped, "\\\"", "\"")
+		escaped = strings.ReplaceAll(escaped, quote, "\\"+quote)
+	}
+	return escaped
 }
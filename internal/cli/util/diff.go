@@ -0,0 +1,233 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the amount of unchanged lines shown around the changes of a unified diff
+const diffContext = 3
+
+// diffOp is a line of a diff: kept in both texts, removed from the old one or added by the new one
+type diffOp struct {
+	kind byte // ' ', '-' or '+'
+	line string
+	// old and new are the indexes of the line in each text. In the text without the line, the index of the next one
+	old, new int
+}
+
+var (
+	ErrDiffTooLarge Error = MakeError("the texts differ in more than %d lines")
+)
+
+// MaxDiffEdits is the amount of added and removed lines after which UnifiedDiff stops looking for the differences
+const MaxDiffEdits = 4000
+
+// UnifiedDiff returns the unified diff, with 3 lines of context, that turns the old text into the new one. The texts
+// are named in the header with oldName and newName. Returns an empty string if the texts are equal and
+// ErrDiffTooLarge if they differ in more than MaxDiffEdits lines
+func UnifiedDiff(oldName, newName, oldText, newText string) (string, error) {
+	if oldText == newText {
+		return "", nil
+	}
+	ops, ok := diffLines(splitLines(oldText), splitLines(newText), MaxDiffEdits)
+	if !ok {
+		return "", ErrDiffTooLarge.WithArgs(MaxDiffEdits)
+	}
+	sb := strings.Builder{}
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+	for start := 0; start < len(ops); {
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+		// the hunk ends when there are more than 2 * diffContext unchanged lines until the next change
+		end := start
+		for unchanged := 0; end < len(ops) && unchanged <= 2*diffContext; end++ {
+			if ops[end].kind == ' ' {
+				unchanged++
+			} else {
+				unchanged = 0
+			}
+		}
+		for end > start && ops[end-1].kind == ' ' {
+			end--
+		}
+		writeHunk(&sb, ops[max(0, start-diffContext):min(len(ops), end+diffContext)])
+		start = end
+	}
+	return sb.String(), nil
+}
+
+func writeHunk(sb *strings.Builder, ops []diffOp) {
+	oldCount, newCount := 0, 0
+	for _, op := range ops {
+		if op.kind != '+' {
+			oldCount++
+		}
+		if op.kind != '-' {
+			newCount++
+		}
+	}
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(ops[0].old, oldCount), hunkRange(ops[0].new, newCount))
+	for _, op := range ops {
+		sb.WriteByte(op.kind)
+		sb.WriteString(op.line)
+		if !strings.HasSuffix(op.line, "\n") {
+			sb.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunkRange returns the range of lines of a hunk, starting at 1 or, if it has no lines, the line before it
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
+
+// splitLines splits the text in lines that keep their \n
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines returns the shortest list of operations that turns a into b, or false if it needs more than maxEdits
+// added and removed lines. It uses the linear space version of the Myers algorithm, which splits the texts in the
+// middle of the shortest edit script and diffs each half
+func diffLines(a, b []string, maxEdits int) ([]diffOp, bool) {
+	d := &differ{a: a, b: b, maxEdits: maxEdits}
+	if !d.diff(0, len(a), 0, len(b)) {
+		return nil, false
+	}
+	return d.ops, true
+}
+
+type differ struct {
+	a, b     []string
+	maxEdits int
+	ops      []diffOp
+}
+
+// diff appends the operations that turn a[aLo:aHi] into b[bLo:bHi]
+func (d *differ) diff(aLo, aHi, bLo, bHi int) bool {
+	prefix := 0
+	for aLo+prefix < aHi && bLo+prefix < bHi && d.a[aLo+prefix] == d.b[bLo+prefix] {
+		prefix++
+	}
+	suffix := 0
+	for aHi-suffix > aLo+prefix && bHi-suffix > bLo+prefix && d.a[aHi-suffix-1] == d.b[bHi-suffix-1] {
+		suffix++
+	}
+	d.keep(aLo, bLo, prefix)
+	aLo, bLo, aHi, bHi = aLo+prefix, bLo+prefix, aHi-suffix, bHi-suffix
+
+	switch {
+	case aLo == aHi:
+		for y := bLo; y < bHi; y++ {
+			d.ops = append(d.ops, diffOp{kind: '+', line: d.b[y], old: aLo, new: y})
+		}
+	case bLo == bHi:
+		for x := aLo; x < aHi; x++ {
+			d.ops = append(d.ops, diffOp{kind: '-', line: d.a[x], old: x, new: bLo})
+		}
+	default:
+		x, y, ok := d.bisect(aLo, aHi, bLo, bHi)
+		if !ok || !d.diff(aLo, x, bLo, y) || !d.diff(x, aHi, y, bHi) {
+			return false
+		}
+	}
+	d.keep(aHi, bHi, suffix)
+	return true
+}
+
+func (d *differ) keep(x, y, n int) {
+	for i := 0; i < n; i++ {
+		d.ops = append(d.ops, diffOp{kind: ' ', line: d.a[x+i], old: x + i, new: y + i})
+	}
+}
+
+// bisect returns the point where the shortest edit script that turns a[aLo:aHi] into b[bLo:bHi] has done half of
+// its edits, found by following the edits from the start and from the end of the texts until they meet. The
+// texts can not be empty nor start or end with the same line
+func (d *differ) bisect(aLo, aHi, bLo, bHi int) (int, int, bool) {
+	n, m := aHi-aLo, bHi-bLo
+	maxD := (n + m + 1) / 2
+	offset := maxD + 1
+	// forward[k] is the furthest x reached from the start in the diagonal k, backward[k] the furthest from the end
+	forward, backward := make([]int, 2*offset+1), make([]int, 2*offset+1)
+	for i := range forward {
+		forward[i], backward[i] = -1, -1
+	}
+	forward[offset+1], backward[offset+1] = 0, 0
+	delta := n - m
+	// the paths meet in a forward step if delta is odd, else in a backward one
+	odd := delta%2 != 0
+	// the diagonals at the start and at the end of the range that already left the texts are skipped
+	forwardStart, forwardEnd, backwardStart, backwardEnd := 0, 0, 0, 0
+	for step := 0; step < maxD; step++ {
+		if 2*step > d.maxEdits {
+			return 0, 0, false
+		}
+		for k := -step + forwardStart; k <= step-forwardEnd; k += 2 {
+			var x int
+			if k == -step || (k != step && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && d.a[aLo+x] == d.b[bLo+y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+			switch {
+			case x > n:
+				forwardEnd += 2
+			case y > m:
+				forwardStart += 2
+			case odd:
+				if other := offset + delta - k; other >= 0 && other < len(backward) && backward[other] != -1 && x >= n-backward[other] {
+					return aLo + x, bLo + y, true
+				}
+			}
+		}
+		for k := -step + backwardStart; k <= step-backwardEnd; k += 2 {
+			var x int
+			if k == -step || (k != step && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && d.a[aHi-x-1] == d.b[bHi-y-1] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+			switch {
+			case x > n:
+				backwardEnd += 2
+			case y > m:
+				backwardStart += 2
+			case !odd:
+				if other := offset + delta - k; other >= 0 && other < len(forward) && forward[other] != -1 && forward[other] >= n-x {
+					return aLo + forward[other], bLo + forward[other] - (other - offset), true
+				}
+			}
+		}
+	}
+	// the texts have no line in common, all the lines of a are removed and all the ones of b added
+	return aHi, bLo, n+m <= d.maxEdits
+}
@@ -0,0 +1,146 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     string
+	}{
+		{
+			name: "equal",
+			old:  "a\nb\n",
+			new:  "a\nb\n",
+			want: "",
+		},
+		{
+			name: "changed line",
+			old:  "a\nb\nc\n",
+			new:  "a\nx\nc\n",
+			want: "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n",
+		},
+		{
+			name: "from empty",
+			old:  "",
+			new:  "a\nb\n",
+			want: "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name: "to empty",
+			old:  "a\n",
+			new:  "",
+			want: "--- old\n+++ new\n@@ -1 +0,0 @@\n-a\n",
+		},
+		{
+			name: "missing newline at end",
+			old:  "a\nb",
+			new:  "a\nb\n",
+			want: "--- old\n+++ new\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+		{
+			name: "separate hunks",
+			old:  "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			new:  "x\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ny\n",
+			want: "--- old\n+++ new\n@@ -1,4 +1,4 @@\n-1\n+x\n 2\n 3\n 4\n@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+y\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := UnifiedDiff("old", "new", test.old, test.new)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != test.want {
+				t.Errorf("got\n%s\nwant\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+// lcs returns the length of the longest common subsequence of a and b
+func lcs(a, b []string) int {
+	previous, current := make([]int, len(b)+1), make([]int, len(b)+1)
+	for i := range a {
+		for j := range b {
+			if a[i] == b[j] {
+				current[j+1] = previous[j] + 1
+			} else {
+				current[j+1] = max(previous[j+1], current[j])
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func TestDiffLinesIsShortest(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	randomLines := func() []string {
+		lines := make([]string, random.Intn(30))
+		for i := range lines {
+			lines[i] = fmt.Sprintf("%d\n", random.Intn(5))
+		}
+		return lines
+	}
+	for i := 0; i < 500; i++ {
+		a, b := randomLines(), randomLines()
+		ops, ok := diffLines(a, b, len(a)+len(b))
+		if !ok {
+			t.Fatalf("diff of %q and %q gave up", a, b)
+		}
+		var gotA, gotB []string
+		edits := 0
+		for _, op := range ops {
+			if op.kind != '+' {
+				if a[op.old] != op.line {
+					t.Fatalf("diff of %q and %q: line %d of the old text is not %q", a, b, op.old, op.line)
+				}
+				gotA = append(gotA, op.line)
+			}
+			if op.kind != '-' {
+				if b[op.new] != op.line {
+					t.Fatalf("diff of %q and %q: line %d of the new text is not %q", a, b, op.new, op.line)
+				}
+				gotB = append(gotB, op.line)
+			}
+			if op.kind != ' ' {
+				edits++
+			}
+		}
+		if strings.Join(gotA, "") != strings.Join(a, "") || strings.Join(gotB, "") != strings.Join(b, "") {
+			t.Fatalf("diff of %q and %q does not rebuild the texts", a, b)
+		}
+		if want := len(a) + len(b) - 2*lcs(a, b); edits != want {
+			t.Fatalf("diff of %q and %q has %d edits, the shortest has %d", a, b, edits, want)
+		}
+	}
+}
+
+func TestUnifiedDiffLargeTexts(t *testing.T) {
+	var old, new strings.Builder
+	for i := 0; i < 24000; i++ {
+		fmt.Fprintf(&old, "old %d\n", i)
+		fmt.Fprintf(&new, "new %d\n", i)
+	}
+
+	if _, err := UnifiedDiff("old", "new", old.String(), new.String()); !errors.Is(err, ErrDiffTooLarge) {
+		t.Errorf("expected ErrDiffTooLarge, got %v", err)
+	}
+
+	// few changes in a large text are found
+	changed := strings.Replace(old.String(), "old 12000\n", "changed\n", 1)
+	diff, err := UnifiedDiff("old", "new", old.String(), changed)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "@@ -11998,7 +11998,7 @@\n old 11997\n old 11998\n old 11999\n-old 12000\n+changed\n"; !strings.Contains(diff, want) {
+		t.Errorf("diff does not contain\n%s\ngot\n%s", want, diff)
+	}
+}
@@ -0,0 +1,128 @@
+package validate
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	gotypes "go/types"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/parse"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+var (
+	ErrInvalidCondition      util.Error = util.MakeError("invalid condition '%s' in the path %s in the lang %s (file %s): %w")
+	ErrConditionSyntax                  = util.MakeError("it is not a valid go expression: %w")
+	ErrUnknownIdentifier                = util.MakeError("'%s' is not an argument of the message")
+	ErrConditionTypeCheck               = util.MakeError("it does not type check with the arguments of the message: %w")
+	ErrConditionIsNotBoolean            = util.MakeError("it is of type %s but conditions must be booleans")
+	ErrNotBooleanOperator               = util.MakeError("the operator %s never gives a boolean but conditions must be booleans")
+	ErrBlankIdentifier                  = util.MakeError("the blank identifier _ can not be used as a value")
+)
+
+// packages imports the packages of the standard library used by the conditions
+var packages = importer.Default()
+
+// Conditions checks that the conditions of every conditional message are go expressions that only use
+// the arguments of their message and evaluate to a boolean
+func Conditions(msgs *types.MessageBag, wc *util.WarningsCollector) {
+	for _, instance := range msgs.Instances() {
+		for _, lang := range instance.Languages().Get() {
+			types.VisitValues(instance.MessageMust(lang), func(value types.MessageValue) {
+				conditional, ok := value.(*types.ValueConditional)
+				if !ok {
+					return
+				}
+				for _, condition := range conditional.Conditions {
+					if err := CheckCondition(condition.Condition, instance.Args()); err != nil {
+						wc.AddWarning(ErrInvalidCondition.WithArgs(condition.Condition, instance.PathAsStr(), lang, instance.Source(lang), err))
+					}
+				}
+			})
+		}
+	}
+}
+
+// CheckCondition checks that condition is a boolean go expression using only the arguments in args and the
+// packages of parse.ConditionPackages. Arguments whose type can not be resolved without importing packages are opaque:
+// the operations that use them are not checked, but the rest of the expression is, and a condition whose result depends
+// on them must at least not be an operation that never gives a boolean, like `amount + 1`
+func CheckCondition(condition string, args *types.ArgumentList) error {
+	expr, err := parser.ParseExpr(condition)
+	if err != nil {
+		return ErrConditionSyntax.WithArgs(err)
+	}
+
+	blank := false
+	ast.Inspect(expr, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Ident); ok && ident.Name == "_" {
+			blank = true
+		}
+		return !blank
+	})
+	if blank {
+		return ErrBlankIdentifier
+	}
+
+	for _, name := range parse.ConditionIdentifiers(expr) {
+		if _, found := args.GetArgument(name); !found {
+			return ErrUnknownIdentifier.WithArgs(name)
+		}
+	}
+
+	fset := token.NewFileSet()
+	pkg := gotypes.NewPackage("messages", "messages")
+	for _, arg := range args.Args {
+		var typ gotypes.Type = gotypes.Typ[gotypes.Invalid] // go/types reports nothing about operations with invalid operands
+		if argType, err := gotypes.Eval(fset, nil, token.NoPos, arg.Type.Type); err == nil && argType.IsType() {
+			typ = argType.Type
+		}
+		pkg.Scope().Insert(gotypes.NewVar(token.NoPos, pkg, arg.Name, typ))
+	}
+	for _, name := range parse.ConditionPackageNames(expr) {
+		if _, found := args.GetArgument(name); found {
+			continue
+		}
+		if imported, err := packages.Import(parse.ConditionPackages[name]); err == nil {
+			pkg.Scope().Insert(gotypes.NewPkgName(token.NoPos, pkg, name, imported))
+		} else {
+			pkg.Scope().Insert(gotypes.NewVar(token.NoPos, pkg, name, gotypes.Typ[gotypes.Invalid])) // opaque
+		}
+	}
+	result, err := gotypes.Eval(fset, pkg, token.NoPos, condition)
+	if err != nil {
+		return ErrConditionTypeCheck.WithArgs(err)
+	}
+	if result.Type == nil || result.Type == gotypes.Typ[gotypes.Invalid] {
+		return notBooleanOperation(expr) // the result depends on an opaque argument
+	}
+	if basic, ok := result.Type.Underlying().(*gotypes.Basic); !ok || basic.Info()&gotypes.IsBoolean == 0 {
+		return ErrConditionIsNotBoolean.WithArgs(result.Type.String())
+	}
+	return nil
+}
+
+// notBooleanOperation returns an error if the expression is an operation whose result is never a boolean, whatever the
+// types of its operands are. Other expressions, like calls or arguments, may be booleans
+func notBooleanOperation(expr ast.Expr) error {
+	for {
+		paren, ok := expr.(*ast.ParenExpr)
+		if !ok {
+			break
+		}
+		expr = paren.X
+	}
+	switch e := expr.(type) {
+	case *ast.UnaryExpr:
+		if e.Op != token.NOT {
+			return ErrNotBooleanOperator.WithArgs(e.Op.String())
+		}
+	case *ast.BinaryExpr:
+		if e.Op.Precedence() > token.EQL.Precedence() { // arithmetic operators
+			return ErrNotBooleanOperator.WithArgs(e.Op.String())
+		}
+	}
+	return nil
+}
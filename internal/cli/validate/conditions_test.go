@@ -0,0 +1,57 @@
+package validate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+)
+
+func TestCheckCondition(t *testing.T) {
+	args := &types.ArgumentList{Args: []*types.MessageArgument{
+		{Name: "count", Type: &types.ArgumentType{Name: "int", Type: "int"}},
+		{Name: "name", Type: &types.ArgumentType{Name: "string", Type: "string"}},
+		{Name: "time", Type: &types.ArgumentType{Name: "bool", Type: "bool"}},
+		{Name: "amount", Type: &types.ArgumentType{Name: "money", Type: "money.Amount", Import: "github.com/acme/money"}},
+	}}
+	tests := []struct {
+		condition string
+		want      error // nil if the condition is valid
+	}{
+		{condition: "count > 1"},
+		{condition: `count > 1 && name == "admin"`},
+		{condition: "count", want: ErrConditionIsNotBoolean},
+		{condition: "count >", want: ErrConditionSyntax},
+		{condition: "other > 1", want: ErrUnknownIdentifier},
+		{condition: `count == "one"`, want: ErrConditionTypeCheck},
+		// amount has a type of another package, only the operations with it are not checked
+		{condition: "amount > 0"},
+		{condition: "amount.IsZero()"},
+		{condition: "amount > 0 && count == 1"},
+		{condition: `amount > 0 && count == "one"`, want: ErrConditionTypeCheck},
+		{condition: "amount + 1", want: ErrNotBooleanOperator},
+		{condition: "-(amount)", want: ErrNotBooleanOperator},
+		{condition: "!amount.IsZero()"},
+		{condition: "_", want: ErrBlankIdentifier},
+		{condition: "count > _", want: ErrBlankIdentifier},
+		{condition: "amount == _", want: ErrBlankIdentifier},
+		{condition: `name + "s"`, want: ErrConditionIsNotBoolean},
+		// the packages of the standard library are not arguments, unless an argument hides them
+		{condition: `strings.HasPrefix(name, "admin")`},
+		{condition: `strings.Contains(name, 1)`, want: ErrConditionTypeCheck},
+		{condition: "strings.ToUpper(name)", want: ErrConditionIsNotBoolean},
+		{condition: "math.Abs(float64(count)) > 1"},
+		{condition: "time && count > 1"},
+	}
+	for _, test := range tests {
+		t.Run(test.condition, func(t *testing.T) {
+			err := CheckCondition(test.condition, args)
+			if test.want == nil && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if test.want != nil && !errors.Is(err, test.want) {
+				t.Errorf("expected %v, got %v", test.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package validate
+
+import (
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+var (
+	ErrInvalidFormat util.Error = util.MakeError("invalid format '%s' of the %s argument %s in the path %s in the lang %s (file %s): %w")
+)
+
+// Formats checks that the arguments whose type restricts the formats, like the localized ones, are used with a
+// format of their type
+func Formats(msgs *types.MessageBag, wc *util.WarningsCollector) {
+	for _, instance := range msgs.Instances() {
+		for _, lang := range instance.Languages().Get() {
+			types.VisitValues(instance.MessageMust(lang), func(value types.MessageValue) {
+				parametrized, ok := value.(*types.ValueParametrized)
+				if !ok {
+					return
+				}
+				for _, used := range parametrized.Args {
+					if used.IsReference() {
+						continue
+					}
+					// the argument of the instance has the type and format merged from all languages
+					arg, found := instance.Args().GetArgument(used.Argument.Name)
+					if !found || arg.Type.CheckFormat == nil {
+						continue
+					}
+					format := used.Format
+					if format == "" {
+						format = arg.DefaultFormat()
+					}
+					if err := arg.Type.CheckFormat(format); err != nil {
+						wc.AddWarning(ErrInvalidFormat.WithArgs(format, arg.Type.Name, arg.Name, instance.PathAsStr(), lang, instance.Source(lang), err))
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package validate
+
+import (
+	"github.com/MrNemo64/go-n-i18n/internal/cli/cldr"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/writing"
+)
+
+var (
+	ErrNoPluralRules   util.Error = util.MakeError("no plural rules are known for the lang %s, all numbers will use the 'other' plural form")
+	ErrNoOrdinalRules             = util.MakeError("no ordinal rules are known for the lang %s, all numbers will use the 'other' ordinal form")
+	ErrNoNumberSymbols            = util.MakeError("no number symbols are known for the lang %s, numbers will be written with the root symbols")
+	ErrNoCalendarData             = util.MakeError("no calendar data is known for the lang %s, dates will be written with the root patterns")
+	ErrNoTimeUnits                = util.MakeError("no time unit patterns are known for the lang %s, durations will be written with the root patterns")
+	ErrNoListPatterns             = util.MakeError("no list patterns are known for the lang %s, lists will be joined with the root patterns")
+)
+
+// LanguageData reports the languages that have no CLDR data for the arguments the messages use. The generated code
+// still works but uses the root data for them
+func LanguageData(msgs *types.MessageBag, langs []string, wc *util.WarningsCollector) {
+	plurals, ordinals := writing.UsesPlurals(msgs) || writing.UsesDurations(msgs), writing.UsesOrdinals(msgs)
+	numbers, dates, durations, lists := writing.UsesNumbers(msgs), writing.UsesDates(msgs), writing.UsesDurations(msgs), writing.UsesLists(msgs)
+	for _, lang := range langs {
+		if _, found := cldr.CardinalRules(lang); !found && plurals {
+			wc.AddWarning(ErrNoPluralRules.WithArgs(lang))
+		}
+		if _, found := cldr.OrdinalRules(lang); !found && plurals && ordinals {
+			wc.AddWarning(ErrNoOrdinalRules.WithArgs(lang))
+		}
+		if _, found := cldr.Numbers(lang); !found && numbers {
+			wc.AddWarning(ErrNoNumberSymbols.WithArgs(lang))
+		}
+		if _, found := cldr.Dates(lang); !found && dates {
+			wc.AddWarning(ErrNoCalendarData.WithArgs(lang))
+		}
+		if _, found := cldr.Units(lang); !found && durations {
+			wc.AddWarning(ErrNoTimeUnits.WithArgs(lang))
+		}
+		if _, found := cldr.Lists(lang); !found && lists {
+			wc.AddWarning(ErrNoListPatterns.WithArgs(lang))
+		}
+	}
+}
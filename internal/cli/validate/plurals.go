@@ -0,0 +1,57 @@
+package validate
+
+import (
+	"go/token"
+	gotypes "go/types"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+var (
+	ErrMissingOtherForm util.Error = util.MakeError("the plural in the path %s in the lang %s (file %s) has no 'other' form")
+	ErrInvalidExactForm            = util.MakeError("the exact form =%s of the plural in the path %s in the lang %s (file %s) can not be compared with the argument %s of type %s: %w")
+)
+
+// Plurals checks that the plurals have an other form and that their exact forms are numbers of the type of their
+// argument, like integers for int arguments. Only the plurals that are the whole message of a language that is not the
+// default one can leave the other form out, they use the one of the default language
+func Plurals(msgs *types.MessageBag, defLang string, wc *util.WarningsCollector) {
+	for _, instance := range msgs.Instances() {
+		for _, lang := range instance.Languages().Get() {
+			message := instance.MessageMust(lang)
+			types.VisitValues(message, func(value types.MessageValue) {
+				plural, ok := value.(*types.ValuePlural)
+				if !ok {
+					return
+				}
+				if arg, found := instance.Args().GetArgument(plural.Argument.Name); found {
+					for _, exact := range plural.Exact {
+						if err := checkExactForm(arg, exact.Number); err != nil {
+							wc.AddWarning(ErrInvalidExactForm.WithArgs(exact.Number, instance.PathAsStr(), lang, instance.Source(lang),
+								arg.Name, arg.Type.Type, err))
+						}
+					}
+				}
+				if plural.Other() != nil || (lang != defLang && value == message) {
+					return
+				}
+				wc.AddWarning(ErrMissingOtherForm.WithArgs(instance.PathAsStr(), lang, instance.Source(lang)))
+			})
+		}
+	}
+}
+
+// checkExactForm checks that the argument can be compared with the number in the generated code. Arguments of types of
+// other packages are not checked
+func checkExactForm(arg *types.MessageArgument, number string) error {
+	fset := token.NewFileSet()
+	argType, err := gotypes.Eval(fset, nil, token.NoPos, arg.Type.Type)
+	if err != nil || !argType.IsType() {
+		return nil
+	}
+	pkg := gotypes.NewPackage("messages", "messages")
+	pkg.Scope().Insert(gotypes.NewVar(token.NoPos, pkg, "arg", argType.Type))
+	_, err = gotypes.Eval(fset, pkg, token.NoPos, "arg == "+number)
+	return err
+}
@@ -0,0 +1,36 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+)
+
+func TestCheckExactForm(t *testing.T) {
+	tests := []struct {
+		argType string
+		number  string
+		valid   bool
+	}{
+		{argType: "int", number: "0", valid: true},
+		{argType: "int", number: "-1", valid: true},
+		{argType: "int", number: "1.0", valid: true}, // an untyped constant representable as an int
+		{argType: "int", number: "1.5"},
+		{argType: "uint", number: "-1"},
+		{argType: "int8", number: "1000"},
+		{argType: "float64", number: "1.5", valid: true},
+		{argType: "money.Amount", number: "1.5", valid: true}, // types of other packages are not checked
+	}
+	for _, test := range tests {
+		t.Run(test.argType+" "+test.number, func(t *testing.T) {
+			arg := &types.MessageArgument{Name: "count", Type: &types.ArgumentType{Name: test.argType, Type: test.argType}}
+			err := checkExactForm(arg, test.number)
+			if test.valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.valid && err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
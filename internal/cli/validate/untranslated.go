@@ -0,0 +1,28 @@
+package validate
+
+import (
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+var (
+	ErrUntranslatedDefault util.Error = util.MakeError("the message in the path %s (file %s) is null but the default language %s must have all its texts")
+)
+
+// Untranslated checks that the default language has no untranslated texts, since they are the ones the rest of
+// languages use for theirs
+func Untranslated(msgs *types.MessageBag, defLang string, wc *util.WarningsCollector) {
+	for _, instance := range msgs.Instances() {
+		message, found := instance.Message(defLang)
+		if !found {
+			continue
+		}
+		untranslated := false
+		types.VisitValues(message, func(value types.MessageValue) {
+			untranslated = untranslated || types.IsUntranslated(value)
+		})
+		if untranslated {
+			wc.AddWarning(ErrUntranslatedDefault.WithArgs(instance.PathAsStr(), instance.Source(defLang), defLang))
+		}
+	}
+}
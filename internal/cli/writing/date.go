@@ -0,0 +1,158 @@
+package writing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/cldr"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+// dateTypes are the argument types formatted with the calendar data of each language
+var dateTypes = []string{"date", "time", "datetime"}
+
+// UsesDates reports if any message in msgs has a date, time or datetime argument
+func UsesDates(msgs *types.MessageBag) bool {
+	return usesArgumentTypes(msgs, dateTypes...)
+}
+
+// createDateArgument returns the expression that formats a date, time or datetime argument in the language
+func (w *GoCodeWriter) createDateArgument(lang string, arg *types.MessageArgument, format string) string {
+	symbols, _ := cldr.Dates(lang)
+	return fmt.Sprintf("formatDateTime(%s, %s, %q)", dateNamesName(symbols), arg.Name, symbols.DatePattern(arg.Type.Name, format))
+}
+
+func dateNamesName(symbols *cldr.DateSymbols) string {
+	return "dateNames_" + strings.ReplaceAll(symbols.Id, "-", "_")
+}
+
+// WriteDateFormats writes the names of the months and days of each language and the function that formats dates
+// with them
+func (w *GoCodeWriter) WriteDateFormats() {
+	if !w.dates {
+		return
+	}
+	w.use("strconv", "strings", "time")
+	w.w("\n// dateNames are the CLDR names used to format the dates of a language, the days start on Sunday\n")
+	w.w("type dateNames struct {\n")
+	w.w("    months, shortMonths [12]string\n")
+	w.w("    days, shortDays [7]string\n")
+	w.w("    am, pm string\n")
+	w.w("}\n")
+
+	written := util.NewSet[string]()
+	for _, lang := range w.langs {
+		symbols, _ := cldr.Dates(lang)
+		if written.Contains(symbols.Id) {
+			continue
+		}
+		written.Add(symbols.Id)
+		w.w("\nvar %s = dateNames{\n", dateNamesName(symbols))
+		w.addIndent()
+		w.w("months: %s,\n", stringArray(symbols.Months[:]))
+		w.w("shortMonths: %s,\n", stringArray(symbols.ShortMonths[:]))
+		w.w("days: %s,\n", stringArray(symbols.Days[:]))
+		w.w("shortDays: %s,\n", stringArray(symbols.ShortDays[:]))
+		w.w("am: %q, pm: %q,\n", symbols.Am, symbols.Pm)
+		w.removeIndent()
+		w.w("}\n")
+	}
+
+	w.w("\n// formatDateTime writes t with a CLDR date pattern like \"EEEE, d 'de' MMMM 'de' y\"\n")
+	w.w("func formatDateTime(names dateNames, t time.Time, pattern string) string {\n")
+	w.addIndent()
+	w.w("sb := strings.Builder{}\n")
+	w.w("for i := 0; i < len(pattern); {\n")
+	w.addIndent()
+	w.w("c := pattern[i]\n")
+	w.w("if c == '\\'' {\n")
+	w.addIndent()
+	w.w("end := strings.IndexByte(pattern[i+1:], '\\'')\n")
+	w.w("switch {\n")
+	w.w("case end < 0:\n")
+	w.w("    sb.WriteString(pattern[i+1:])\n")
+	w.w("    i = len(pattern)\n")
+	w.w("case end == 0: // '' is a quote\n")
+	w.w("    sb.WriteByte('\\'')\n")
+	w.w("    i += 2\n")
+	w.w("default:\n")
+	w.w("    sb.WriteString(pattern[i+1 : i+1+end])\n")
+	w.w("    i += end + 2\n")
+	w.w("}\n")
+	w.w("continue\n")
+	w.removeIndent()
+	w.w("}\n")
+	w.w("if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z') {\n")
+	w.w("    sb.WriteByte(c)\n")
+	w.w("    i++\n")
+	w.w("    continue\n")
+	w.w("}\n")
+	w.w("n := 1\n")
+	w.w("for i+n < len(pattern) && pattern[i+n] == c {\n")
+	w.w("    n++\n")
+	w.w("}\n")
+	w.w("i += n\n")
+	w.w("switch c {\n")
+	w.w("case 'y':\n")
+	w.w("    if n == 2 {\n")
+	w.w("        sb.WriteString(padDateField(t.Year()%%100, 2))\n")
+	w.w("    } else {\n")
+	w.w("        sb.WriteString(padDateField(t.Year(), n))\n")
+	w.w("    }\n")
+	w.w("case 'M', 'L':\n")
+	w.w("    switch {\n")
+	w.w("    case n >= 4:\n")
+	w.w("        sb.WriteString(names.months[t.Month()-1])\n")
+	w.w("    case n == 3:\n")
+	w.w("        sb.WriteString(names.shortMonths[t.Month()-1])\n")
+	w.w("    default:\n")
+	w.w("        sb.WriteString(padDateField(int(t.Month()), n))\n")
+	w.w("    }\n")
+	w.w("case 'd':\n")
+	w.w("    sb.WriteString(padDateField(t.Day(), n))\n")
+	w.w("case 'E':\n")
+	w.w("    if n >= 4 {\n")
+	w.w("        sb.WriteString(names.days[t.Weekday()])\n")
+	w.w("    } else {\n")
+	w.w("        sb.WriteString(names.shortDays[t.Weekday()])\n")
+	w.w("    }\n")
+	w.w("case 'a':\n")
+	w.w("    if t.Hour() < 12 {\n")
+	w.w("        sb.WriteString(names.am)\n")
+	w.w("    } else {\n")
+	w.w("        sb.WriteString(names.pm)\n")
+	w.w("    }\n")
+	w.w("case 'h':\n")
+	w.w("    sb.WriteString(padDateField((t.Hour()+11)%%12+1, n))\n")
+	w.w("case 'H':\n")
+	w.w("    sb.WriteString(padDateField(t.Hour(), n))\n")
+	w.w("case 'K':\n")
+	w.w("    sb.WriteString(padDateField(t.Hour()%%12, n))\n")
+	w.w("case 'm':\n")
+	w.w("    sb.WriteString(padDateField(t.Minute(), n))\n")
+	w.w("case 's':\n")
+	w.w("    sb.WriteString(padDateField(t.Second(), n))\n")
+	w.w("case 'z':\n")
+	w.w("    sb.WriteString(t.Format(\"MST\"))\n")
+	w.w("}\n")
+	w.removeIndent()
+	w.w("}\n")
+	w.w("return sb.String()\n")
+	w.removeIndent()
+	w.w("}\n")
+
+	w.w("\nfunc padDateField(value, width int) string {\n")
+	w.w("    s := strconv.Itoa(value)\n")
+	w.w("    for len(s) < width {\n")
+	w.w("        s = \"0\" + s\n")
+	w.w("    }\n")
+	w.w("    return s\n")
+	w.w("}\n")
+}
+
+// stringArray returns the go array literal with the values
+func stringArray(values []string) string {
+	quoted := util.Map(values, func(_ int, v *string) string { return fmt.Sprintf("%q", *v) })
+	return fmt.Sprintf("[%d]string{%s}", len(values), strings.Join(quoted, ", "))
+}
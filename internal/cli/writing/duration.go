@@ -0,0 +1,129 @@
+package writing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/cldr"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+// durationTypes are the argument types written as an amount of time units with the patterns of each language
+var durationTypes = []string{"duration", "reltime"}
+
+// UsesDurations reports if any message in msgs has a duration or reltime argument
+func UsesDurations(msgs *types.MessageBag) bool {
+	return usesArgumentTypes(msgs, durationTypes...)
+}
+
+// createDurationArgument returns the expression that writes a duration or reltime argument in the language
+func (w *GoCodeWriter) createDurationArgument(lang string, arg *types.MessageArgument) string {
+	units, _ := cldr.Units(lang)
+	rules, _ := cldr.CardinalRules(lang)
+	function := "formatDuration"
+	if arg.Type.Name == "reltime" {
+		function = "formatRelativeTime"
+	}
+	return fmt.Sprintf("%s(%s, %s, %s)", function, timeUnitsName(units), pluralRuleFunctionName(rules, false), arg.Name)
+}
+
+func timeUnitsName(units *cldr.TimeUnits) string {
+	return "timeUnits_" + strings.ReplaceAll(units.Id, "-", "_")
+}
+
+// WriteDurationFormats writes the time unit patterns of each language and the functions that write durations and
+// relative times with them
+func (w *GoCodeWriter) WriteDurationFormats() {
+	if !w.durations {
+		return
+	}
+	w.use("strconv", "strings", "time")
+	w.w("\n// timeUnits are the CLDR patterns used to write durations and relative times of a language, by unit from the\n")
+	w.w("// second to the year and by plural form. The plural forms without pattern use the other one\n")
+	w.w("type timeUnits struct {\n")
+	w.w("    duration, future, past [%d][%d]string\n", len(cldr.TimeUnitNames), len(types.PluralCategories()))
+	w.w("    now string\n")
+	w.w("}\n")
+
+	w.w("\nvar timeUnitSizes = [%d]time.Duration{time.Second, time.Minute, time.Hour, 24 * time.Hour, 7 * 24 * time.Hour, 30 * 24 * time.Hour, 365 * 24 * time.Hour}\n", len(cldr.TimeUnitNames))
+
+	written := util.NewSet[string]()
+	for _, lang := range w.langs {
+		units, _ := cldr.Units(lang)
+		if written.Contains(units.Id) {
+			continue
+		}
+		written.Add(units.Id)
+		w.w("\nvar %s = timeUnits{\n", timeUnitsName(units))
+		w.addIndent()
+		w.writeUnitPatterns("duration", units.Duration)
+		w.writeUnitPatterns("future", units.Future)
+		w.writeUnitPatterns("past", units.Past)
+		w.w("now: %q,\n", units.Now)
+		w.removeIndent()
+		w.w("}\n")
+	}
+
+	w.w("\n// formatDuration writes d rounded to the largest unit it has at least one of\n")
+	w.w("func formatDuration(units timeUnits, plural func(float64) pluralForm, d time.Duration) string {\n")
+	w.w("    return formatTimeUnits(units.duration, plural, d)\n")
+	w.w("}\n")
+
+	w.w("\n// formatRelativeTime writes the time from now to t rounded to the largest unit it has at least one of\n")
+	w.w("func formatRelativeTime(units timeUnits, plural func(float64) pluralForm, t time.Time) string {\n")
+	w.addIndent()
+	w.w("d := time.Until(t).Round(time.Second)\n")
+	w.w("switch {\n")
+	w.w("case d == 0:\n")
+	w.w("    return units.now\n")
+	w.w("case d < 0:\n")
+	w.w("    return formatTimeUnits(units.past, plural, d)\n")
+	w.w("default:\n")
+	w.w("    return formatTimeUnits(units.future, plural, d)\n")
+	w.w("}\n")
+	w.removeIndent()
+	w.w("}\n")
+
+	w.w("\n// formatTimeUnits writes the magnitude of d, which is unsigned so the one of math.MinInt64 fits, rounded to the\n")
+	w.w("// nearest amount of its unit. The next unit is used if the rounded amount is at least one of it\n")
+	w.w("func formatTimeUnits(patterns [%d][%d]string, plural func(float64) pluralForm, d time.Duration) string {\n", len(cldr.TimeUnitNames), len(types.PluralCategories()))
+	w.addIndent()
+	w.w("magnitude := uint64(d)\n")
+	w.w("if d < 0 {\n")
+	w.w("    magnitude = -magnitude\n")
+	w.w("}\n")
+	w.w("unit := 0\n")
+	w.w("for unit < len(timeUnitSizes)-1 && magnitude >= uint64(timeUnitSizes[unit+1]) {\n")
+	w.w("    unit++\n")
+	w.w("}\n")
+	w.w("amount := (magnitude + uint64(timeUnitSizes[unit])/2) / uint64(timeUnitSizes[unit])\n")
+	w.w("if unit < len(timeUnitSizes)-1 && amount*uint64(timeUnitSizes[unit]) >= uint64(timeUnitSizes[unit+1]) {\n")
+	w.w("    unit++\n")
+	w.w("    amount = (magnitude + uint64(timeUnitSizes[unit])/2) / uint64(timeUnitSizes[unit])\n")
+	w.w("}\n")
+	w.w("pattern := patterns[unit][plural(float64(amount))]\n")
+	w.w("if pattern == \"\" {\n")
+	w.w("    pattern = patterns[unit][%s]\n", pluralFormConstant("other"))
+	w.w("}\n")
+	w.w("return strings.ReplaceAll(pattern, \"{0}\", strconv.FormatUint(amount, 10))\n")
+	w.removeIndent()
+	w.w("}\n")
+}
+
+// writeUnitPatterns writes the field of timeUnits with the patterns of each unit by plural form
+func (w *GoCodeWriter) writeUnitPatterns(field string, patterns [7]map[string]string) {
+	w.w("%s: [%d][%d]string{\n", field, len(patterns), len(types.PluralCategories()))
+	w.addIndent()
+	for i, byCategory := range patterns {
+		forms := make([]string, 0, len(byCategory))
+		for _, category := range types.PluralCategories() {
+			if pattern, found := byCategory[category.String()]; found {
+				forms = append(forms, fmt.Sprintf("%s: %q", pluralFormConstant(category.String()), pattern))
+			}
+		}
+		w.w("{%s}, // %s\n", strings.Join(forms, ", "), cldr.TimeUnitNames[i])
+	}
+	w.removeIndent()
+	w.w("},\n")
+}
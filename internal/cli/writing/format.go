@@ -0,0 +1,94 @@
+package writing
+
+import (
+	"errors"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	gotypes "go/types"
+	"strconv"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+var (
+	ErrInvalidMessageCode      util.Error = util.MakeError("the generated code of the message %s in the lang %s is not valid go: %w")
+	ErrInvalidMessageSignature            = util.MakeError("the generated signature of the message %s is not valid go: %w")
+	ErrInvalidCode                        = util.MakeError("the generated code is not valid go: %w")
+)
+
+// writtenFunction is the range of lines of the generated code with the method of a message in a language, or with
+// its declaration in the interface if the language is empty
+type writtenFunction struct {
+	start, end int
+	path, lang string
+}
+
+// formatCode type checks the generated code and formats it with gofmt. If the code is not valid go the error points to
+// the message whose method has the first error
+func (w *GoCodeWriter) formatCode(code string) (string, error) {
+	formatted, err := format.Source([]byte(code))
+	if err != nil {
+		var list scanner.ErrorList
+		if errors.As(err, &list) && len(list) > 0 {
+			return "", w.codeError(list[0].Pos.Line, err)
+		}
+		return "", ErrInvalidCode.WithArgs(err)
+	}
+	if err := typeCheck(code); err != nil {
+		var typeErr gotypes.Error
+		if errors.As(err, &typeErr) {
+			return "", w.codeError(typeErr.Fset.Position(typeErr.Pos).Line, err)
+		}
+		return "", ErrInvalidCode.WithArgs(err)
+	}
+	return string(formatted), nil
+}
+
+// codeError returns the error of the message whose method has the line of the generated code
+func (w *GoCodeWriter) codeError(line int, err error) error {
+	for _, function := range w.functions {
+		if function.start > line || line > function.end {
+			continue
+		}
+		if function.lang == "" {
+			return ErrInvalidMessageSignature.WithArgs(function.path, err)
+		}
+		return ErrInvalidMessageCode.WithArgs(function.path, function.lang, err)
+	}
+	return ErrInvalidCode.WithArgs(err)
+}
+
+// typeCheck returns the first type error of the code. The code that imports packages that can not be imported, like
+// the ones of custom types outside of the standard library, is not checked since its types can not be known
+func typeCheck(code string) error {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "generated.go", code, parser.SkipObjectResolution)
+	if err != nil {
+		return err
+	}
+	imported := importer.Default()
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return err
+		}
+		if _, err := imported.Import(path); err != nil {
+			return nil
+		}
+	}
+	var first error
+	config := gotypes.Config{
+		Importer: imported,
+		Error: func(err error) {
+			if first == nil {
+				first = err
+			}
+		},
+	}
+	config.Check(file.Name.Name, fset, []*ast.File{file}, nil)
+	return first
+}
@@ -0,0 +1,50 @@
+package writing
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFormatCode(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want error // nil if the code is valid
+	}{
+		{
+			name: "valid",
+			code: "package lang\n\nimport \"strings\"\n\nfunc Greet(name string) string {\n\treturn strings.ToUpper(name)\n}\n",
+		},
+		{
+			name: "syntax error",
+			code: "package lang\n\nfunc Greet(name string) string {\n\treturn name +\n}\n",
+			want: ErrInvalidMessageCode,
+		},
+		{
+			name: "type error",
+			code: "package lang\n\nfunc Greet(name string) string {\n\treturn name + 1\n}\n",
+			want: ErrInvalidMessageCode,
+		},
+		{
+			name: "type error outside of the messages",
+			code: "package lang\n\nfunc Greet(name string) string {\n\treturn name\n}\n\nvar count int = \"one\"\n",
+			want: ErrInvalidCode,
+		},
+		{
+			name: "packages that can not be imported",
+			code: "package lang\n\nimport \"github.com/acme/money\"\n\nfunc Greet(amount money.Amount) string {\n\treturn amount + 1\n}\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := &GoCodeWriter{functions: []writtenFunction{{start: 3, end: 6, path: "greet", lang: "en"}}}
+			_, err := w.formatCode(test.code)
+			if test.want == nil && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if test.want != nil && !errors.Is(err, test.want) {
+				t.Errorf("expected %v, got %v", test.want, err)
+			}
+		})
+	}
+}
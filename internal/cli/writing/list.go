@@ -0,0 +1,82 @@
+package writing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/assert"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/cldr"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+// UsesLists reports if any message in msgs has a list argument
+func UsesLists(msgs *types.MessageBag) bool {
+	return usesArgumentTypes(msgs, "list")
+}
+
+// createListArgument returns the expression that joins the items of a list argument in the language
+func (w *GoCodeWriter) createListArgument(lang string, arg *types.MessageArgument, format string) string {
+	patterns, _ := cldr.Lists(lang)
+	style, err := cldr.ParseListFormat(format)
+	assert.NoError(err) // the formats are validated before generating the code
+	return fmt.Sprintf("formatList(%s.%s, %s)", listPatternsName(patterns), style, arg.Name)
+}
+
+func listPatternsName(patterns *cldr.ListPatterns) string {
+	return "listPatterns_" + strings.ReplaceAll(patterns.Id, "-", "_")
+}
+
+// WriteListFormats writes the list patterns of each language and the function that joins lists with them
+func (w *GoCodeWriter) WriteListFormats() {
+	if !w.lists {
+		return
+	}
+	w.use("strings")
+	w.w("\n// listPattern are the CLDR patterns that join the first item of a list with the rest, the items in between,\n")
+	w.w("// the last two items and the items of lists with only two\n")
+	w.w("type listPattern struct {\n")
+	w.w("    start, middle, end, two string\n")
+	w.w("}\n")
+
+	written := util.NewSet[string]()
+	for _, lang := range w.langs {
+		patterns, _ := cldr.Lists(lang)
+		if written.Contains(patterns.Id) {
+			continue
+		}
+		written.Add(patterns.Id)
+		w.w("\nvar %s = struct{ and, or, unit listPattern }{\n", listPatternsName(patterns))
+		w.addIndent()
+		for _, style := range []struct {
+			name    string
+			pattern cldr.ListPattern
+		}{{"and", patterns.And}, {"or", patterns.Or}, {"unit", patterns.Unit}} {
+			w.w("%s: listPattern{start: %q, middle: %q, end: %q, two: %q},\n", style.name, style.pattern.Start, style.pattern.Middle, style.pattern.End, style.pattern.Two)
+		}
+		w.removeIndent()
+		w.w("}\n")
+	}
+
+	w.w("\n// formatList joins the items with the pattern\n")
+	w.w("func formatList(p listPattern, items []string) string {\n")
+	w.addIndent()
+	w.w("join := func(pattern, first, rest string) string {\n")
+	w.w("    return strings.NewReplacer(\"{0}\", first, \"{1}\", rest).Replace(pattern)\n")
+	w.w("}\n")
+	w.w("switch len(items) {\n")
+	w.w("case 0:\n")
+	w.w("    return \"\"\n")
+	w.w("case 1:\n")
+	w.w("    return items[0]\n")
+	w.w("case 2:\n")
+	w.w("    return join(p.two, items[0], items[1])\n")
+	w.w("}\n")
+	w.w("joined := join(p.end, items[len(items)-2], items[len(items)-1])\n")
+	w.w("for i := len(items) - 3; i > 0; i-- {\n")
+	w.w("    joined = join(p.middle, items[i], joined)\n")
+	w.w("}\n")
+	w.w("return join(p.start, items[0], joined)\n")
+	w.removeIndent()
+	w.w("}\n")
+}
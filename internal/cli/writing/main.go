@@ -2,10 +2,13 @@ package writing
 
 import (
 	"fmt"
+	"go/parser"
 	"slices"
 	"strings"
 
 	"github.com/MrNemo64/go-n-i18n/internal/cli/assert"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/cldr"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/parse"
 	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
 	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
 )
@@ -19,9 +22,25 @@ type GoCodeWriter struct {
 	langs     []string
 	defLang   string
 	pack      string
+	plurals   bool
+	cardinals bool
+	ordinals  bool
+	numbers   bool
+	dates     bool
+	durations bool
+	lists     bool
+	// imports are the packages used by the code written so far and aliases the names of the ones imported with
+	// another name than their path
+	imports *util.Set[string]
+	aliases map[string]string
+	// lines is the amount of lines written so far and functions the lines of the methods of the messages
+	lines     int
+	functions []writtenFunction
 }
 
-func GenerateGoCode(msgs *types.MessageBag, namer MessageEntryNamer, langs []string, defLang, pack string) string {
+// GenerateGoCode returns the formatted code of the messages, or an error if the code is not valid go, like when a
+// condition is not a valid expression
+func GenerateGoCode(msgs *types.MessageBag, namer MessageEntryNamer, langs []string, defLang, pack string) (string, error) {
 	assert.Has(langs, defLang)
 	slices.Sort(langs)
 	cw := GoCodeWriter{
@@ -33,16 +52,39 @@ func GenerateGoCode(msgs *types.MessageBag, namer MessageEntryNamer, langs []str
 		langs:     langs,
 		defLang:   defLang,
 		pack:      pack,
+		imports:   util.NewSet[string](),
+		aliases:   make(map[string]string),
 	}
+	cw.numbers = UsesNumbers(msgs)
+	cw.dates = UsesDates(msgs)
+	cw.durations = UsesDurations(msgs)
+	cw.lists = UsesLists(msgs)
+	cw.cardinals, cw.ordinals = pluralKinds(msgs)
+	cw.cardinals = cw.cardinals || cw.durations // the units are written with the plural form of their amount
+	cw.plurals = cw.cardinals || cw.ordinals
 	cw.GenerateCode()
-	return cw.sb.String()
+	return cw.formatCode(cw.sb.String())
 }
 
+// GenerateCode writes the code and then the header before it, importing the packages the code uses
 func (w *GoCodeWriter) GenerateCode() {
-	w.WriteHeader()
 	w.WriteGetMethods()
 	w.WriteInterfaces()
 	w.WriteStructs()
+	w.WritePluralRules()
+	w.WriteNumberFormats()
+	w.WriteDateFormats()
+	w.WriteDurationFormats()
+	w.WriteListFormats()
+	code := w.sb.String()
+	w.sb.Reset()
+	w.lines = 0
+	w.WriteHeader()
+	for i := range w.functions {
+		w.functions[i].start += w.lines
+		w.functions[i].end += w.lines
+	}
+	w.sb.WriteString(code)
 }
 
 func (w *GoCodeWriter) WriteHeader() {
@@ -51,13 +93,80 @@ func (w *GoCodeWriter) WriteHeader() {
 	w.w("package ")
 	w.w(w.pack)
 	w.w("\n\n")
+	if w.imports.Size() == 0 {
+		return
+	}
+	std, others := importGroups(w.imports)
 	w.w("import (\n")
-	w.w("    \"fmt\"\n")
-	w.w("    \"strings\"\n")
+	for _, path := range std {
+		w.writeImport(path)
+	}
+	if len(others) > 0 {
+		w.w("\n")
+	}
+	for _, path := range others {
+		w.writeImport(path)
+	}
 	w.w(")\n\n")
 }
 
+func (w *GoCodeWriter) writeImport(path string) {
+	if alias, found := w.aliases[path]; found {
+		w.w("    %s %q\n", alias, path)
+	} else {
+		w.w("    %q\n", path)
+	}
+}
+
+// importGroups returns the sorted import paths of the standard library, whose first element has no dot, and the
+// sorted rest
+func importGroups(imports *util.Set[string]) (std []string, others []string) {
+	for _, path := range imports.Get() {
+		if first, _, _ := strings.Cut(path, "/"); strings.Contains(first, ".") {
+			others = append(others, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	slices.Sort(std)
+	slices.Sort(others)
+	return
+}
+
+// use records that the code being written needs the packages
+func (w *GoCodeWriter) use(paths ...string) {
+	for _, path := range paths {
+		if path != "" {
+			w.imports.Add(path)
+		}
+	}
+}
+
+// condition records the packages used by the condition of the message and returns it
+func (w *GoCodeWriter) condition(msg *types.MessageInstance, condition string) string {
+	expr, err := parser.ParseExpr(condition)
+	if err != nil {
+		return condition // reported by formatCode
+	}
+	for _, name := range parse.ConditionPackageNames(expr) {
+		if _, found := msg.Args().GetArgument(name); !found {
+			w.use(parse.ConditionPackages[name])
+		}
+	}
+	return condition
+}
+
+// useType records that the code being written needs the package of the argument type, imported with the name the
+// type is qualified with
+func (w *GoCodeWriter) useType(argType *types.ArgumentType) {
+	w.use(argType.Import)
+	if argType.Package != "" && argType.Package != argType.Import {
+		w.aliases[argType.Import] = argType.Package
+	}
+}
+
 func (w *GoCodeWriter) WriteGetMethods() {
+	w.use("fmt", "strings")
 	w.w("func MessagesFor(tag string) (%s, bool) {\n", w.namer.TopLevelName())
 	w.w("    switch strings.ReplaceAll(tag, \"_\", \"-\") {\n")
 	for _, lang := range w.langs {
@@ -98,12 +207,17 @@ func (w *GoCodeWriter) writeInterface(i *types.MessageBag) {
 	w.w("type %s interface{\n", w.namer.InterfaceName(i))
 	w.addIndent()
 	for _, child := range i.Children() {
+		if child.IsInstance() {
+			w.writeDescription(child.AsInstance())
+		}
+		start := w.lines + 1
 		w.w("%s(%s) ", w.namer.FunctionName(child), w.createArgList(child))
 		switch child.Type() {
 		case types.MessageEntryBag:
 			w.w("%s\n", w.namer.InterfaceName(child.AsBag()))
 		case types.MessageEntryInstance:
 			w.w("string\n")
+			w.functions = append(w.functions, writtenFunction{start: start, end: w.lines, path: child.PathAsStr()})
 		default:
 			panic(fmt.Errorf("unknown message entry type %d", child.Type()))
 		}
@@ -118,6 +232,23 @@ func (w *GoCodeWriter) writeInterface(i *types.MessageBag) {
 	}
 }
 
+// writeDescription writes the description of the message in the default language, or in the first language that
+// has one, as the doc comment of its method
+func (w *GoCodeWriter) writeDescription(msg *types.MessageInstance) {
+	description := msg.Description(w.defLang)
+	for _, lang := range w.langs {
+		if description == "" {
+			description = msg.Description(lang)
+		}
+	}
+	if description == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(description), "\n") {
+		w.w("// %s\n", strings.TrimRight(line, " \t"))
+	}
+}
+
 func (w *GoCodeWriter) WriteStructs() {
 	for _, lang := range w.langs {
 		w.writeStruct(lang, w.msgs)
@@ -143,11 +274,13 @@ func (w *GoCodeWriter) writeFunction(lang string, msg types.MessageEntry) {
 		w.w("    return %s{}\n", w.namer.InterfaceNameForLang(lang, msg.AsBag()))
 		w.w("}\n")
 	case types.MessageEntryInstance:
+		start := w.lines + 1
 		w.w("string {\n")
 		w.addIndent()
 		w.writeFunctionBody(lang, msg.AsInstance())
 		w.removeIndent()
 		w.w("}\n")
+		w.functions = append(w.functions, writtenFunction{start: start, end: w.lines, path: msg.PathAsStr(), lang: lang})
 	default:
 		panic(fmt.Errorf("unknown message entry type %d", msg.Type()))
 	}
@@ -159,7 +292,10 @@ func (w *GoCodeWriter) createArgList(msg types.MessageEntry) string {
 		return ""
 	case types.MessageEntryInstance:
 		return strings.Join(
-			util.Map(msg.AsInstance().Args().Args, func(_ int, t **types.MessageArgument) string { return (*t).Name + " " + (*t).Type.Type }),
+			util.Map(msg.AsInstance().Args().Args, func(_ int, t **types.MessageArgument) string {
+				w.useType((*t).Type)
+				return (*t).Name + " " + (*t).Type.Type
+			}),
 			", ",
 		)
 	default:
@@ -169,25 +305,26 @@ func (w *GoCodeWriter) createArgList(msg types.MessageEntry) string {
 
 func (w *GoCodeWriter) writeFunctionBody(lang string, msg *types.MessageInstance) {
 	val := msg.MessageMust(lang)
-	w.writeValue(val)
+	w.writeValue(lang, msg, val)
 }
 
-func (w *GoCodeWriter) writeValue(val types.MessageValue) {
+func (w *GoCodeWriter) writeValue(lang string, msg *types.MessageInstance, val types.MessageValue) {
 	switch val.(type) {
 	case *types.ValueString:
 		w.w("return %s\n", w.createValueValueString(val.AsValueString()))
 	case *types.ValueParametrized:
-		w.w("return %s\n", w.createValueParametrizedValue(val.AsValueParametrized()))
+		w.w("return %s\n", w.createValueParametrizedValue(lang, msg, val.AsValueParametrized()))
 	case *types.ValueMultiline:
 		lines := val.AsMultiline().Lines
-		w.w("return %s", w.createMultilineableString(lines[0]))
+		w.w("return %s", w.createMultilineableString(lang, msg, lines[0]))
 		if len(lines) == 1 {
+			w.w("\n")
 			return
 		}
 		w.w(` + "\n" +` + "\n") // writen like this so maybe the compiler joins them
 		w.addIndent()
 		for i := 1; i < len(lines); i++ {
-			w.wl(w.createMultilineableString(lines[i]))
+			w.wl(w.createMultilineableString(lang, msg, lines[i]))
 			if i != len(lines)-1 {
 				w.w(` + "\n" +` + "\n") // writen like this so maybe the compiler joins them
 			}
@@ -196,49 +333,211 @@ func (w *GoCodeWriter) writeValue(val types.MessageValue) {
 		w.removeIndent()
 	case *types.ValueConditional:
 		conditions := val.AsConditional()
-		w.w("if %s {\n", conditions.Conditions[0].Condition)
+		if len(conditions.Conditions) == 0 && conditions.Else != nil {
+			w.writeValue(lang, msg, conditions.Else.(types.MessageValue))
+			return
+		}
+		w.w("if %s {\n", w.condition(msg, conditions.Conditions[0].Condition))
 		w.addIndent()
 		mval, ok := conditions.Conditions[0].Value.(types.MessageValue)
 		if !ok {
 			panic("") // TODO
 		}
-		w.writeValue(mval)
+		w.writeValue(lang, msg, mval)
 		w.removeIndent()
 		w.w("}")
 		for i := 1; i < len(conditions.Conditions); i++ {
 			condition := conditions.Conditions[i]
-			w.w(" else if %s {\n", condition.Condition)
+			w.w(" else if %s {\n", w.condition(msg, condition.Condition))
 			w.addIndent()
 			mval, ok := conditions.Conditions[i].Value.(types.MessageValue)
 			if !ok {
 				panic("") // TODO
 			}
-			w.writeValue(mval)
+			w.writeValue(lang, msg, mval)
 			w.removeIndent()
 			w.w("}")
 		}
 		w.w(" else {\n")
 		w.addIndent()
 		if conditions.Else == nil {
+			w.use("fmt")
 			w.wl(`panic(fmt.Errorf("no condition was true in conditional"))` + "\n")
 		} else {
 			mval, ok := conditions.Else.(types.MessageValue)
 			if !ok {
 				panic("") // TODO
 			}
-			w.writeValue(mval)
+			w.writeValue(lang, msg, mval)
+		}
+		w.removeIndent()
+		w.w("}\n")
+	case *types.ValuePlural:
+		w.writePlural(lang, msg, val.AsPlural())
+	}
+}
+
+func (w *GoCodeWriter) writePlural(lang string, msg *types.MessageInstance, plural *types.ValuePlural) {
+	arg, found := msg.Args().GetArgument(plural.Argument.Name)
+	assert.True(found, "plural arguments are always added to the argument list")
+	for _, exact := range plural.Exact {
+		w.w("if %s == %s {\n", arg.Name, exact.Number)
+		w.addIndent()
+		w.writeValue(lang, msg, w.pluralizableAsValue(exact.Value))
+		w.removeIndent()
+		w.w("}\n")
+	}
+	if len(plural.Forms) > 1 {
+		value := arg.Name
+		if arg.Type.Type != "float64" {
+			value = fmt.Sprintf("float64(%s)", arg.Name)
+		}
+		rules, _ := cldr.CardinalRules(lang)
+		if plural.Ordinal {
+			rules, _ = cldr.OrdinalRules(lang)
+		}
+		w.w("switch %s(%s) {\n", pluralRuleFunctionName(rules, plural.Ordinal), value)
+		for _, form := range plural.Forms {
+			if form.Category == types.PluralOther {
+				continue
+			}
+			w.w("case %s:\n", pluralFormConstant(form.Category.String()))
+			w.addIndent()
+			w.writeValue(lang, msg, w.pluralizableAsValue(form.Value))
+			w.removeIndent()
+		}
+		w.w("}\n")
+	}
+	w.writeValue(lang, msg, w.pluralizableAsValue(plural.Other()))
+}
+
+func (w *GoCodeWriter) pluralizableAsValue(p types.Pluralizable) types.MessageValue {
+	value, ok := p.(types.MessageValue)
+	if !ok {
+		panic(fmt.Errorf("unknown Pluralizable type %+v", p))
+	}
+	return value
+}
+
+func (w *GoCodeWriter) WritePluralRules() {
+	if !w.plurals {
+		return
+	}
+	w.use("strconv", "strings")
+	w.w("type pluralForm int\n\n")
+	w.w("const (\n")
+	w.addIndent()
+	for i, category := range types.PluralCategories() {
+		if i == 0 {
+			w.w("%s pluralForm = iota\n", pluralFormConstant(category.String()))
+		} else {
+			w.w("%s\n", pluralFormConstant(category.String()))
 		}
+	}
+	w.removeIndent()
+	w.w(")\n\n")
+
+	w.w("// pluralOperands returns the CLDR plural operands of x\n")
+	w.w("func pluralOperands(x float64) (n float64, i, v, f, t int64) {\n")
+	w.addIndent()
+	w.w("if x < 0 {\n")
+	w.w("    x = -x\n")
+	w.w("}\n")
+	w.w("n = x\n")
+	w.w("integer, fraction, _ := strings.Cut(strconv.FormatFloat(x, 'f', -1, 64), \".\")\n")
+	w.w("i, _ = strconv.ParseInt(integer, 10, 64)\n")
+	w.w("v = int64(len(fraction))\n")
+	w.w("f, _ = strconv.ParseInt(fraction, 10, 64)\n")
+	w.w("t = f\n")
+	w.w("return\n")
+	w.removeIndent()
+	w.w("}\n")
+
+	if w.cardinals {
+		w.writePluralRuleFunctions(cldr.CardinalRules, false)
+	}
+	if w.ordinals {
+		w.writePluralRuleFunctions(cldr.OrdinalRules, true)
+	}
+}
+
+func (w *GoCodeWriter) writePluralRuleFunctions(rulesFor func(string) (*cldr.PluralRules, bool), ordinal bool) {
+	written := util.NewSet[string]()
+	for _, lang := range w.langs {
+		rules, _ := rulesFor(lang)
+		if written.Contains(rules.Id) {
+			continue
+		}
+		written.Add(rules.Id)
+		w.w("\nfunc %s(x float64) pluralForm {\n", pluralRuleFunctionName(rules, ordinal))
+		w.addIndent()
+		if len(rules.Rules) > 0 {
+			used := rules.Operands()
+			operands := util.Map([]string{"n", "i", "v", "f", "t"}, func(_ int, op *string) string {
+				if used[*op] {
+					return *op
+				}
+				return "_"
+			})
+			w.w("%s := pluralOperands(x)\n", strings.Join(operands, ", "))
+		} else {
+			w.w("_ = x\n")
+		}
+		for _, rule := range rules.Rules {
+			w.w("if %s {\n", rule.Condition)
+			w.w("    return %s\n", pluralFormConstant(rule.Category))
+			w.w("}\n")
+		}
+		w.w("return %s\n", pluralFormConstant("other"))
 		w.removeIndent()
 		w.w("}\n")
 	}
 }
 
-func (w *GoCodeWriter) createMultilineableString(s types.Multilineable) string {
+func pluralFormConstant(category string) string {
+	return "plural" + strings.ToUpper(category[:1]) + category[1:]
+}
+
+func pluralRuleFunctionName(rules *cldr.PluralRules, ordinal bool) string {
+	if ordinal {
+		return "ordinalRule_" + strings.ReplaceAll(rules.Id, "-", "_")
+	}
+	return "pluralRule_" + strings.ReplaceAll(rules.Id, "-", "_")
+}
+
+// UsesPlurals reports if any message in msgs has a plural value
+func UsesPlurals(msgs *types.MessageBag) bool {
+	cardinals, ordinals := pluralKinds(msgs)
+	return cardinals || ordinals
+}
+
+// UsesOrdinals reports if any message in msgs has an ordinal plural value
+func UsesOrdinals(msgs *types.MessageBag) bool {
+	_, ordinals := pluralKinds(msgs)
+	return ordinals
+}
+
+// pluralKinds reports if any message in msgs has a cardinal or an ordinal plural value
+func pluralKinds(msgs *types.MessageBag) (cardinals bool, ordinals bool) {
+	for _, instance := range msgs.Instances() {
+		for _, lang := range instance.Languages().Get() {
+			types.VisitValues(instance.MessageMust(lang), func(value types.MessageValue) {
+				if plural, isPlural := value.(*types.ValuePlural); isPlural {
+					cardinals = cardinals || !plural.Ordinal
+					ordinals = ordinals || plural.Ordinal
+				}
+			})
+		}
+	}
+	return
+}
+
+func (w *GoCodeWriter) createMultilineableString(lang string, msg *types.MessageInstance, s types.Multilineable) string {
 	switch s.(type) {
 	case *types.ValueString:
 		return w.createValueValueString(s.(*types.ValueString))
 	case *types.ValueParametrized:
-		return w.createValueParametrizedValue(s.(*types.ValueParametrized))
+		return w.createValueParametrizedValue(lang, msg, s.(*types.ValueParametrized))
 	default:
 		panic(fmt.Errorf("unknown Multilineable type %+v", s))
 	}
@@ -248,24 +547,48 @@ func (w *GoCodeWriter) createValueValueString(s *types.ValueString) string {
 	return "\"" + s.AsValueString().Escaped("\"") + "\""
 }
 
-func (w *GoCodeWriter) createValueParametrizedValue(p *types.ValueParametrized) string {
+func (w *GoCodeWriter) createValueParametrizedValue(lang string, msg *types.MessageInstance, p *types.ValueParametrized) string {
 	messagePartSb := &strings.Builder{}
+	argList := make([]string, len(p.Args))
 	for i, arg := range p.Args {
-		messagePartSb.WriteString(p.TextSegments[i].Escaped("\""))
+		messagePartSb.WriteString(escapeFormat(p.TextSegments[i].Escaped("\"")))
 		messagePartSb.WriteString("%")
-		if arg.Format == "" {
-			messagePartSb.WriteString(p.Args[i].Argument.Type.DefaultFormat)
+		if arg.IsReference() {
+			messagePartSb.WriteString("s")
+			argList[i] = w.createReferenceCall(lang, arg.Reference.Target)
+			continue
+		}
+		// the argument of the instance has the type and format merged from all languages
+		declared, found := msg.Args().GetArgument(arg.Argument.Name)
+		assert.True(found, "used arguments are always added to the argument list")
+		format := arg.Format
+		if format == "" {
+			format = declared.DefaultFormat()
+		}
+		if declared.Type.Localized {
+			messagePartSb.WriteString("s")
+			argList[i] = w.createLocalizedArgument(lang, declared, format)
 		} else {
-			messagePartSb.WriteString(p.Args[i].Format)
+			messagePartSb.WriteString(format)
+			argList[i] = declared.Name
 		}
 	}
-	messagePartSb.WriteString(p.TextSegments[len(p.TextSegments)-1].Escaped("\""))
-	argListPart := strings.Join(
-		util.Map(p.Args, func(_ int, t **types.UsedArgument) string { return (*t).Argument.Name }),
-		", ",
-	)
+	messagePartSb.WriteString(escapeFormat(p.TextSegments[len(p.TextSegments)-1].Escaped("\"")))
 	messagePart := messagePartSb.String()
-	return fmt.Sprintf("fmt.Sprintf(\"%s\", %s)", messagePart, argListPart)
+	w.use("fmt")
+	return fmt.Sprintf("fmt.Sprintf(\"%s\", %s)", messagePart, strings.Join(argList, ", "))
+}
+
+// createReferenceCall calls the method of the referenced message on the struct of the same language, forwarding the
+// arguments it needs, which the referencing message also has
+func (w *GoCodeWriter) createReferenceCall(lang string, target *types.MessageInstance) string {
+	args := util.Map(target.Args().Args, func(_ int, t **types.MessageArgument) string { return (*t).Name })
+	return fmt.Sprintf("%s{}.%s(%s)", w.namer.InterfaceNameForLang(lang, target.Parent()), w.namer.FunctionName(target), strings.Join(args, ", "))
+}
+
+// escapeFormat escapes the % of a text used as format of fmt.Sprintf
+func escapeFormat(text string) string {
+	return strings.ReplaceAll(text, "%", "%%")
 }
 
 func (w *GoCodeWriter) w(str string, args ...any) {
@@ -274,6 +597,7 @@ func (w *GoCodeWriter) w(str string, args ...any) {
 	}
 	msg := fmt.Sprintf(str, args...)
 	w.sb.WriteString(msg)
+	w.lines += strings.Count(msg, "\n")
 	w.inNewLine = strings.HasSuffix(msg, "\n")
 }
 
@@ -282,6 +606,7 @@ func (w *GoCodeWriter) wl(str string) {
 		w.sb.WriteString(strings.Repeat(" ", w.indent))
 	}
 	w.sb.WriteString(str)
+	w.lines += strings.Count(str, "\n")
 	w.inNewLine = strings.HasSuffix(str, "\n")
 }
 
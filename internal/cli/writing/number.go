@@ -0,0 +1,168 @@
+package writing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/assert"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/cldr"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/types"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+// numberTypes are the argument types formatted with the number symbols of each language
+var numberTypes = []string{"number", "currency", "percent"}
+
+// UsesNumbers reports if any message in msgs has a number, currency or percent argument
+func UsesNumbers(msgs *types.MessageBag) bool {
+	return usesArgumentTypes(msgs, numberTypes...)
+}
+
+// usesArgumentTypes reports if any message in msgs has an argument of one of the types
+func usesArgumentTypes(msgs *types.MessageBag, names ...string) bool {
+	for _, instance := range msgs.Instances() {
+		for _, arg := range instance.Args().Args {
+			for _, name := range names {
+				if arg.Type.Name == name {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// createLocalizedArgument returns the expression that formats a localized argument in the language
+func (w *GoCodeWriter) createLocalizedArgument(lang string, arg *types.MessageArgument, format string) string {
+	symbols, _ := cldr.Numbers(lang)
+	switch arg.Type.Name {
+	case "number":
+		minFraction, maxFraction, err := cldr.ParseNumberFormat(format)
+		assert.NoError(err) // the formats are validated before generating the code
+		return fmt.Sprintf("formatNumber(%s, %s, %d, %d)", numberSymbolsName(symbols), arg.Name, minFraction, maxFraction)
+	case "percent":
+		fraction, err := cldr.ParsePercentFormat(format)
+		assert.NoError(err)
+		return fmt.Sprintf("formatPercent(%s, %s, %d)", numberSymbolsName(symbols), arg.Name, fraction)
+	case "currency":
+		code, fraction, err := cldr.ParseCurrencyFormat(format)
+		assert.NoError(err)
+		return fmt.Sprintf("formatCurrency(%s, %s, %q, %d)", numberSymbolsName(symbols), arg.Name, cldr.CurrencySymbol(lang, code), fraction)
+	case "date", "time", "datetime":
+		return w.createDateArgument(lang, arg, format)
+	case "duration", "reltime":
+		return w.createDurationArgument(lang, arg)
+	case "list":
+		return w.createListArgument(lang, arg, format)
+	default:
+		panic(fmt.Errorf("unknown localized argument type %s", arg.Type.Name))
+	}
+}
+
+func numberSymbolsName(symbols *cldr.NumberSymbols) string {
+	return "numberSymbols_" + strings.ReplaceAll(symbols.Id, "-", "_")
+}
+
+// WriteNumberFormats writes the number symbols of each language and the functions that format numbers with them
+func (w *GoCodeWriter) WriteNumberFormats() {
+	if !w.numbers {
+		return
+	}
+	w.use("math", "strconv", "strings")
+	w.w("\n// numberSymbols are the CLDR symbols used to format the numbers of a language\n")
+	w.w("type numberSymbols struct {\n")
+	w.w("    decimal, group, minus, nan string\n")
+	w.w("    // primaryGroup is the size of the group closest to the decimal separator and secondaryGroup the size of the rest\n")
+	w.w("    primaryGroup, secondaryGroup int\n")
+	w.w("    // minGrouping is the minimum amount of digits before the first group separator\n")
+	w.w("    minGrouping int\n")
+	w.w("    percentPrefix, percentSuffix string\n")
+	w.w("    // the currency affixes have a ¤ where the symbol of the currency goes\n")
+	w.w("    currencyPrefix, currencySuffix string\n")
+	w.w("}\n")
+
+	written := util.NewSet[string]()
+	for _, lang := range w.langs {
+		symbols, _ := cldr.Numbers(lang)
+		if written.Contains(symbols.Id) {
+			continue
+		}
+		written.Add(symbols.Id)
+		primary, secondary := symbols.Groups()
+		percentPrefix, percentSuffix := symbols.PercentAffixes()
+		currencyPrefix, currencySuffix := symbols.CurrencyAffixes()
+		w.w("\nvar %s = numberSymbols{\n", numberSymbolsName(symbols))
+		w.addIndent()
+		w.w("decimal: %q, group: %q, minus: %q, nan: %q,\n", symbols.Decimal, symbols.Group, symbols.Minus, symbols.NaN)
+		w.w("primaryGroup: %d, secondaryGroup: %d, minGrouping: %d,\n", primary, secondary, symbols.MinGrouping)
+		w.w("percentPrefix: %q, percentSuffix: %q,\n", percentPrefix, percentSuffix)
+		w.w("currencyPrefix: %q, currencySuffix: %q,\n", currencyPrefix, currencySuffix)
+		w.removeIndent()
+		w.w("}\n")
+	}
+
+	w.w("\n// formatNumber rounds x to maxFraction fraction digits, keeping at least minFraction, and writes it with the symbols\n")
+	w.w("func formatNumber(s numberSymbols, x float64, minFraction, maxFraction int) string {\n")
+	w.w("    return formatAffixedNumber(s, x, minFraction, maxFraction, \"\", \"\")\n")
+	w.w("}\n")
+
+	w.w("\n// formatPercent writes x, where 1 is 100%%, as a percentage with fraction fraction digits\n")
+	w.w("func formatPercent(s numberSymbols, x float64, fraction int) string {\n")
+	w.w("    return formatAffixedNumber(s, x*100, fraction, fraction, s.percentPrefix, s.percentSuffix)\n")
+	w.w("}\n")
+
+	w.w("\n// formatCurrency writes x as an amount of money with the symbol of its currency and fraction fraction digits\n")
+	w.w("func formatCurrency(s numberSymbols, x float64, symbol string, fraction int) string {\n")
+	w.w("    return formatAffixedNumber(s, x, fraction, fraction, strings.ReplaceAll(s.currencyPrefix, \"¤\", symbol), strings.ReplaceAll(s.currencySuffix, \"¤\", symbol))\n")
+	w.w("}\n")
+
+	w.w("\nfunc formatAffixedNumber(s numberSymbols, x float64, minFraction, maxFraction int, prefix, suffix string) string {\n")
+	w.addIndent()
+	w.w("if math.IsNaN(x) {\n")
+	w.w("    return prefix + s.nan + suffix\n")
+	w.w("}\n")
+	w.w("negative := x < 0\n")
+	w.w("if negative {\n")
+	w.w("    x = -x\n")
+	w.w("}\n")
+	w.w("if math.IsInf(x, 0) {\n")
+	w.w("    if negative {\n")
+	w.w("        return s.minus + prefix + %q + suffix\n", cldr.Infinity)
+	w.w("    }\n")
+	w.w("    return prefix + %q + suffix\n", cldr.Infinity)
+	w.w("}\n")
+	w.w("integer, fraction, _ := strings.Cut(strconv.FormatFloat(x, 'f', maxFraction, 64), \".\")\n")
+	w.w("for len(fraction) > minFraction && strings.HasSuffix(fraction, \"0\") {\n")
+	w.w("    fraction = fraction[:len(fraction)-1]\n")
+	w.w("}\n")
+	w.w("sb := strings.Builder{}\n")
+	w.w("if negative && strings.Trim(integer+fraction, \"0\") != \"\" {\n")
+	w.w("    sb.WriteString(s.minus)\n")
+	w.w("}\n")
+	w.w("sb.WriteString(prefix)\n")
+	w.w("if len(integer) < s.primaryGroup+s.minGrouping {\n")
+	w.w("    sb.WriteString(integer)\n")
+	w.w("} else {\n")
+	w.addIndent()
+	w.w("head := len(integer) - s.primaryGroup\n")
+	w.w("groups := []string{integer[head:]}\n")
+	w.w("for head > s.secondaryGroup {\n")
+	w.w("    groups = append(groups, integer[head-s.secondaryGroup:head])\n")
+	w.w("    head -= s.secondaryGroup\n")
+	w.w("}\n")
+	w.w("sb.WriteString(integer[:head])\n")
+	w.w("for i := len(groups) - 1; i >= 0; i-- {\n")
+	w.w("    sb.WriteString(s.group)\n")
+	w.w("    sb.WriteString(groups[i])\n")
+	w.w("}\n")
+	w.removeIndent()
+	w.w("}\n")
+	w.w("if fraction != \"\" {\n")
+	w.w("    sb.WriteString(s.decimal)\n")
+	w.w("    sb.WriteString(fraction)\n")
+	w.w("}\n")
+	w.w("sb.WriteString(suffix)\n")
+	w.w("return sb.String()\n")
+	w.removeIndent()
+	w.w("}\n")
+}
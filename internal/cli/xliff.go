@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/MrNemo64/go-n-i18n/internal/cli/exchange"
+	"github.com/MrNemo64/go-n-i18n/internal/cli/util"
+)
+
+type XliffArgs struct {
+	MessagesArgs
+	XliffDirectory string
+	Languages      []string // languages to export even if there are no messages for them yet
+}
+
+// ExportXliff writes a <lang>.xlf file for each language but the default one into the xliff directory
+func ExportXliff(args XliffArgs) {
+	log := newLogger(args.LogLevel)
+	messages, _ := args.load(log)
+
+	if err := os.MkdirAll(args.XliffDirectory, 0755); err != nil {
+		log.Error("Could not create the xliff directory", "err", err)
+		os.Exit(1)
+	}
+
+	langs := messages.Languages().Get()
+	for _, lang := range args.Languages {
+		if !slices.Contains(langs, lang) {
+			langs = append(langs, lang)
+		}
+	}
+	for _, lang := range langs {
+		if lang == args.DefaultLanguage {
+			continue
+		}
+		log.Info("Exporting language", "lang", lang)
+		name := filepath.Join(args.XliffDirectory, lang+".xlf")
+		file, err := os.Create(name)
+		if err != nil {
+			log.Error("Could not create xliff file", "file", name, "err", err)
+			os.Exit(1)
+		}
+		err = exchange.WriteXliff(file, args.DefaultLanguage, lang, exchange.XliffUnits(messages, lang, args.DefaultLanguage))
+		file.Close()
+		if err != nil {
+			log.Error("Could not write xliff file", "file", name, "err", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// ImportXliff merges the translations of the .xlf files of the xliff directory into the json files of each language.
+// Translations that drop or invent arguments are refused
+func ImportXliff(args XliffArgs) {
+	log := newLogger(args.LogLevel)
+	messages, files := args.load(log)
+
+	xliffFiles, err := filepath.Glob(filepath.Join(args.XliffDirectory, "*.xlf"))
+	if err != nil {
+		log.Error("Could not list the xliff files", "err", err)
+		os.Exit(1)
+	}
+
+	wc := util.NewWarningsCollector()
+	importer := exchange.NewImporter(messages, files, args.DefaultLanguage, wc)
+	for _, xliffFile := range xliffFiles {
+		file, err := os.Open(xliffFile)
+		if err != nil {
+			log.Error("Could not open xliff file", "file", xliffFile, "err", err)
+			os.Exit(1)
+		}
+		translations, lang, err := exchange.ReadXliff(file)
+		file.Close()
+		if err != nil {
+			log.Error("Could not read xliff file", "file", xliffFile, "err", err)
+			os.Exit(1)
+		}
+
+		if lang == "" {
+			lang = strings.TrimSuffix(filepath.Base(xliffFile), filepath.Ext(xliffFile))
+		}
+		if lang == args.DefaultLanguage {
+			log.Info("Skipping the xliff file of the default language", "file", xliffFile)
+			continue
+		}
+		log.Info("Importing language", "lang", lang, "file", xliffFile)
+
+		keys := make(map[string]string)
+		for _, unit := range exchange.XliffUnits(messages, lang, args.DefaultLanguage) {
+			keys[unit.Id] = unit.Key
+		}
+
+		var missing []string
+		for _, translation := range translations {
+			key, found := keys[translation.Id]
+			if !found {
+				key = translation.Name
+			}
+			if translation.Missing {
+				missing = append(missing, key)
+				continue
+			}
+			if dropped, invented := importer.CompareArguments(lang, key, translation.Text); len(dropped) > 0 || len(invented) > 0 {
+				log.Warn("Refusing translation that does not use the arguments of the message", "lang", lang, "unit", translation.Id,
+					"dropped-arguments", dropped, "unknown-arguments", invented)
+				continue
+			}
+			importer.Set(lang, key, translation.Text)
+		}
+		if len(missing) > 0 {
+			log.Warn("Missing translations", "lang", lang, "units", missing)
+		}
+	}
+	for _, warning := range wc.Warnings() {
+		log.Warn(warning.Error())
+	}
+
+	if err := importer.Save(); err != nil {
+		log.Error("Could not save the imported translations", "err", err)
+		os.Exit(1)
+	}
+}